@@ -1,7 +1,9 @@
 package ksyun
 
 import (
+	"encoding/binary"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 )
@@ -59,3 +61,67 @@ func getIpSegRange(userSegIp, offset uint8) (int, int) {
 	segMaxIp := userSegIp&(255<<offset) | ^(255 << offset)
 	return int(segMinIp), int(segMaxIp)
 }
+
+// getCidrSpan returns the first and the last address of the IPv4 cidr block and its mask length
+func getCidrSpan(cidr string) (first, last uint32, maskLen int, err error) {
+	_, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return first, last, maskLen, err
+	}
+	ip := ipNet.IP.To4()
+	if ip == nil {
+		return first, last, maskLen, fmt.Errorf("%s is not an IPv4 cidr block", cidr)
+	}
+	maskLen, _ = ipNet.Mask.Size()
+	first = binary.BigEndian.Uint32(ip)
+	last = first | ^(^uint32(0) << uint(32-maskLen))
+	if maskLen == 0 {
+		last = ^uint32(0)
+	}
+	return first, last, maskLen, err
+}
+
+// allocateCidrBlocks returns count blocks of maskLen inside the parent cidr block, which overlap neither the used
+// blocks nor each other, the lowest free blocks are taken.
+func allocateCidrBlocks(parent string, used []string, maskLen int, count int) (blocks []string, err error) {
+	first, last, parentMaskLen, err := getCidrSpan(parent)
+	if err != nil {
+		return blocks, err
+	}
+	if maskLen < parentMaskLen || maskLen > 32 {
+		return blocks, fmt.Errorf("the mask length %d must be between %d and 32 in %s", maskLen, parentMaskLen, parent)
+	}
+	var spans [][2]uint64
+	for _, cidr := range used {
+		usedFirst, usedLast, _, err := getCidrSpan(cidr)
+		if err != nil {
+			return blocks, err
+		}
+		spans = append(spans, [2]uint64{uint64(usedFirst), uint64(usedLast)})
+	}
+	size := uint64(1) << uint(32-maskLen)
+	for start := uint64(first); start+size-1 <= uint64(last) && len(blocks) < count; {
+		end := start + size - 1
+		next := start + size
+		free := true
+		for _, span := range spans {
+			if span[0] <= end && start <= span[1] {
+				// skip to the first aligned block after the overlapped one
+				next = (span[1]/size + 1) * size
+				free = false
+				break
+			}
+		}
+		if free {
+			ip := make(net.IP, 4)
+			binary.BigEndian.PutUint32(ip, uint32(start))
+			blocks = append(blocks, fmt.Sprintf("%s/%d", ip, maskLen))
+			spans = append(spans, [2]uint64{start, end})
+		}
+		start = next
+	}
+	if len(blocks) < count {
+		return blocks, fmt.Errorf("only %d free blocks with mask length %d are left in %s, %d are required", len(blocks), maskLen, parent, count)
+	}
+	return blocks, err
+}
@@ -0,0 +1,37 @@
+package ksyun
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAllocateCidrBlocks(t *testing.T) {
+	used := []string{"10.0.0.0/24", "10.0.2.0/23"}
+	blocks, err := allocateCidrBlocks("10.0.0.0/16", used, 24, 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if expected := []string{"10.0.1.0/24", "10.0.4.0/24", "10.0.5.0/24"}; !reflect.DeepEqual(blocks, expected) {
+		t.Errorf("Expected %v, got %v", expected, blocks)
+	}
+
+	// the larger block is aligned after the used ones
+	blocks, err = allocateCidrBlocks("10.0.0.0/16", used, 20, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if expected := []string{"10.0.16.0/20"}; !reflect.DeepEqual(blocks, expected) {
+		t.Errorf("Expected %v, got %v", expected, blocks)
+	}
+
+	_, err = allocateCidrBlocks("192.168.0.0/24", []string{"192.168.0.0/25"}, 26, 3)
+	if err == nil || !strings.Contains(err.Error(), "only 2 free blocks") {
+		t.Errorf("Expected an exhausted error, got %v", err)
+	}
+
+	_, err = allocateCidrBlocks("192.168.0.0/24", nil, 16, 1)
+	if err == nil {
+		t.Errorf("Expected an error for the block larger than the parent")
+	}
+}
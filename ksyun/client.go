@@ -69,7 +69,10 @@ type KsyunClient struct {
 	kmrconn        *kmr.Client            `json:"kmrconn,omitempty"`
 	klogconn       *klog.Client           `json:"klogconn,omitempty"`
 
-	config *Config
+	config       *Config
+	dryRunOnPlan bool
+	// the clients of other regions, they're created lazily by WithRegionClient
+	regionClients map[string]*KsyunClient
 }
 
 func (client *KsyunClient) GetVpcClient() *vpc.Vpc {
@@ -4,11 +4,12 @@ import (
 	"fmt"
 	klog "github.com/kingsoftcloud/sdk-go/v2/ksyun/client/klog/v20200731"
 	kmr "github.com/kingsoftcloud/sdk-go/v2/ksyun/client/kmr/v20210902"
-	"github.com/kingsoftcloud/sdk-go/v2/ksyun/common"
 	"github.com/kingsoftcloud/sdk-go/v2/ksyun/common/profile"
+	"log"
 	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"sync"
 	"time"
 
@@ -39,39 +40,88 @@ import (
 	"github.com/KscSDK/ksc-sdk-go/service/sks"
 	"github.com/KscSDK/ksc-sdk-go/service/slb"
 	"github.com/KscSDK/ksc-sdk-go/service/sqlserver"
+	"github.com/KscSDK/ksc-sdk-go/service/sts"
 	"github.com/KscSDK/ksc-sdk-go/service/tag"
 	"github.com/KscSDK/ksc-sdk-go/service/tagv2"
 	"github.com/KscSDK/ksc-sdk-go/service/vpc"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/ks3sdklib/ksyun-ks3-go-sdk/ks3"
+	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/credential"
 	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/network"
+	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/recorder"
+	"github.com/terraform-providers/terraform-provider-ksyun/logger"
 )
 
 // Config is the configuration of ksyun meta data
 type Config struct {
 	AccessKey     string
 	SecretKey     string
+	SecurityToken string
+	AssumeRole    *AssumeRoleConfig
 	Region        string
+
+	SharedCredentialsFile string
+	Profile               string
+
 	Insecure      bool
 	Domain        string
 	Endpoint      string
 	DryRun        bool
+	DryRunOnPlan  bool
 	IgnoreService bool
 	HttpKeepAlive bool
 	MaxRetries    int
+	RetryMinDelay time.Duration
+	RetryMaxDelay time.Duration
+	RateLimits    []RateLimitConfig
+	DefaultTags   map[string]interface{}
+	IgnoreTags    *IgnoreTagsConfig
 	HttpProxy     string
 	UseSSL        bool
+
+	// MaxThrottleRetries is the max retries of the throttled requests
+	MaxThrottleRetries int
+
+	// credentials are shared by all service clients
+	credentials *credentials.Credentials
+}
+
+// RateLimitConfig is the configuration of client-side rate limit of a service
+type RateLimitConfig struct {
+	Service string
+	Rate    float64
+	Burst   int
+}
+
+// IgnoreTagsConfig is the configuration of tags ignored by resources
+type IgnoreTagsConfig struct {
+	Keys        []string
+	KeyPrefixes []string
+}
+
+// AssumeRoleConfig is the configuration of assuming an iam role
+type AssumeRoleConfig struct {
+	RoleKrn     string
+	SessionName string
+	Duration    int
+	Policy      string
 }
 
 // Client will returns a client with connections for all product
 func (c *Config) Client() (*KsyunClient, error) {
 	var client KsyunClient
 	var err error
+	if err = c.loadSharedCredentials(); err != nil {
+		return nil, err
+	}
 	// init ksc client info
 	client.region = c.Region
 	cli := ksc.NewClient(c.AccessKey, c.SecretKey)
 
-	registerClient(cli, c)
+	if err = registerClient(cli, c); err != nil {
+		return nil, err
+	}
 	// 重试去掉
 	var MaxRetries = c.MaxRetries
 	cli.Config.MaxRetries = &MaxRetries
@@ -85,8 +135,12 @@ func (c *Config) Client() (*KsyunClient, error) {
 		CustomerDomain:              c.Domain,
 		CustomerDomainIgnoreService: c.IgnoreService,
 	}
+	if err = c.loadCredentials(cli, cfg, url); err != nil {
+		return nil, err
+	}
 
 	client.dryRun = c.DryRun
+	client.dryRunOnPlan = c.DryRunOnPlan
 	client.vpcconn = vpc.SdkNew(cli, cfg, url)
 	client.eipconn = eip.SdkNew(cli, cfg, url)
 	client.slbconn = slb.SdkNew(cli, cfg, url)
@@ -123,10 +177,89 @@ func (c *Config) Client() (*KsyunClient, error) {
 	return &client, nil
 }
 
+// loadSharedCredentials fills the credentials and region, which are neither set explicitly
+// nor by environment variables, from the profile of shared credentials file.
+func (c *Config) loadSharedCredentials() error {
+	if c.AccessKey != "" && c.SecretKey != "" && c.Region != "" {
+		return nil
+	}
+
+	profile, err := credential.LoadProfile(c.SharedCredentialsFile, c.Profile)
+	if err != nil {
+		// the default shared credentials file is optional
+		if c.SharedCredentialsFile == "" && c.Profile == "" {
+			log.Printf("[DEBUG] skip loading default shared credentials: %s", err)
+			return nil
+		}
+		return err
+	}
+
+	if c.AccessKey == "" && c.SecretKey == "" {
+		c.AccessKey = profile.AccessKey
+		c.SecretKey = profile.SecretKey
+		if c.SecurityToken == "" {
+			c.SecurityToken = profile.SecurityToken
+		}
+	}
+	if c.Region == "" {
+		c.Region = profile.Region
+	}
+	return nil
+}
+
+// metadataEndpoint overrides the endpoint of instance metadata service if not empty
+var metadataEndpoint string
+
+// loadCredentials sets up the credentials of session, the temporary credentials
+// of assumed role will be refreshed automatically before they expire.
+func (c *Config) loadCredentials(cli *session.Session, cfg *ksc.Config, urlInfo *utils.UrlInfo) error {
+	if c.AccessKey == "" && c.SecretKey == "" {
+		// fall back to the credentials of the role attached to kec instance, which are checked
+		// up front, otherwise every request fails with an obscure signing error
+		provider := credential.NewMetadataProvider()
+		if metadataEndpoint != "" {
+			provider.Endpoint = metadataEndpoint
+		}
+		cli.Config.Credentials = credentials.NewCredentials(provider)
+		if _, err := cli.Config.Credentials.Get(); err != nil {
+			return fmt.Errorf("no credentials found, set access_key and secret_key of the provider, "+
+				"the environment variables KSYUN_ACCESS_KEY and KSYUN_SECRET_KEY, or a profile of the shared credentials file; "+
+				"the credentials of the kec instance role are not available either: %s", err)
+		}
+	} else if c.AccessKey == "" || c.SecretKey == "" {
+		return fmt.Errorf("both access_key and secret_key must be set, only one of them is found")
+	} else if c.SecurityToken != "" {
+		cli.Config.Credentials = credentials.NewStaticCredentials(c.AccessKey, c.SecretKey, c.SecurityToken)
+	}
+
+	if c.AssumeRole != nil {
+		// sts client keeps signing requests with the original credentials
+		stsconn := sts.SdkNew(cli, cfg, urlInfo)
+		provider := credential.NewAssumeRoleProvider(stsconn, c.AssumeRole.RoleKrn)
+		if c.AssumeRole.SessionName != "" {
+			provider.SessionName = c.AssumeRole.SessionName
+		}
+		if c.AssumeRole.Duration > 0 {
+			provider.Duration = time.Duration(c.AssumeRole.Duration) * time.Second
+		}
+		provider.Policy = c.AssumeRole.Policy
+
+		cli.Config.Credentials = credentials.NewCredentials(provider)
+		if _, err := cli.Config.Credentials.Get(); err != nil {
+			return fmt.Errorf("error assuming role %s: %s", c.AssumeRole.RoleKrn, err)
+		}
+	}
+
+	c.credentials = cli.Config.Credentials
+	return nil
+}
+
 var goSdkMutex = sync.RWMutex{} // The Go SDK is not thread-safe
 var loadSdkfromRemoteMutex = sync.Mutex{}
 var loadSdkEndpointMutex = sync.Mutex{}
 var tagsMutex = sync.Mutex{}
+var regionClientsMutex = sync.Mutex{}
+var subnetCidrMutex = sync.Mutex{}
 
 func (client *KsyunClient) WithKs3BucketByName(bucketName string, do func(*ks3.Bucket) (interface{}, error)) (interface{}, error) {
 	return client.WithKs3Client(func(ks3Client *ks3.Client) (interface{}, error) {
@@ -142,7 +275,8 @@ func (client *KsyunClient) WithKs3Client(do func(*ks3.Client) (interface{}, erro
 	defer goSdkMutex.Unlock()
 	// Initialize the KS3 client if necessary
 	if client.ks3conn == nil {
-		ks3conn, err := ks3.New(client.config.Endpoint, client.config.AccessKey, client.config.SecretKey)
+		ks3conn, err := ks3.New(client.config.Endpoint, client.config.AccessKey, client.config.SecretKey,
+			ks3.SetCredentialsProvider(&credential.Ks3Provider{Credentials: client.config.credentials}))
 		if err != nil {
 			return nil, fmt.Errorf("unable to initialize the KS3 client: %#v", err)
 		}
@@ -151,13 +285,41 @@ func (client *KsyunClient) WithKs3Client(do func(*ks3.Client) (interface{}, erro
 	return do(client.ks3conn)
 }
 
-func registerClient(cli *session.Session, c *Config) {
+// WithRegionClient runs do with the client of region, which shares the configuration of provider. It's used by
+// the resources across regions, such as the copies of snapshots.
+func (client *KsyunClient) WithRegionClient(region string, do func(*KsyunClient) (interface{}, error)) (interface{}, error) {
+	if region == "" || region == client.region {
+		return do(client)
+	}
+	regionClientsMutex.Lock()
+	regionClient, ok := client.regionClients[region]
+	if !ok {
+		config := *client.config
+		config.Region = region
+		var err error
+		if regionClient, err = config.Client(); err != nil {
+			regionClientsMutex.Unlock()
+			return nil, fmt.Errorf("unable to initialize the client of region %s: %s", region, err)
+		}
+		if client.regionClients == nil {
+			client.regionClients = make(map[string]*KsyunClient)
+		}
+		client.regionClients[region] = regionClient
+	}
+	regionClientsMutex.Unlock()
+	return do(regionClient)
+}
+
+func registerClient(cli *session.Session, c *Config) error {
 
 	// register http client
-	httpClient := getKsyunClient(c)
+	httpClient, err := getKsyunClient(c)
+	if err != nil {
+		return err
+	}
 	cli.Config.WithHTTPClient(httpClient)
 
-	cli.Config.Retryer = network.GetKsyunRetryer(c.MaxRetries)
+	cli.Config.Retryer = network.GetKsyunRetryer(c.MaxRetries, c.MaxThrottleRetries, c.RetryMinDelay, c.RetryMaxDelay)
 
 	cli.Handlers.CompleteAttempt.PushBackNamed(network.NetErrorHandler)
 
@@ -165,9 +327,29 @@ func registerClient(cli *session.Session, c *Config) {
 	// cli.Handlers.CompleteAttempt.PushBackNamed(network.OutputResetError)
 
 	cli.Handlers.Sign.PushBackNamed(network.HandleRequestBody)
+
+	// wait for the rate limit before signing, so that the signature is fresh when it's sent
+	if len(c.RateLimits) > 0 {
+		limiter := network.NewRateLimiter()
+		for _, l := range c.RateLimits {
+			limiter.SetLimit(l.Service, l.Rate, l.Burst)
+		}
+		cli.Handlers.Sign.PushFrontNamed(limiter.Handler())
+	}
+
+	// trace every api call as a json line, enabled by KSYUN_API_TRACE
+	if path := os.Getenv(logger.TraceEnv); path != "" {
+		tracer, err := logger.OpenTracer(path)
+		if err != nil {
+			log.Printf("[WARN] api trace is disabled, %s", err)
+			return nil
+		}
+		cli.Handlers.Complete.PushBackNamed(tracer.Handler())
+	}
+	return nil
 }
 
-func getKsyunClient(c *Config) *http.Client {
+func getKsyunClient(c *Config) (*http.Client, error) {
 	tp := &http.Transport{
 		Proxy: func(r *http.Request) (*url.URL, error) {
 			if c.HttpProxy != "" {
@@ -195,7 +377,18 @@ func getKsyunClient(c *Config) *http.Client {
 		Timeout:   3 * time.Minute, // a completed request, includes tcp connect, received response, elapsed time.
 		Transport: tp,
 	}
-	return httpClient
+
+	// record or replay the api calls as fixtures, enabled by KSYUN_FIXTURE_MODE.
+	// the ks3, klog and kmr clients don't use this http client, so their calls are not recorded
+	if mode := recorder.ModeFromEnv(); mode != recorder.ModeDisabled {
+		rt, err := recorder.NewTransport(mode, os.Getenv(recorder.CassetteEnv), tp)
+		if err != nil {
+			return nil, err
+		}
+		rt.Secrets = []string{c.AccessKey, c.SecretKey, c.SecurityToken}
+		httpClient.Transport = rt
+	}
+	return httpClient, nil
 }
 
 func klogSdkNew(c *Config) (*klog.Client, error) {
@@ -203,7 +396,13 @@ func klogSdkNew(c *Config) (*klog.Client, error) {
 	cpf.HttpProfile.ReqMethod = "POST"
 	cpf.HttpProfile.ReqTimeout = 20
 	cpf.HttpProfile.Endpoint = c.Endpoint
-	return klog.NewClient(common.NewCredential(c.AccessKey, c.SecretKey), c.Region, cpf)
+	conn, err := klog.NewClient(&credential.CommonCredential{Credentials: c.credentials}, c.Region, cpf)
+	if err != nil {
+		return nil, err
+	}
+	// the signer of sdk-go drops the session token of temporary credentials
+	conn.WithHttpTransport(&credential.TokenTransport{Credentials: c.credentials})
+	return conn, nil
 }
 
 func (client *KsyunClient) WithKmrClient(do func(*kmr.Client) (interface{}, error)) (interface{}, error) {
@@ -211,13 +410,14 @@ func (client *KsyunClient) WithKmrClient(do func(*kmr.Client) (interface{}, erro
 	defer goSdkMutex.Unlock()
 	// Initialize the KMR client if necessary
 	if client.kmrconn == nil {
-		credential := common.NewCredential(client.config.AccessKey, client.config.SecretKey)
+		cred := &credential.CommonCredential{Credentials: client.config.credentials}
 		cpf := profile.NewClientProfile()
 		cpf.HttpProfile.Endpoint = client.config.Endpoint
-		kmrconn, err := kmr.NewClient(credential, client.config.Region, cpf)
+		kmrconn, err := kmr.NewClient(cred, client.config.Region, cpf)
 		if err != nil {
 			return nil, fmt.Errorf("unable to initialize the KMR client: %#v", err)
 		}
+		kmrconn.WithHttpTransport(&credential.TokenTransport{Credentials: client.config.credentials})
 		client.kmrconn = kmrconn
 	}
 	return do(client.kmrconn)
@@ -0,0 +1,35 @@
+package ksyun
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/KscSDK/ksc-sdk-go/ksc"
+)
+
+func TestLoadCredentialsNotFound(t *testing.T) {
+	// an instance without role attached
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	metadataEndpoint = server.URL
+	defer func() { metadataEndpoint = "" }()
+
+	c := &Config{}
+	err := c.loadCredentials(ksc.NewClient("", ""), nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "no credentials found") {
+		t.Errorf("Expected a no credentials error, got %v", err)
+	}
+
+	c = &Config{AccessKey: "ak"}
+	err = c.loadCredentials(ksc.NewClient(c.AccessKey, ""), nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "both access_key and secret_key must be set") {
+		t.Errorf("Expected a missing secret_key error, got %v", err)
+	}
+
+	c = &Config{AccessKey: "ak", SecretKey: "sk"}
+	if err = c.loadCredentials(ksc.NewClient(c.AccessKey, c.SecretKey), nil, nil); err != nil {
+		t.Errorf("Expected no error, got %s", err)
+	}
+}
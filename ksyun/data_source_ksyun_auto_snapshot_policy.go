@@ -110,6 +110,11 @@ func dataSourceKsyunAutoSnapshotPolicy() *schema.Resource {
 							Description: "The volume number that is attached to this policy.",
 						},
 						"attach_ebs_volume_num": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The EBS volume number that is attached to this policy.",
+						},
+						"retention_time": {
 							Type:        schema.TypeInt,
 							Computed:    true,
 							Description: "The snapshot retention period (unit: day).",
@@ -0,0 +1,125 @@
+/*
+This data source provides a list of the routes learned by the cen from its network instances.
+
+# Example Usage
+
+```hcl
+
+	data "ksyun_cen_route_entries" "default" {
+	  output_file = "output_result"
+	  cen_id      = ksyun_cen.default.id
+	}
+
+```
+*/
+package ksyun
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceKsyunCenRouteEntries() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceKsyunCenRouteEntriesRead,
+		Schema: map[string]*schema.Schema{
+			"cen_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The ID of the cen.",
+			},
+			"ids": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set:         schema.HashString,
+				Description: "A list of cen route IDs.",
+			},
+			"network_instance_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The ID of the network instance which the routes are learned from.",
+			},
+			"output_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "File name where to save data source results (after running `terraform plan`).",
+			},
+			"total_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Total number of cen routes that satisfy the condition.",
+			},
+			"cen_route_entries": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "An information list of cen routes. Each element contains the following attributes:",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the cen route.",
+						},
+						"cen_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the cen.",
+						},
+						"destination_cidr_block": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The destination CIDR block of the route.",
+						},
+						"network_instance_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the network instance which the route is learned from.",
+						},
+						"instance_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the network instance.",
+						},
+						"instance_region": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The region of the network instance.",
+						},
+						"instance_account_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The account ID of the network instance.",
+						},
+						"instance_route_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the route in the network instance.",
+						},
+						"network_route_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the route in the network instance.",
+						},
+						"self_route_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the self-defined route.",
+						},
+						"create_time": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The time of creation.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceKsyunCenRouteEntriesRead(d *schema.ResourceData, meta interface{}) error {
+	cenService := CenService{meta.(*KsyunClient)}
+	return cenService.ReadAndSetCenRouteEntries(d, dataSourceKsyunCenRouteEntries())
+}
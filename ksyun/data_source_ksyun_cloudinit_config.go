@@ -0,0 +1,199 @@
+/*
+This data source renders a multi-part MIME cloud-init payload for the `user_data` of instances, the payload is
+gzip-compressed and base64-encoded by default. It's rendered locally without calling any api.
+
+# Example Usage
+
+```hcl
+
+	data "ksyun_cloudinit_config" "default" {
+	  part {
+	    filename     = "init.sh"
+	    content_type = "text/x-shellscript"
+	    content      = "#!/bin/bash\necho hello > /tmp/hello"
+	  }
+
+	  part {
+	    content_type = "text/cloud-config"
+	    content      = <<-EOT
+	      write_files:
+	        - path: /etc/motd
+	          content: managed by terraform
+	    EOT
+	  }
+	}
+
+	resource "ksyun_instance" "default" {
+	  user_data = data.ksyun_cloudinit_config.default.rendered
+	  # ...
+	}
+
+```
+*/
+package ksyun
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"hash/crc32"
+	"mime/multipart"
+	"net/textproto"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// kecUserDataMaxSize is the max size of the user data of instances
+const kecUserDataMaxSize = 16 * 1024
+
+func dataSourceKsyunCloudinitConfig() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceKsyunCloudinitConfigRead,
+		Schema: map[string]*schema.Schema{
+			"part": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Description: "The parts of the payload, they're processed by cloud-init in order.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"content_type": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "text/plain",
+							Description: "The MIME type of the part, such as `text/x-shellscript` for scripts and `text/cloud-config` for cloud-config YAML. Default is `text/plain`.",
+						},
+						"content": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The content of the part.",
+						},
+						"filename": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The filename of the part, it's used by cloud-init to name the scripts.",
+						},
+						"merge_type": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "The merge type of the cloud-config part, such as `list(append)+dict(recurse_array)+str()`.",
+						},
+					},
+				},
+			},
+			"gzip": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether to compress the payload with gzip. Default is `true`.",
+			},
+			"base64_encode": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether to encode the payload in base64, the `user_data` of instance must be encoded. Default is `true`.",
+			},
+			"boundary": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "MIMEBOUNDARY",
+				ValidateFunc: validation.StringLenBetween(1, 70),
+				Description:  "The boundary of the MIME parts. Default is `MIMEBOUNDARY`.",
+			},
+			"output_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "File name where to save data source results (after running `terraform plan`).",
+			},
+			"rendered": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The rendered payload.",
+			},
+			"size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The size of the rendered payload in bytes, it's limited in 16 KB.",
+			},
+		},
+	}
+}
+
+func dataSourceKsyunCloudinitConfigRead(d *schema.ResourceData, meta interface{}) error {
+	rendered, err := renderCloudinitConfig(d)
+	if err != nil {
+		return err
+	}
+	if len(rendered) > kecUserDataMaxSize {
+		return fmt.Errorf("the rendered cloud-init payload is %d bytes, which exceeds the user data limit of %d bytes", len(rendered), kecUserDataMaxSize)
+	}
+	d.SetId(strconv.Itoa(int(crc32.ChecksumIEEE([]byte(rendered)))))
+	_ = d.Set("size", len(rendered))
+	if err = d.Set("rendered", rendered); err != nil {
+		return err
+	}
+	if outputFile, ok := d.GetOk("output_file"); ok && outputFile.(string) != "" {
+		return writeToFile(outputFile.(string), rendered)
+	}
+	return nil
+}
+
+func renderCloudinitConfig(d *schema.ResourceData) (string, error) {
+	gzipped := d.Get("gzip").(bool)
+	base64Encode := d.Get("base64_encode").(bool)
+	if gzipped && !base64Encode {
+		return "", fmt.Errorf("base64_encode is required when gzip is true, the compressed payload is binary")
+	}
+
+	var buf bytes.Buffer
+	mimeWriter := multipart.NewWriter(&buf)
+	if err := mimeWriter.SetBoundary(d.Get("boundary").(string)); err != nil {
+		return "", err
+	}
+	buf.WriteString(fmt.Sprintf("Content-Type: multipart/mixed; boundary=\"%s\"\r\n", mimeWriter.Boundary()))
+	buf.WriteString("MIME-Version: 1.0\r\n\r\n")
+
+	for _, v := range d.Get("part").([]interface{}) {
+		part := v.(map[string]interface{})
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", part["content_type"].(string))
+		header.Set("MIME-Version", "1.0")
+		header.Set("Content-Transfer-Encoding", "7bit")
+		if filename := part["filename"].(string); filename != "" {
+			header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+		}
+		if mergeType := part["merge_type"].(string); mergeType != "" {
+			header.Set("X-Merge-Type", mergeType)
+		}
+		w, err := mimeWriter.CreatePart(header)
+		if err != nil {
+			return "", err
+		}
+		if _, err = w.Write([]byte(part["content"].(string))); err != nil {
+			return "", err
+		}
+	}
+	if err := mimeWriter.Close(); err != nil {
+		return "", err
+	}
+
+	payload := buf.Bytes()
+	if gzipped {
+		var gzipBuf bytes.Buffer
+		gzipWriter := gzip.NewWriter(&gzipBuf)
+		if _, err := gzipWriter.Write(payload); err != nil {
+			return "", err
+		}
+		if err := gzipWriter.Close(); err != nil {
+			return "", err
+		}
+		payload = gzipBuf.Bytes()
+	}
+	if base64Encode {
+		return base64.StdEncoding.EncodeToString(payload), nil
+	}
+	return string(payload), nil
+}
@@ -0,0 +1,109 @@
+package ksyun
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestKsyunCloudinitConfigDataSource_render(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, dataSourceKsyunCloudinitConfig().Schema, map[string]interface{}{
+		"part": []interface{}{
+			map[string]interface{}{
+				"filename":     "init.sh",
+				"content_type": "text/x-shellscript",
+				"content":      "#!/bin/bash\necho hello",
+			},
+			map[string]interface{}{
+				"content_type": "text/cloud-config",
+				"content":      "packages:\n  - nginx\n",
+				"merge_type":   "list(append)+dict(recurse_array)+str()",
+			},
+		},
+	})
+	if err := dataSourceKsyunCloudinitConfigRead(d, nil); err != nil {
+		t.Fatal(err)
+	}
+	rendered := d.Get("rendered").(string)
+	if d.Get("size").(int) != len(rendered) {
+		t.Fatalf("size %d != %d", d.Get("size").(int), len(rendered))
+	}
+
+	compressed, err := base64.StdEncoding.DecodeString(rendered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/mixed" || params["boundary"] != "MIMEBOUNDARY" {
+		t.Fatalf("unexpected content type %q, %v", msg.Header.Get("Content-Type"), err)
+	}
+
+	mimeReader := multipart.NewReader(msg.Body, params["boundary"])
+	var parts []*multipart.Part
+	var contents []string
+	for {
+		part, err := mimeReader.NextPart()
+		if err != nil {
+			break
+		}
+		content, _ := ioutil.ReadAll(part)
+		parts = append(parts, part)
+		contents = append(contents, string(content))
+	}
+	if len(parts) != 2 {
+		t.Fatalf("expect 2 parts, got %d", len(parts))
+	}
+	if parts[0].FileName() != "init.sh" || parts[0].Header.Get("Content-Type") != "text/x-shellscript" || contents[0] != "#!/bin/bash\necho hello" {
+		t.Fatalf("unexpected script part %v %q", parts[0].Header, contents[0])
+	}
+	if parts[1].Header.Get("X-Merge-Type") != "list(append)+dict(recurse_array)+str()" || !strings.Contains(contents[1], "nginx") {
+		t.Fatalf("unexpected cloud-config part %v %q", parts[1].Header, contents[1])
+	}
+}
+
+func TestKsyunCloudinitConfigDataSource_limit(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, dataSourceKsyunCloudinitConfig().Schema, map[string]interface{}{
+		"gzip": false,
+		"part": []interface{}{
+			map[string]interface{}{
+				"content": strings.Repeat("x", kecUserDataMaxSize),
+			},
+		},
+	})
+	err := dataSourceKsyunCloudinitConfigRead(d, nil)
+	if err == nil || !strings.Contains(err.Error(), "exceeds the user data limit") {
+		t.Fatalf("expect the limit error, got %v", err)
+	}
+
+	d = schema.TestResourceDataRaw(t, dataSourceKsyunCloudinitConfig().Schema, map[string]interface{}{
+		"base64_encode": false,
+		"part": []interface{}{
+			map[string]interface{}{
+				"content": "#cloud-config",
+			},
+		},
+	})
+	if err = dataSourceKsyunCloudinitConfigRead(d, nil); err == nil {
+		t.Fatal("expect the error of gzip without base64")
+	}
+}
@@ -0,0 +1,232 @@
+/*
+This data source provides a list of KEC instance types available in the current region, the types are
+sorted by cpu, memory and gpu in ascending order, so the first one is the smallest type meeting the filters.
+If `charge_type` is set, the prices of the types are queried and the types are sorted by price instead, so the first
+one is the cheapest type meeting the filters.
+
+# Example Usage
+
+```hcl
+
+	data "ksyun_instance_types" "default" {
+	  availability_zone = "cn-beijing-6a"
+	  instance_family   = "N3"
+	  min_cpu           = 2
+	  min_memory        = 4
+	  output_file       = "output_result"
+	}
+
+	output "smallest_instance_type" {
+	  value = data.ksyun_instance_types.default.instance_types.0.instance_type
+	}
+
+	data "ksyun_instance_types" "cheapest" {
+	  availability_zone = "cn-beijing-6a"
+	  min_cpu           = 2
+	  min_memory        = 4
+	  charge_type       = "HourlyInstantSettlement"
+	  max_price         = 1.5
+	}
+
+	output "cheapest_instance_type" {
+	  value = data.ksyun_instance_types.cheapest.instance_types.0.instance_type
+	}
+
+```
+*/
+package ksyun
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func dataSourceKsyunInstanceTypes() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceKsyunInstanceTypesRead,
+		Schema: map[string]*schema.Schema{
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "A regex string to filter results by instance type.",
+			},
+			"availability_zone": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The availability zone which the instance types are available in.",
+			},
+			"instance_family": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The family of the instance types, such as `N3`.",
+			},
+			"cpu": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The number of cpu cores of the instance types.",
+			},
+			"memory": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The memory size of the instance types, unit is GB.",
+			},
+			"gpu": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The number of gpu of the instance types.",
+			},
+			"min_cpu": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The minimum number of cpu cores of the instance types.",
+			},
+			"min_memory": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The minimum memory size of the instance types, unit is GB.",
+			},
+			"min_gpu": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The minimum number of gpu of the instance types.",
+			},
+			"charge_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"Daily",
+					"HourlyInstantSettlement",
+				}, false),
+				Description: "The charge type to query the prices of the instance types by. Valid Values: 'Daily', 'HourlyInstantSettlement'. " +
+					"If set, the price of each type meeting the other filters is queried by one api call, and the types are sorted by price in ascending order.",
+			},
+			"max_price": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				ValidateFunc: validation.FloatAtLeast(0),
+				Description:  "The maximum price of the instance types, it must be used with `charge_type`.",
+			},
+			"output_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "File name where to save data source results (after running `terraform plan`).",
+			},
+			"total_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Total number of instance types that satisfy the condition.",
+			},
+			"instance_types": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "It is a nested type which documented below.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"instance_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The instance type.",
+						},
+						"instance_family": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The family of the instance type.",
+						},
+						"instance_family_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The display name of the instance family.",
+						},
+						"cpu": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The number of cpu cores.",
+						},
+						"memory": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The memory size, unit is GB.",
+						},
+						"gpu": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The number of gpu.",
+						},
+						"gpu_spec": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The specification of gpu.",
+						},
+						"network_interface_count": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The max number of network interfaces.",
+						},
+						"private_ip_count": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The max number of private ips of a network interface.",
+						},
+						"price": {
+							Type:        schema.TypeFloat,
+							Computed:    true,
+							Description: "The trade price of an instance of the type, 0 if `charge_type` is not set.",
+						},
+						"price_unit": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The unit of the price, empty if `charge_type` is not set.",
+						},
+						"availability_zones": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The availability zones which the instance type is available in.",
+						},
+						"system_disk_types": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The types of system disk supported.",
+						},
+						"data_disk_quotas": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The quotas of data disks supported.",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"disk_type": {
+										Type:        schema.TypeString,
+										Computed:    true,
+										Description: "The type of data disk.",
+									},
+									"min_size": {
+										Type:        schema.TypeInt,
+										Computed:    true,
+										Description: "The minimum size of data disk, unit is GB.",
+									},
+									"max_size": {
+										Type:        schema.TypeInt,
+										Computed:    true,
+										Description: "The maximum size of data disk, unit is GB.",
+									},
+									"count": {
+										Type:        schema.TypeInt,
+										Computed:    true,
+										Description: "The max number of data disks.",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceKsyunInstanceTypesRead(d *schema.ResourceData, meta interface{}) error {
+	kecService := KecService{meta.(*KsyunClient)}
+	return kecService.ReadAndSetInstanceTypes(d, dataSourceKsyunInstanceTypes())
+}
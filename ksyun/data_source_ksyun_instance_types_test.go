@@ -0,0 +1,83 @@
+package ksyun
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/mockapi"
+	"testing"
+)
+
+func TestAccKsyunInstanceTypesDataSource_basic(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataInstanceTypesConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckIDExists("data.ksyun_instance_types.foo"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataInstanceTypesConfig = `
+data "ksyun_instance_types" "foo" {
+  output_file="output_result"
+  availability_zone="cn-beijing-6a"
+  min_cpu=2
+  min_memory=4
+}`
+
+func TestMockKsyunInstanceTypesDataSource(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testMockProviderConfig(s) + `
+data "ksyun_instance_types" "all" {}
+
+data "ksyun_instance_types" "foo" {
+  availability_zone = "cn-beijing-6a"
+  instance_family   = "N3"
+  min_cpu           = 2
+}
+
+data "ksyun_instance_types" "gpu" {
+  min_gpu = 1
+}
+
+data "ksyun_instance_types" "cheap" {
+  availability_zone = "cn-beijing-6a"
+  min_cpu           = 2
+  charge_type       = "HourlyInstantSettlement"
+  max_price         = 1
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ksyun_instance_types.all", "total_count", "5"),
+					resource.TestCheckResourceAttr("data.ksyun_instance_types.all", "instance_types.0.instance_type", "N3.1A"),
+					resource.TestCheckResourceAttr("data.ksyun_instance_types.foo", "total_count", "2"),
+					resource.TestCheckResourceAttr("data.ksyun_instance_types.foo", "instance_types.0.instance_type", "N3.2B"),
+					resource.TestCheckResourceAttr("data.ksyun_instance_types.foo", "instance_types.0.memory", "4"),
+					resource.TestCheckResourceAttr("data.ksyun_instance_types.foo", "instance_types.0.availability_zones.#", "2"),
+					resource.TestCheckResourceAttr("data.ksyun_instance_types.foo", "instance_types.0.data_disk_quotas.0.max_size", "16000"),
+					resource.TestCheckResourceAttr("data.ksyun_instance_types.gpu", "total_count", "1"),
+					resource.TestCheckResourceAttr("data.ksyun_instance_types.gpu", "instance_types.0.gpu_spec", "NVIDIA T4"),
+					resource.TestCheckResourceAttr("data.ksyun_instance_types.gpu", "instance_types.0.price", "0"),
+					// the cheapest comes first, the gpu type is beyond max_price
+					resource.TestCheckResourceAttr("data.ksyun_instance_types.cheap", "total_count", "3"),
+					resource.TestCheckResourceAttr("data.ksyun_instance_types.cheap", "instance_types.0.instance_type", "S6.2B"),
+					resource.TestCheckResourceAttr("data.ksyun_instance_types.cheap", "instance_types.0.price", "0.35"),
+					resource.TestCheckResourceAttr("data.ksyun_instance_types.cheap", "instance_types.0.price_unit", "hour"),
+					resource.TestCheckResourceAttr("data.ksyun_instance_types.cheap", "instance_types.2.instance_type", "N3.4B"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,134 @@
+/*
+This data source plans the cidr blocks of new subnets in a VPC. The lowest free blocks of the requested mask lengths are
+returned, which overlap neither the existing subnets of the VPC nor each other.
+
+~> **NOTE:** The blocks are planned on every read, the subnets created from them are taken as existing ones by the next
+read, so the results change after apply. To allocate the block of a subnet once on creation, use `cidr_mask_length`
+of `ksyun_subnet` instead.
+
+# Example Usage
+
+```hcl
+
+	data "ksyun_vpc_cidr_plan" "default" {
+	  vpc_id = "a8979fe2-cf1a-47b9-80f6-57445227c541"
+	  requests {
+	    availability_zone = "cn-beijing-6a"
+	    cidr_mask_length  = 24
+	    count             = 2
+	  }
+	  requests {
+	    availability_zone = "cn-beijing-6b"
+	    cidr_mask_length  = 26
+	  }
+	  output_file = "output_result"
+	}
+
+```
+*/
+package ksyun
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func dataSourceKsyunVpcCidrPlan() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceKsyunVpcCidrPlanRead,
+		Schema: map[string]*schema.Schema{
+			"vpc_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The id of the vpc.",
+			},
+			"reserved_cidr_blocks": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateCIDRNetworkAddress,
+				},
+				Description: "The cidr blocks which are not planned besides the existing subnets.",
+			},
+			"requests": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"availability_zone": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The name of the availability zone.",
+						},
+						"cidr_mask_length": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntBetween(8, 29),
+							Description:  "The mask length of the cidr blocks.",
+						},
+						"count": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      1,
+							ValidateFunc: validation.IntAtLeast(1),
+							Description:  "The count of the cidr blocks. Default is 1.",
+						},
+					},
+				},
+				Description: "The requested cidr blocks, which are planned in order.",
+			},
+			"output_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "File name where to save data source results (after running `terraform plan`).",
+			},
+			"vpc_cidr_block": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The cidr block of the vpc.",
+			},
+			"used_cidr_blocks": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The cidr blocks of the existing subnets and the reserved ones.",
+			},
+			"plans": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"availability_zone": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the availability zone.",
+						},
+						"cidr_mask_length": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The mask length of the cidr blocks.",
+						},
+						"cidr_blocks": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+							Description: "The planned cidr blocks.",
+						},
+					},
+				},
+				Description: "The planned cidr blocks in the order of requests.",
+			},
+		},
+	}
+}
+
+func dataSourceKsyunVpcCidrPlanRead(d *schema.ResourceData, meta interface{}) error {
+	vpcService := VpcService{meta.(*KsyunClient)}
+	return vpcService.ReadAndSetVpcCidrPlan(d, dataSourceKsyunVpcCidrPlan())
+}
@@ -0,0 +1,154 @@
+/*
+This data source provides a list of VPC peering connection resources according to their ID and the VPC they belong to.
+
+# Example Usage
+
+```hcl
+
+	data "ksyun_vpc_peering_connections" "default" {
+	  output_file = "output_result"
+	  ids         = []
+	  vpc_ids     = []
+	}
+
+```
+*/
+package ksyun
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func dataSourceKsyunVpcPeeringConnections() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceKsyunVpcPeeringConnectionsRead,
+		Schema: map[string]*schema.Schema{
+			"ids": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set:         schema.HashString,
+				Description: "A list of peering connection IDs, all the peering connections belong to this region will be retrieved if the ID is `\"\"`.",
+			},
+
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "A regex string to filter results by peering connection name.",
+			},
+
+			"output_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "File name where to save data source results (after running `terraform plan`).",
+			},
+
+			"total_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Total number of peering connections that satisfy the condition.",
+			},
+
+			"vpc_ids": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set:         schema.HashString,
+				Description: "A list of VPC id that the desired peering connection belongs to.",
+			},
+
+			"project_ids": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set:         schema.HashString,
+				Description: "A list of Project id that the desired peering connection belongs to.",
+			},
+
+			"vpc_peering_connections": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "An information list of peering connections. Each element contains the following attributes:",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the peering connection.",
+						},
+						"peering_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the peering connection.",
+						},
+						"vpc_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the requester VPC.",
+						},
+						"peer_vpc_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the peer VPC.",
+						},
+						"peer_region": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The region of the peer VPC.",
+						},
+						"peer_account_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The account ID of the peer VPC.",
+						},
+						"band_width": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The bandwidth of the cross-region peering connection, unit is 'Mbps'.",
+						},
+						"state": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The state of the peering connection.",
+						},
+						"vpc_peering_connection_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the peering connection.",
+						},
+						"charge_type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The charge type of the peering connection.",
+						},
+						"project_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the project.",
+						},
+						"create_time": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The time of creation.",
+						},
+						"requester_vpc_info": vpcPeeringConnectionVpcInfoSchema("The information of the requester VPC."),
+						"accepter_vpc_info":  vpcPeeringConnectionVpcInfoSchema("The information of the accepter VPC."),
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceKsyunVpcPeeringConnectionsRead(d *schema.ResourceData, meta interface{}) error {
+	vpcService := VpcService{meta.(*KsyunClient)}
+	return vpcService.ReadAndSetVpcPeeringConnections(d, dataSourceKsyunVpcPeeringConnections())
+}
@@ -0,0 +1,121 @@
+package credential
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	v4 "github.com/aws/aws-sdk-go/aws/signer/v4"
+	"github.com/kingsoftcloud/sdk-go/v2/ksyun/common"
+	"github.com/ks3sdklib/ksyun-ks3-go-sdk/ks3"
+)
+
+// Ks3Provider adapts the shared credentials to ks3 client,
+// so that ks3 requests are signed with the refreshed credentials.
+type Ks3Provider struct {
+	Credentials *credentials.Credentials
+}
+
+var _ ks3.CredentialsProvider = (*Ks3Provider)(nil)
+
+func (p *Ks3Provider) GetCredentials() ks3.Credentials {
+	return &ks3Credentials{value: getValue(p.Credentials)}
+}
+
+type ks3Credentials struct {
+	value credentials.Value
+}
+
+func (c *ks3Credentials) GetAccessKeyID() string {
+	return c.value.AccessKeyID
+}
+
+func (c *ks3Credentials) GetAccessKeySecret() string {
+	return c.value.SecretAccessKey
+}
+
+func (c *ks3Credentials) GetSecurityToken() string {
+	return c.value.SessionToken
+}
+
+// CommonCredential adapts the shared credentials to the clients built by kingsoftcloud sdk-go
+type CommonCredential struct {
+	Credentials *credentials.Credentials
+}
+
+var _ common.Credentials = (*CommonCredential)(nil)
+
+func (c *CommonCredential) GetSecretId() string {
+	return getValue(c.Credentials).AccessKeyID
+}
+
+func (c *CommonCredential) GetSecretKey() string {
+	return getValue(c.Credentials).SecretAccessKey
+}
+
+// TokenTransport signs the requests of the clients built by kingsoftcloud sdk-go again with the
+// session token of temporary credentials, which is dropped by the signer of sdk-go.
+type TokenTransport struct {
+	Credentials *credentials.Credentials
+	// Transport sends the signed requests, http.DefaultTransport is used if nil
+	Transport http.RoundTripper
+}
+
+var _ http.RoundTripper = (*TokenTransport)(nil)
+
+func (t *TokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	value := getValue(t.Credentials)
+	region, service, ok := signingScope(req.Header.Get("Authorization"))
+	if value.SessionToken == "" || !ok {
+		return transport.RoundTrip(req)
+	}
+
+	var body io.ReadSeeker
+	if req.Body != nil {
+		b, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(b)
+	}
+	signed := req.Clone(req.Context())
+	signed.Header.Del("Authorization")
+	signer := v4.NewSigner(credentials.NewStaticCredentialsFromCreds(value))
+	if _, err := signer.Sign(signed, body, service, region, time.Now()); err != nil {
+		return nil, err
+	}
+	return transport.RoundTrip(signed)
+}
+
+// signingScope returns the region and service of the credential scope of a v4 Authorization header,
+// e.g. "AWS4-HMAC-SHA256 Credential=ak/20060102/cn-beijing-6/klog/aws4_request, ..."
+func signingScope(authorization string) (region, service string, ok bool) {
+	i := strings.Index(authorization, "Credential=")
+	if i < 0 {
+		return "", "", false
+	}
+	scope := strings.SplitN(authorization[i+len("Credential="):], ",", 2)[0]
+	parts := strings.Split(scope, "/")
+	if len(parts) != 5 {
+		return "", "", false
+	}
+	return parts[2], parts[3], true
+}
+
+func getValue(creds *credentials.Credentials) credentials.Value {
+	value, err := creds.Get()
+	if err != nil {
+		log.Printf("[WARN] get credentials failed: %s", err)
+	}
+	return value
+}
@@ -0,0 +1,58 @@
+package credential
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	klog "github.com/kingsoftcloud/sdk-go/v2/ksyun/client/klog/v20200731"
+	"github.com/kingsoftcloud/sdk-go/v2/ksyun/common/profile"
+)
+
+func TestTokenTransport(t *testing.T) {
+	var header http.Header
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		b, _ := ioutil.ReadAll(r.Body)
+		body = string(b)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"RequestId":"req","Projects":[]}`))
+	}))
+	defer srv.Close()
+
+	creds := credentials.NewStaticCredentials("ak", "sk", "token")
+	cpf := profile.NewClientProfile()
+	cpf.HttpProfile.Endpoint = strings.TrimPrefix(srv.URL, "http://")
+	conn, err := klog.NewClient(&CommonCredential{Credentials: creds}, "cn-beijing-6", cpf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.WithHttpTransport(&TokenTransport{Credentials: creds})
+
+	request := klog.NewListProjectsRequest()
+	request.ProjectName = stringPtr("tf-test")
+	if _, err = conn.ListProjectsSend(request); err != nil {
+		t.Fatal(err)
+	}
+	if token := header.Get("X-Amz-Security-Token"); token != "token" {
+		t.Errorf("Expected the session token, got %q", token)
+	}
+	authorization := header.Get("Authorization")
+	if !strings.Contains(authorization, "Credential=ak/") || !strings.Contains(authorization, "/cn-beijing-6/klog/aws4_request") {
+		t.Errorf("Expected the scope of klog, got %s", authorization)
+	}
+	if !strings.Contains(authorization, "x-amz-security-token") {
+		t.Errorf("Expected the session token to be signed, got %s", authorization)
+	}
+	if !strings.Contains(body, "tf-test") {
+		t.Errorf("Expected the request body to be kept, got %s", body)
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,145 @@
+package credential
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+// AssumeRoleProviderName provides a name of assume role provider
+const AssumeRoleProviderName = "KsyunAssumeRoleProvider"
+
+const (
+	// DefaultDuration is the default lifetime of assumed role credentials
+	DefaultDuration = time.Hour
+
+	// DefaultSessionName is the role session name used when it's not specified
+	DefaultSessionName = "terraform"
+)
+
+// expirationLayouts is a list of time layouts that sts returns as the expiration
+var expirationLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+// AssumeRoleAPI is the subset of sts client that assume role provider depends on
+type AssumeRoleAPI interface {
+	AssumeRole(input *map[string]interface{}) (*map[string]interface{}, error)
+}
+
+// AssumeRoleProvider retrieves temporary credentials by assuming an iam role,
+// the credentials will be refreshed before they expire.
+type AssumeRoleProvider struct {
+	credentials.Expiry
+
+	Client      AssumeRoleAPI
+	RoleKrn     string
+	SessionName string
+	Duration    time.Duration
+	Policy      string
+
+	// ExpiryWindow allows the credentials to trigger refreshing prior to
+	// the credentials actually expiring.
+	ExpiryWindow time.Duration
+}
+
+var _ credentials.Provider = (*AssumeRoleProvider)(nil)
+
+// NewAssumeRoleProvider returns an assume role provider with default settings
+func NewAssumeRoleProvider(client AssumeRoleAPI, roleKrn string) *AssumeRoleProvider {
+	return &AssumeRoleProvider{
+		Client:      client,
+		RoleKrn:     roleKrn,
+		SessionName: DefaultSessionName,
+		Duration:    DefaultDuration,
+	}
+}
+
+// Retrieve assumes the role and returns the temporary credentials
+func (p *AssumeRoleProvider) Retrieve() (credentials.Value, error) {
+	if p.RoleKrn == "" {
+		return credentials.Value{ProviderName: AssumeRoleProviderName}, fmt.Errorf("role_krn is required to assume role")
+	}
+	sessionName := p.SessionName
+	if sessionName == "" {
+		sessionName = DefaultSessionName
+	}
+	duration := p.Duration
+	if duration == 0 {
+		duration = DefaultDuration
+	}
+
+	input := map[string]interface{}{
+		"RoleKrn":         p.RoleKrn,
+		"RoleSessionName": sessionName,
+		"DurationSeconds": int(duration / time.Second),
+	}
+	if p.Policy != "" {
+		input["Policy"] = p.Policy
+	}
+
+	resp, err := p.Client.AssumeRole(&input)
+	if err != nil {
+		return credentials.Value{ProviderName: AssumeRoleProviderName}, fmt.Errorf("assume role %s failed: %s", p.RoleKrn, err)
+	}
+
+	value, expiration, err := parseAssumeRoleResponse(resp)
+	if err != nil {
+		return credentials.Value{ProviderName: AssumeRoleProviderName}, fmt.Errorf("assume role %s failed: %s", p.RoleKrn, err)
+	}
+	if expiration.IsZero() {
+		expiration = time.Now().Add(duration)
+	}
+
+	window := p.ExpiryWindow
+	if window == 0 {
+		// refresh at the last tenth of the lifetime
+		window = duration / 10
+	}
+	p.SetExpiration(expiration, window)
+
+	return value, nil
+}
+
+func parseAssumeRoleResponse(resp *map[string]interface{}) (credentials.Value, time.Time, error) {
+	value := credentials.Value{ProviderName: AssumeRoleProviderName}
+	if resp == nil {
+		return value, time.Time{}, fmt.Errorf("empty response")
+	}
+
+	data := *resp
+	if result, ok := data["AssumeRoleResult"].(map[string]interface{}); ok {
+		data = result
+	}
+	creds, ok := data["Credentials"].(map[string]interface{})
+	if !ok {
+		return value, time.Time{}, fmt.Errorf("credentials not found in response")
+	}
+
+	value.AccessKeyID, _ = creds["AccessKeyId"].(string)
+	value.SecretAccessKey, _ = creds["AccessKeySecret"].(string)
+	value.SessionToken, _ = creds["SecurityToken"].(string)
+	if value.AccessKeyID == "" || value.SecretAccessKey == "" {
+		return value, time.Time{}, fmt.Errorf("incomplete credentials in response")
+	}
+
+	var expiration time.Time
+	if exp, ok := creds["Expiration"].(string); ok && exp != "" {
+		expiration = parseExpiration(exp)
+	}
+	return value, expiration, nil
+}
+
+func parseExpiration(exp string) time.Time {
+	exp = strings.TrimSpace(exp)
+	for _, layout := range expirationLayouts {
+		if t, err := time.Parse(layout, exp); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
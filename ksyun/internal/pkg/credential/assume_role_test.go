@@ -0,0 +1,98 @@
+package credential
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/KscSDK/ksc-sdk-go/ksc"
+	"github.com/KscSDK/ksc-sdk-go/ksc/utils"
+	"github.com/KscSDK/ksc-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+func newStsServer(t *testing.T, expiration time.Time, calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(calls, 1)
+		if a := r.URL.Query().Get("Action"); a != "AssumeRole" {
+			t.Errorf("Expected action AssumeRole, got %s", a)
+		}
+		if krn := r.URL.Query().Get("RoleKrn"); krn != "krn:ksc:iam::1:role/test" {
+			t.Errorf("Expected role krn, got %s", krn)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"RequestId":"req","AssumeRoleResult":{"Credentials":{"AccessKeyId":"ak-%d","AccessKeySecret":"sk-%d","SecurityToken":"token-%d","Expiration":"%s"}}}`,
+			n, n, n, expiration.UTC().Format(time.RFC3339))
+	}))
+}
+
+func newStsClient(endpoint string) *sts.Sts {
+	sess := session.Must(session.NewSession(&aws.Config{
+		Credentials: credentials.NewStaticCredentials("ak", "sk", ""),
+		Region:      aws.String("cn-beijing-6"),
+	}))
+	// the stand-in sts server is addressed the same way as the provider's domain
+	return sts.SdkNew(sess, &ksc.Config{Region: aws.String("cn-beijing-6")}, &utils.UrlInfo{
+		CustomerDomain:              strings.TrimPrefix(endpoint, "http://"),
+		CustomerDomainIgnoreService: true,
+	})
+}
+
+func TestAssumeRoleProvider(t *testing.T) {
+	var calls int32
+	srv := newStsServer(t, time.Now().Add(time.Hour), &calls)
+	defer srv.Close()
+
+	creds := credentials.NewCredentials(NewAssumeRoleProvider(newStsClient(srv.URL), "krn:ksc:iam::1:role/test"))
+	v, err := creds.Get()
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if v.AccessKeyID != "ak-1" || v.SecretAccessKey != "sk-1" || v.SessionToken != "token-1" {
+		t.Errorf("Unexpected credentials %+v", v)
+	}
+
+	if _, err = creds.Get(); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected credentials to be cached, got %d calls", calls)
+	}
+}
+
+func TestAssumeRoleProviderRefresh(t *testing.T) {
+	var calls int32
+	// expires within the expiry window, so every retrieving refreshes
+	srv := newStsServer(t, time.Now().Add(time.Minute), &calls)
+	defer srv.Close()
+
+	creds := credentials.NewCredentials(NewAssumeRoleProvider(newStsClient(srv.URL), "krn:ksc:iam::1:role/test"))
+	if _, err := creds.Get(); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	v, err := creds.Get()
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if v.AccessKeyID != "ak-2" {
+		t.Errorf("Expected refreshed credentials, got %s", v.AccessKeyID)
+	}
+}
+
+func TestParseExpiration(t *testing.T) {
+	cases := []string{"2023-08-29T09:11:43Z", "2023-08-29T09:11:43", "2023-08-29 09:11:43"}
+	for _, c := range cases {
+		if parseExpiration(c).IsZero() {
+			t.Errorf("Expected %s to be parsed", c)
+		}
+	}
+	if !parseExpiration("invalid").IsZero() {
+		t.Errorf("Expected invalid expiration to be zero")
+	}
+}
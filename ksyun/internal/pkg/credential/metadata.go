@@ -0,0 +1,117 @@
+package credential
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/credentials"
+)
+
+// MetadataProviderName provides a name of instance metadata provider
+const MetadataProviderName = "KsyunMetadataProvider"
+
+// DefaultMetadataEndpoint is the endpoint of instance metadata service
+const DefaultMetadataEndpoint = "http://169.254.169.254/latest/meta-data"
+
+const metadataRolePath = "/iam/security-credentials/"
+
+const defaultMetadataRefreshInterval = 15 * time.Minute
+
+// MetadataProvider retrieves the credentials of the role attached to the
+// kec instance from instance metadata service.
+type MetadataProvider struct {
+	credentials.Expiry
+
+	// Endpoint of instance metadata service, DefaultMetadataEndpoint is used if empty
+	Endpoint string
+	Client   *http.Client
+
+	// ExpiryWindow allows the credentials to trigger refreshing prior to
+	// the credentials actually expiring.
+	ExpiryWindow time.Duration
+}
+
+var _ credentials.Provider = (*MetadataProvider)(nil)
+
+type metadataCredentials struct {
+	Code            string
+	AccessKeyId     string
+	SecretAccessKey string
+	Token           string
+	Expiration      string
+}
+
+// NewMetadataProvider returns an instance metadata provider with default settings
+func NewMetadataProvider() *MetadataProvider {
+	return &MetadataProvider{
+		Endpoint:     DefaultMetadataEndpoint,
+		Client:       &http.Client{Timeout: 5 * time.Second},
+		ExpiryWindow: 5 * time.Minute,
+	}
+}
+
+// Retrieve fetches the credentials of the attached role from instance metadata service
+func (p *MetadataProvider) Retrieve() (credentials.Value, error) {
+	value := credentials.Value{ProviderName: MetadataProviderName}
+	endpoint := strings.TrimSuffix(p.Endpoint, "/")
+	if endpoint == "" {
+		endpoint = DefaultMetadataEndpoint
+	}
+
+	roles, err := p.get(endpoint + metadataRolePath)
+	if err != nil {
+		return value, fmt.Errorf("failed to get instance role: %s", err)
+	}
+	role := strings.TrimSpace(strings.SplitN(strings.TrimSpace(roles), "\n", 2)[0])
+	if role == "" {
+		return value, fmt.Errorf("no role attached to the instance")
+	}
+
+	body, err := p.get(endpoint + metadataRolePath + role)
+	if err != nil {
+		return value, fmt.Errorf("failed to get credentials of instance role %s: %s", role, err)
+	}
+	var creds metadataCredentials
+	if err = json.Unmarshal([]byte(body), &creds); err != nil {
+		return value, fmt.Errorf("failed to parse credentials of instance role %s: %s", role, err)
+	}
+	if creds.Code != "" && creds.Code != "Success" {
+		return value, fmt.Errorf("failed to get credentials of instance role %s: %s", role, creds.Code)
+	}
+
+	value.AccessKeyID = creds.AccessKeyId
+	value.SecretAccessKey = creds.SecretAccessKey
+	value.SessionToken = creds.Token
+	expiration := parseExpiration(creds.Expiration)
+	if expiration.IsZero() {
+		// check the rotated credentials periodically if expiration is absent
+		expiration = time.Now().Add(defaultMetadataRefreshInterval)
+	}
+	p.SetExpiration(expiration, p.ExpiryWindow)
+	return value, nil
+}
+
+func (p *MetadataProvider) get(url string) (string, error) {
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return string(body), nil
+}
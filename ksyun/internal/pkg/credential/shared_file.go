@@ -0,0 +1,131 @@
+package credential
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// DefaultProfileName is the profile used when it's not specified
+	DefaultProfileName = "default"
+
+	// DefaultSharedCredentialsFile is the credentials file used when it's not specified,
+	// it's relative to the home directory of current user.
+	DefaultSharedCredentialsFile = ".ksyun/credentials"
+)
+
+// Profile is a named set of credentials in the shared credentials file
+type Profile struct {
+	AccessKey     string `json:"access_key"`
+	SecretKey     string `json:"secret_key"`
+	SecurityToken string `json:"security_token"`
+	Region        string `json:"region"`
+}
+
+// LoadProfile reads the profile from shared credentials file, the file
+// can be in ini format:
+//
+//	[default]
+//	access_key = ak
+//	secret_key = sk
+//	region     = cn-beijing-6
+//
+// or in json format:
+//
+//	{"default": {"access_key": "ak", "secret_key": "sk", "region": "cn-beijing-6"}}
+func LoadProfile(filename, profile string) (*Profile, error) {
+	if profile == "" {
+		profile = DefaultProfileName
+	}
+	filename, err := sharedCredentialsFilename(filename)
+	if err != nil {
+		return nil, err
+	}
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read shared credentials file %s: %s", filename, err)
+	}
+
+	var profiles map[string]Profile
+	if trimmed := strings.TrimSpace(string(content)); strings.HasPrefix(trimmed, "{") {
+		if err := json.Unmarshal(content, &profiles); err != nil {
+			return nil, fmt.Errorf("failed to parse shared credentials file %s: %s", filename, err)
+		}
+	} else {
+		if profiles, err = parseIniProfiles(trimmed); err != nil {
+			return nil, fmt.Errorf("failed to parse shared credentials file %s: %s", filename, err)
+		}
+	}
+
+	p, ok := profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("profile %s not found in shared credentials file %s", profile, filename)
+	}
+	return &p, nil
+}
+
+func sharedCredentialsFilename(filename string) (string, error) {
+	if filename == "" {
+		filename = filepath.Join("~", DefaultSharedCredentialsFile)
+	}
+	if filename == "~" || strings.HasPrefix(filename, "~/") || strings.HasPrefix(filename, "~"+string(os.PathSeparator)) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to expand shared credentials file %s: %s", filename, err)
+		}
+		filename = filepath.Join(home, filename[1:])
+	}
+	return filename, nil
+}
+
+func parseIniProfiles(content string) (map[string]Profile, error) {
+	profiles := make(map[string]Profile)
+	var (
+		section string
+		lineNum int
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("invalid section at line %d", lineNum)
+			}
+			section = strings.TrimSpace(strings.TrimPrefix(line[1:len(line)-1], "profile "))
+			profiles[section] = Profile{}
+			continue
+		}
+
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid key value pair at line %d", lineNum)
+		}
+		if section == "" {
+			return nil, fmt.Errorf("key value pair out of section at line %d", lineNum)
+		}
+		p := profiles[section]
+		value := strings.TrimSpace(kv[1])
+		switch strings.TrimSpace(kv[0]) {
+		case "access_key", "access_key_id":
+			p.AccessKey = value
+		case "secret_key", "secret_access_key":
+			p.SecretKey = value
+		case "security_token":
+			p.SecurityToken = value
+		case "region":
+			p.Region = value
+		}
+		profiles[section] = p
+	}
+	return profiles, scanner.Err()
+}
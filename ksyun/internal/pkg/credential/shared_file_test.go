@@ -0,0 +1,95 @@
+package credential
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeCredentialsFile(t *testing.T, content string) string {
+	dir, err := ioutil.TempDir("", "ksyun-credentials")
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	filename := filepath.Join(dir, "credentials")
+	if err = ioutil.WriteFile(filename, []byte(content), 0600); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	return filename
+}
+
+func TestLoadProfile(t *testing.T) {
+	cases := map[string]string{
+		"ini": `
+# comment
+[default]
+access_key = ak-default
+secret_key = sk-default
+
+[profile staging]
+access_key = ak-staging
+secret_key = sk-staging
+region     = cn-shanghai-2
+`,
+		"json": `{
+  "default": {"access_key": "ak-default", "secret_key": "sk-default"},
+  "staging": {"access_key": "ak-staging", "secret_key": "sk-staging", "region": "cn-shanghai-2"}
+}`,
+	}
+
+	for name, content := range cases {
+		filename := writeCredentialsFile(t, content)
+		defer os.RemoveAll(filepath.Dir(filename))
+
+		p, err := LoadProfile(filename, "")
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %s", name, err)
+		}
+		if p.AccessKey != "ak-default" || p.SecretKey != "sk-default" || p.Region != "" {
+			t.Errorf("%s: Unexpected default profile %+v", name, p)
+		}
+
+		p, err = LoadProfile(filename, "staging")
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %s", name, err)
+		}
+		if p.AccessKey != "ak-staging" || p.SecretKey != "sk-staging" || p.Region != "cn-shanghai-2" {
+			t.Errorf("%s: Unexpected staging profile %+v", name, p)
+		}
+
+		if _, err = LoadProfile(filename, "prod"); err == nil {
+			t.Errorf("%s: Expected error for missing profile", name)
+		}
+	}
+}
+
+func TestMetadataProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/latest/meta-data/iam/security-credentials/":
+			fmt.Fprint(w, "kec-role\n")
+		case "/latest/meta-data/iam/security-credentials/kec-role":
+			fmt.Fprint(w, `{"Code":"Success","AccessKeyId":"ak","SecretAccessKey":"sk","Token":"token","Expiration":"2099-01-01T00:00:00Z"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	p := NewMetadataProvider()
+	p.Endpoint = srv.URL + "/latest/meta-data"
+	v, err := p.Retrieve()
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if v.AccessKeyID != "ak" || v.SecretAccessKey != "sk" || v.SessionToken != "token" {
+		t.Errorf("Unexpected credentials %+v", v)
+	}
+	if p.IsExpired() {
+		t.Errorf("Expected credentials not to be expired")
+	}
+}
@@ -0,0 +1,282 @@
+package mockapi
+
+import "fmt"
+
+// the kinds of cen resources
+const (
+	KindCen                 = "Cen"
+	KindCenGrant            = "CenGrant"
+	KindCenNetworkInstance  = "CenNetworkInstance"
+	KindCenBandwidthPackage = "CenBandWidthPackage"
+	KindCenRegionBandwidth  = "CenRegionBandwidth"
+	KindCenRoute            = "CenRoute"
+)
+
+func registerCenActions(s *Server) {
+	s.register(Kind{Name: KindCen, SetName: "CenSet", IdField: "CenId"})
+	s.register(Kind{Name: KindCenGrant, SetName: "CenGrantSet", IdField: "CenGrantId"})
+	s.register(Kind{Name: KindCenNetworkInstance, SetName: "NetworkInstanceSet", IdField: "NetworkInstanceId"})
+	s.register(Kind{Name: KindCenBandwidthPackage, SetName: "CenBandWidthPackageSet", IdField: "CenBandWidthPackageId",
+		Ints: []string{"PackageBandWidth", "PurchaseTime"}})
+	s.register(Kind{Name: KindCenRegionBandwidth, SetName: "CenRegionBandwidthSet", IdField: "CenRegionBandwidthId",
+		Ints: []string{"InterBandWidth"}})
+	s.register(Kind{Name: KindCenRoute, SetName: "CenRouteSet", IdField: "CenRouteId"})
+
+	s.actions["CreateCen"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		item := s.Create(KindCen, p, nil)
+		return map[string]interface{}{"Cen": deepCopy(item)}, nil
+	}
+	s.actions["DescribeCens"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return s.Describe(KindCen, p), nil
+	}
+	s.actions["ModifyCen"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		item, err := s.Modify(KindCen, p)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"Cen": deepCopy(item)}, nil
+	}
+	s.actions["DeleteCen"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		id := p.String("CenId")
+		if instances := s.Select(KindCenNetworkInstance, fieldEquals("CenId", id)); len(instances) > 0 {
+			return nil, badRequest("DependencyViolation", "the cen %s still has %d network instances", id, len(instances))
+		}
+		if packages := s.Select(KindCenBandwidthPackage, fieldEquals("CenId", id)); len(packages) > 0 {
+			return nil, badRequest("DependencyViolation", "the cen %s still has %d bandwidth packages", id, len(packages))
+		}
+		return nil, s.Remove(KindCen, id)
+	}
+
+	// grant, it's created by the account of the network instance
+	s.actions["CreateCenGrant"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		if err := s.checkCenNetworkInstance(p.String("InstanceType"), p.String("NetworkInstanceId")); err != nil {
+			return nil, err
+		}
+		item := s.Create(KindCenGrant, p, nil)
+		return map[string]interface{}{"CenGrant": deepCopy(item)}, nil
+	}
+	s.actions["DescribeCenGrants"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return s.Describe(KindCenGrant, p), nil
+	}
+	s.actions["DeleteCenGrant"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return nil, s.Remove(KindCenGrant, p.String("CenGrantId"))
+	}
+
+	// network instance, the routes of the instance are learned by the cen once it's attached
+	s.actions["AttachNetworkInstance"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		cenId := p.String("CenId")
+		instanceId := p.String("NetworkInstanceId")
+		if _, err := s.Get(KindCen, cenId); err != nil {
+			return nil, err
+		}
+		if len(s.Select(KindCenNetworkInstance, fieldEquals("NetworkInstanceId", instanceId))) > 0 {
+			return nil, badRequest("InvalidParameterValue", "the network instance %s is already attached to a cen", instanceId)
+		}
+		account := p.String("InstanceAccountId")
+		if account == "" {
+			account = DefaultAccountId
+		}
+		if account == DefaultAccountId {
+			if err := s.checkCenNetworkInstance(p.String("InstanceType"), instanceId); err != nil {
+				return nil, err
+			}
+		} else {
+			grants := s.Select(KindCenGrant, func(item map[string]interface{}) bool {
+				return item["CenId"] == cenId && item["NetworkInstanceId"] == instanceId && item["CenAccountId"] == DefaultAccountId
+			})
+			if len(grants) == 0 {
+				return nil, badRequest("Forbidden", "the network instance %s of account %s is not granted to the cen %s",
+					instanceId, account, cenId)
+			}
+		}
+		item := s.Create(KindCenNetworkInstance, p, map[string]interface{}{
+			"InstanceRegion": DefaultRegion,
+		})
+		item["InstanceAccountId"] = account
+		// the vpcs of all accounts are kept in the same store
+		if vpc := s.Find(KindVpc, instanceId); vpc != nil {
+			s.Insert(KindCenRoute, map[string]interface{}{
+				"CenRouteId":           s.NewId(),
+				"CenId":                cenId,
+				"DestinationCidrBlock": vpc["CidrBlock"],
+				"NetworkInstanceId":    instanceId,
+				"InstanceType":         item["InstanceType"],
+				"InstanceRegion":       item["InstanceRegion"],
+				"InstanceAccountId":    account,
+				"InstanceRouteType":    "Vpc",
+				"CreateTime":           now(),
+			})
+		}
+		return nil, nil
+	}
+	s.actions["DescribeNetworkInstances"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return s.Describe(KindCenNetworkInstance, p), nil
+	}
+	s.actions["DetachNetworkInstance"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		cenId := p.String("CenId")
+		instanceId := p.String("NetworkInstanceId")
+		item := s.Find(KindCenNetworkInstance, instanceId)
+		if item == nil || item["CenId"] != cenId {
+			return nil, notFound(KindCenNetworkInstance, instanceId)
+		}
+		for _, route := range s.Select(KindCenRoute, fieldEquals("NetworkInstanceId", instanceId)) {
+			_ = s.Remove(KindCenRoute, fmt.Sprintf("%v", route["CenRouteId"]))
+		}
+		return nil, s.Remove(KindCenNetworkInstance, instanceId)
+	}
+	s.actions["DescribeCenRoutes"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return s.Describe(KindCenRoute, p), nil
+	}
+
+	// bandwidth package, the bandwidth is allocated to the pairs of regions by the region bandwidths
+	s.actions["CreateCenBandWidthPackage"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		if cenId := p.String("CenId"); cenId != "" {
+			if _, err := s.Get(KindCen, cenId); err != nil {
+				return nil, err
+			}
+		}
+		item := s.Create(KindCenBandwidthPackage, p, map[string]interface{}{
+			"ProjectId": DefaultProjectId,
+		})
+		for _, k := range []string{"ChargeType", "PurchaseTime"} {
+			delete(item, k)
+		}
+		return map[string]interface{}{"CenBandWidthPackage": deepCopy(item)}, nil
+	}
+	s.actions["DescribeCenBandWidthPackages"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return s.Describe(KindCenBandwidthPackage, p), nil
+	}
+	s.actions["ModifyCenBandWidthPackage"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		item, err := s.Get(KindCenBandwidthPackage, p.String("CenBandWidthPackageId"))
+		if err != nil {
+			return nil, err
+		}
+		if bandwidth := p.Int("PackageBandWidth", 0); bandwidth > 0 && bandwidth < s.cenAllocatedBandwidth(item, "") {
+			return nil, badRequest("InvalidParameterValue", "the PackageBandWidth %d is less than the allocated bandwidth", bandwidth)
+		}
+		item, err = s.Modify(KindCenBandwidthPackage, p)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"CenBandWidthPackage": deepCopy(item)}, nil
+	}
+	s.actions["AttachCenBandWidthPackage"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		item, err := s.Get(KindCenBandwidthPackage, p.String("CenBandWidthPackageId"))
+		if err != nil {
+			return nil, err
+		}
+		if _, err = s.Get(KindCen, p.String("CenId")); err != nil {
+			return nil, err
+		}
+		if _, ok := item["CenId"]; ok {
+			return nil, badRequest("InvalidParameterValue", "the bandwidth package %v is already attached to the cen %v",
+				item["CenBandWidthPackageId"], item["CenId"])
+		}
+		item["CenId"] = p.String("CenId")
+		return nil, nil
+	}
+	s.actions["DetachCenBandWidthPackage"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		item, err := s.Get(KindCenBandwidthPackage, p.String("CenBandWidthPackageId"))
+		if err != nil {
+			return nil, err
+		}
+		if item["CenId"] != p.String("CenId") {
+			return nil, badRequest("InvalidParameterValue", "the bandwidth package %v is not attached to the cen %s",
+				item["CenBandWidthPackageId"], p.String("CenId"))
+		}
+		if len(s.Select(KindCenRegionBandwidth, fieldEquals("CenBandWidthPackageId", p.String("CenBandWidthPackageId")))) > 0 {
+			return nil, badRequest("DependencyViolation", "the bandwidth package %v is still in use by region bandwidths",
+				item["CenBandWidthPackageId"])
+		}
+		delete(item, "CenId")
+		return nil, nil
+	}
+	s.actions["DeleteCenBandWidthPackage"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		id := p.String("CenBandWidthPackageId")
+		item, err := s.Get(KindCenBandwidthPackage, id)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := item["CenId"]; ok {
+			return nil, badRequest("DependencyViolation", "the bandwidth package %s is still attached to the cen %v", id, item["CenId"])
+		}
+		return nil, s.Remove(KindCenBandwidthPackage, id)
+	}
+
+	// region bandwidth
+	s.actions["CreateCenRegionBandwidth"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		pkg, err := s.Get(KindCenBandwidthPackage, p.String("CenBandWidthPackageId"))
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := pkg["CenId"]; !ok {
+			return nil, badRequest("InvalidParameterValue", "the bandwidth package %v is not attached to any cen", pkg["CenBandWidthPackageId"])
+		}
+		if err = s.checkCenBandwidth(pkg, "", p.Int("InterBandWidth", 0)); err != nil {
+			return nil, err
+		}
+		item := s.Create(KindCenRegionBandwidth, p, nil)
+		delete(item, "CreateTime")
+		item["CenId"] = pkg["CenId"]
+		return map[string]interface{}{"CenRegionBandwidth": deepCopy(item)}, nil
+	}
+	s.actions["DescribeCenRegionBandwidths"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return s.Describe(KindCenRegionBandwidth, p), nil
+	}
+	s.actions["ModifyCenRegionBandwidth"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		id := p.String("CenRegionBandwidthId")
+		item, err := s.Get(KindCenRegionBandwidth, id)
+		if err != nil {
+			return nil, err
+		}
+		pkg, err := s.Get(KindCenBandwidthPackage, fmt.Sprintf("%v", item["CenBandWidthPackageId"]))
+		if err != nil {
+			return nil, err
+		}
+		if err = s.checkCenBandwidth(pkg, id, p.Int("InterBandWidth", 0)); err != nil {
+			return nil, err
+		}
+		item, err = s.Modify(KindCenRegionBandwidth, p)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"CenRegionBandwidth": deepCopy(item)}, nil
+	}
+	s.actions["DeleteCenRegionBandwidth"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return nil, s.Remove(KindCenRegionBandwidth, p.String("CenRegionBandwidthId"))
+	}
+}
+
+// checkCenNetworkInstance checks the network instance of the account exists, only the vpcs are kept by the server
+func (s *Server) checkCenNetworkInstance(instanceType, instanceId string) error {
+	switch instanceType {
+	case "Vpc":
+		_, err := s.Get(KindVpc, instanceId)
+		return err
+	case "DirectConnectGateway":
+		return nil
+	}
+	return badRequest("InvalidParameterValue", "the InstanceType %s is invalid", instanceType)
+}
+
+// cenAllocatedBandwidth returns the bandwidth of the package allocated to the region bandwidths except the excluded one
+func (s *Server) cenAllocatedBandwidth(pkg map[string]interface{}, exclude string) int {
+	allocated := 0
+	packageId := fmt.Sprintf("%v", pkg["CenBandWidthPackageId"])
+	for _, item := range s.Select(KindCenRegionBandwidth, fieldEquals("CenBandWidthPackageId", packageId)) {
+		if item["CenRegionBandwidthId"] != exclude {
+			allocated += item["InterBandWidth"].(int)
+		}
+	}
+	return allocated
+}
+
+func (s *Server) checkCenBandwidth(pkg map[string]interface{}, exclude string, bandwidth int) error {
+	if bandwidth < 1 {
+		return badRequest("MissingParameter", "InterBandWidth is required")
+	}
+	if s.cenAllocatedBandwidth(pkg, exclude)+bandwidth > pkg["PackageBandWidth"].(int) {
+		return badRequest("QuotaExceeded", "the bandwidth package %v has only %v Mbps", pkg["CenBandWidthPackageId"], pkg["PackageBandWidth"])
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package mockapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultProjectId is the only project of the account
+const DefaultProjectId = "0"
+
+// DefaultAccountId and DefaultRegion are the account and region of the requests, the server keeps the resources
+// of all regions in one store, only the images and snapshots are isolated by the region of the requests
+const (
+	DefaultAccountId = "2000000000"
+	DefaultRegion    = "cn-beijing-6"
+)
+
+func registerCommonActions(s *Server) {
+	s.actions["GetAccountAllProjectList"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return map[string]interface{}{
+			"ListProjectResult": map[string]interface{}{
+				"ProjectList": []interface{}{
+					map[string]interface{}{"ProjectId": DefaultProjectId, "ProjectName": "default"},
+				},
+			},
+		}, nil
+	}
+
+	s.actions["ReplaceResourcesTags"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		tags := tagParams(p, "Tag_%d_Key", "Tag_%d_Value")
+		var uuids []string
+		if replaceTags, ok := p["ReplaceTags"].([]interface{}); ok {
+			for _, r := range replaceTags {
+				if m, ok := r.(map[string]interface{}); ok {
+					uuids = append(uuids, strings.Split(fmt.Sprintf("%v", m["ResourceUuids"]), ",")...)
+				}
+			}
+		}
+		if len(uuids) == 0 {
+			return nil, badRequest("MissingParameter", "ReplaceTags.ResourceUuids is required")
+		}
+		for _, uuid := range uuids {
+			s.SetTags(uuid, tags)
+		}
+		return nil, nil
+	}
+
+	s.actions["ListTagsByResourceIds"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		result := []interface{}{}
+		for _, uuid := range strings.Split(p.String("ResourceUuids"), ",") {
+			tags := s.tags[uuid]
+			keys := make([]string, 0, len(tags))
+			for k := range tags {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				result = append(result, map[string]interface{}{
+					"ResourceUuid": uuid,
+					"ResourceType": p.String("ResourceType"),
+					"TagKey":       k,
+					"TagValue":     tags[k],
+				})
+			}
+		}
+		return map[string]interface{}{"Tags": result}, nil
+	}
+}
+
+// SetTags replaces the tags of resource
+func (s *Server) SetTags(resourceId string, tags map[string]string) {
+	if len(tags) == 0 {
+		delete(s.tags, resourceId)
+		return
+	}
+	s.tags[resourceId] = tags
+}
+
+// tagParams reads the tags from the parameters with key and value formats, such as Tag.%d.Key
+func tagParams(p Params, keyFormat, valueFormat string) map[string]string {
+	tags := make(map[string]string)
+	for i := 1; ; i++ {
+		k, ok := p[fmt.Sprintf(keyFormat, i)]
+		if !ok {
+			break
+		}
+		tags[fmt.Sprintf("%v", k)] = p.String(fmt.Sprintf(valueFormat, i))
+	}
+	return tags
+}
+
+// ids returns the values of key.N, or the value of key if the list is absent
+func ids(p Params, key string) []string {
+	if list := p.List(key); len(list) > 0 {
+		return list
+	}
+	if v := p.String(key); v != "" {
+		return []string{v}
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+package mockapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// the kinds of ebs resources
+const (
+	KindVolume             = "Volume"
+	KindSnapshot           = "Snapshot"
+	KindAutoSnapshotPolicy = "AutoSnapshotPolicy"
+)
+
+func registerEbsActions(s *Server) {
+	s.register(Kind{Name: KindVolume, SetName: "Volumes", IdField: "VolumeId", Ints: []string{"Size"}})
+	s.register(Kind{Name: KindSnapshot, SetName: "Snapshots", IdField: "SnapshotId", Ints: []string{"Size"}})
+	s.register(Kind{Name: KindAutoSnapshotPolicy, SetName: "AutoSnapshotPolicySet", IdField: "AutoSnapshotPolicyId", Ints: []string{"RetentionTime"}})
+
+	s.actions["DescribeVolumes"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return s.Describe(KindVolume, p), nil
+	}
+	s.actions["ResizeVolume"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		volume, err := s.Get(KindVolume, p.String("VolumeId"))
+		if err != nil {
+			return nil, err
+		}
+		size := p.Int("Size", 0)
+		if size <= volume["Size"].(int) {
+			return nil, badRequest("MalformedParameter", "the Size %d should be greater than %v", size, volume["Size"])
+		}
+		if volume["VolumeStatus"] == "in-use" && p.String("OnlineResize") != "true" {
+			return nil, badRequest("VolumeInUse", "the volume %s in use should be resized online", volume["VolumeId"])
+		}
+		volume["Size"] = size
+		s.syncInstanceDisk(volume)
+		return map[string]interface{}{"Return": true}, nil
+	}
+	s.actions["ModifyVolumeType"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		volume, err := s.Get(KindVolume, p.String("VolumeId"))
+		if err != nil {
+			return nil, err
+		}
+		volumeType := p.String("PerformanceLevelVolumeCategory")
+		if !strings.HasPrefix(volumeType, "ESSD_") && volumeType != "SSD3.0" && volumeType != "EHDD" {
+			return nil, badRequest("MalformedParameter", "the volume type %s is unsupported", volumeType)
+		}
+		volume["VolumeType"] = volumeType
+		s.syncInstanceDisk(volume)
+		return map[string]interface{}{"Return": true}, nil
+	}
+
+	// snapshot, the snapshots are available once created, they're only visible to the requests of their
+	// Region, which is the region of the request creating them, or the destination region of the copies
+	s.actions["CreateSnapshot"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		volume, err := s.Get(KindVolume, p.String("VolumeId"))
+		if err != nil {
+			return nil, err
+		}
+		snapshot := s.Insert(KindSnapshot, map[string]interface{}{
+			"SnapshotId":          s.NewId(),
+			"SnapshotName":        p.String("SnapshotName"),
+			"SnapshotDesc":        p.String("SnapshotDesc"),
+			"SnapshotType":        "CommonSnapShot",
+			"SnapshotStatus":      "available",
+			"Progress":            "100%",
+			"VolumeId":            volume["VolumeId"],
+			"VolumeCategory":      volume["VolumeCategory"],
+			"VolumeStatus":        volume["VolumeStatus"],
+			"Size":                volume["Size"],
+			"ScheduledDeleteTime": p.String("ScheduledDeleteTime"),
+			"Region":              s.Region(),
+			"CreateTime":          now(),
+		})
+		return map[string]interface{}{"SnapshotId": snapshot["SnapshotId"]}, nil
+	}
+	s.actions["DescribeSnapshots"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		snapshots := []interface{}{}
+		for _, snapshot := range s.Select(KindSnapshot, func(item map[string]interface{}) bool {
+			if item["Region"] != s.Region() {
+				return false
+			}
+			for _, k := range []string{"SnapshotId", "SnapshotName", "VolumeId"} {
+				if v := p.String(k); v != "" && fmt.Sprintf("%v", item[k]) != v {
+					return false
+				}
+			}
+			return true
+		}) {
+			snapshots = append(snapshots, deepCopy(snapshot))
+		}
+		return map[string]interface{}{
+			"Snapshots": snapshots,
+			"Page":      map[string]interface{}{"hasNext": false},
+		}, nil
+	}
+	s.actions["ModifySnapshot"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		snapshot, err := s.getSnapshot(p.String("SnapshotId"))
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range []string{"SnapshotName", "SnapshotDesc"} {
+			if v, ok := p[k]; ok {
+				snapshot[k] = v
+			}
+		}
+		return map[string]interface{}{"Return": true}, nil
+	}
+	s.actions["DeleteSnapshot"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		if _, err := s.getSnapshot(p.String("SnapshotId")); err != nil {
+			return nil, err
+		}
+		if err := s.Remove(KindSnapshot, p.String("SnapshotId")); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"Return": true}, nil
+	}
+	// CopySnapshot is an action of kec, it doesn't return the ids of the copies
+	s.actions["CopySnapshot"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		regions := p.List("DestinationRegion")
+		if len(regions) == 0 {
+			return nil, badRequest("MissingParameter", "DestinationRegion is required")
+		}
+		for _, id := range p.List("SnapshotId") {
+			source, err := s.getSnapshot(id)
+			if err != nil {
+				return nil, err
+			}
+			for _, region := range regions {
+				copied := deepCopy(source)
+				copied["SnapshotId"] = s.NewId()
+				copied["SnapshotName"] = p.String("DestinationSnapshotName")
+				copied["SnapshotDesc"] = p.String("DestinationSnapshotDesc")
+				copied["SourceSnapshotId"] = id
+				copied["Region"] = region
+				copied["CreateTime"] = now()
+				s.Insert(KindSnapshot, copied)
+			}
+		}
+		return map[string]interface{}{"Return": true}, nil
+	}
+
+	// auto snapshot policy, the policies are actions of kec and applied to the volumes by AutoSnapshotPolicyId
+	s.actions["CreateAutoSnapshotPolicy"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		if p.String("AutoSnapshotPolicyName") == "" {
+			return nil, badRequest("MissingParameter", "AutoSnapshotPolicyName is required")
+		}
+		policy := s.Insert(KindAutoSnapshotPolicy, map[string]interface{}{
+			"AutoSnapshotPolicyId":   s.NewId(),
+			"AutoSnapshotPolicyName": p.String("AutoSnapshotPolicyName"),
+			"AutoSnapshotDate":       p.List("AutoSnapshotDate"),
+			"AutoSnapshotTime":       p.List("AutoSnapshotTime"),
+			"RetentionTime":          p.Int("RetentionTime", 0),
+			"CreationDate":           now(),
+		})
+		return map[string]interface{}{"AutoSnapshotPolicyId": policy["AutoSnapshotPolicyId"]}, nil
+	}
+	s.actions["DescribeAutoSnapshotPolicy"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		resp := s.Describe(KindAutoSnapshotPolicy, p)
+		for _, v := range resp["AutoSnapshotPolicySet"].([]interface{}) {
+			policy := v.(map[string]interface{})
+			policy["AttachEBSVolumeNum"] = len(s.Select(KindVolume, fieldEquals("AutoSnapshotPolicyId", fmt.Sprintf("%v", policy["AutoSnapshotPolicyId"]))))
+		}
+		return resp, nil
+	}
+	s.actions["ModifyAutoSnapshotPolicy"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		policy, err := s.Get(KindAutoSnapshotPolicy, p.String("AutoSnapshotPolicyId"))
+		if err != nil {
+			return nil, err
+		}
+		if v := p.String("AutoSnapshotPolicyName"); v != "" {
+			policy["AutoSnapshotPolicyName"] = v
+		}
+		for _, k := range []string{"AutoSnapshotDate", "AutoSnapshotTime"} {
+			if v := p.List(k); len(v) > 0 {
+				policy[k] = v
+			}
+		}
+		if _, ok := p["RetentionTime"]; ok {
+			policy["RetentionTime"] = p.Int("RetentionTime", 0)
+		}
+		return map[string]interface{}{"Return": true}, nil
+	}
+	s.actions["DeleteAutoSnapshotPolicy"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		var set []interface{}
+		for _, id := range p.List("AutoSnapshotPolicyId") {
+			if err := s.Remove(KindAutoSnapshotPolicy, id); err != nil {
+				return nil, err
+			}
+			for _, volume := range s.Select(KindVolume, fieldEquals("AutoSnapshotPolicyId", id)) {
+				delete(volume, "AutoSnapshotPolicyId")
+			}
+			set = append(set, map[string]interface{}{"AutoSnapshotPolicyId": id, "Return": true})
+		}
+		return map[string]interface{}{"AutoSnapshotPolicySet": set}, nil
+	}
+	s.actions["ApplyAutoSnapshotPolicy"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		policyId := p.String("AutoSnapshotPolicyId")
+		if _, err := s.Get(KindAutoSnapshotPolicy, policyId); err != nil {
+			return nil, err
+		}
+		return s.applyAutoSnapshotPolicy(p, policyId)
+	}
+	s.actions["CancelAutoSnapshotPolicy"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		if _, err := s.Get(KindAutoSnapshotPolicy, p.String("AutoSnapshotPolicyId")); err != nil {
+			return nil, err
+		}
+		return s.applyAutoSnapshotPolicy(p, "")
+	}
+}
+
+// applyAutoSnapshotPolicy applies the policy to the volumes of AttachVolumeId.N, the policy is cancelled when it's empty
+func (s *Server) applyAutoSnapshotPolicy(p Params, policyId string) (map[string]interface{}, error) {
+	ids := p.List("AttachVolumeId")
+	if len(ids) == 0 {
+		return nil, badRequest("MissingParameter", "AttachVolumeId is required")
+	}
+	var volumes []map[string]interface{}
+	for _, id := range ids {
+		volume, err := s.Get(KindVolume, id)
+		if err != nil {
+			return nil, err
+		}
+		volumes = append(volumes, volume)
+	}
+	for _, volume := range volumes {
+		if policyId == "" {
+			delete(volume, "AutoSnapshotPolicyId")
+			continue
+		}
+		volume["AutoSnapshotPolicyId"] = policyId
+	}
+	return map[string]interface{}{"ReturnSet": map[string]interface{}{"Return": true}}, nil
+}
+
+// getSnapshot returns the snapshot of the request region by id, a not found error is returned if it doesn't exist.
+func (s *Server) getSnapshot(id string) (map[string]interface{}, error) {
+	snapshot := s.Find(KindSnapshot, id)
+	if snapshot == nil || snapshot["Region"] != s.Region() {
+		return nil, notFound(KindSnapshot, id)
+	}
+	return snapshot, nil
+}
+
+// createInstanceVolume creates the volume of the EBS data disk created with instance
+func (s *Server) createInstanceVolume(instanceId string, disk map[string]interface{}) {
+	s.Insert(KindVolume, map[string]interface{}{
+		"VolumeId":           disk["DiskId"],
+		"VolumeName":         fmt.Sprintf("%v", disk["DiskId"]),
+		"VolumeType":         disk["DiskType"],
+		"VolumeCategory":     "data",
+		"VolumeStatus":       "in-use",
+		"Size":               disk["DiskSize"],
+		"InstanceId":         instanceId,
+		"ProjectId":          DefaultProjectId,
+		"DeleteWithInstance": disk["DeleteWithInstance"],
+		"CreateTime":         now(),
+	})
+}
+
+// releaseInstanceVolumes deletes the volumes deleted with instance and detaches the others
+func (s *Server) releaseInstanceVolumes(instanceId string) {
+	for _, volume := range s.Select(KindVolume, fieldEquals("InstanceId", instanceId)) {
+		if volume["DeleteWithInstance"] == true {
+			_ = s.Remove(KindVolume, fmt.Sprintf("%v", volume["VolumeId"]))
+			continue
+		}
+		volume["VolumeStatus"] = "available"
+		delete(volume, "InstanceId")
+	}
+}
+
+// syncInstanceDisk updates the data disk of the instance which the volume is attached to
+func (s *Server) syncInstanceDisk(volume map[string]interface{}) {
+	instance := s.Find(KindInstance, fmt.Sprintf("%v", volume["InstanceId"]))
+	if instance == nil {
+		return
+	}
+	for _, disk := range instance["DataDisks"].([]interface{}) {
+		if m := disk.(map[string]interface{}); m["DiskId"] == volume["VolumeId"] {
+			m["DiskSize"] = volume["Size"]
+			m["DiskType"] = volume["VolumeType"]
+		}
+	}
+}
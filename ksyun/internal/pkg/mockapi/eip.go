@@ -0,0 +1,94 @@
+package mockapi
+
+import "fmt"
+
+// the kinds of eip resources
+const (
+	KindAddress = "Address"
+	KindLine    = "Line"
+)
+
+// DefaultLineId is the id of the BGP line
+const DefaultLineId = "5fc2595f-1bfd-481b-bf64-2d08f116d800"
+
+func registerEipActions(s *Server) {
+	s.register(Kind{Name: KindAddress, SetName: "AddressesSet", IdField: "AllocationId", Ints: []string{"BandWidth"}})
+	s.register(Kind{Name: KindLine, SetName: "LineSet", IdField: "LineId"})
+	s.Insert(KindLine, map[string]interface{}{
+		"LineId":    DefaultLineId,
+		"LineName":  "BGP",
+		"LineType":  "BGP",
+		"IpVersion": "ipv4",
+	})
+
+	s.actions["GetLines"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return s.Describe(KindLine, p), nil
+	}
+	s.actions["AllocateAddress"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		lineId := p.String("LineId")
+		if lineId == "" {
+			lineId = DefaultLineId
+		}
+		if _, err := s.Get(KindLine, lineId); err != nil {
+			return nil, err
+		}
+		item := s.Create(KindAddress, p, map[string]interface{}{
+			"ChargeType": "PostPaidByDay",
+			"BandWidth":  1,
+			"ProjectId":  DefaultProjectId,
+			"IpVersion":  "ipv4",
+		})
+		item["LineId"] = lineId
+		item["PublicIp"] = fmt.Sprintf("198.51.%d.%d", (s.seq/250)%250, s.seq%250+1)
+		item["State"] = "disassociate"
+		return map[string]interface{}{
+			"AllocationId": item["AllocationId"],
+			"PublicIp":     item["PublicIp"],
+		}, nil
+	}
+	s.actions["DescribeAddresses"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return s.Describe(KindAddress, p), nil
+	}
+	s.actions["ModifyAddress"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		_, err := s.Modify(KindAddress, p)
+		return nil, err
+	}
+	s.actions["ReleaseAddress"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		id := p.String("AllocationId")
+		item, err := s.Get(KindAddress, id)
+		if err != nil {
+			return nil, err
+		}
+		if item["State"] == "associate" {
+			return nil, badRequest("AddressInUse", "the address %s is still associated with %v", id, item["InstanceId"])
+		}
+		return nil, s.Remove(KindAddress, id)
+	}
+	s.actions["AssociateAddress"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		item, err := s.Get(KindAddress, p.String("AllocationId"))
+		if err != nil {
+			return nil, err
+		}
+		if item["State"] == "associate" {
+			return nil, badRequest("AddressInUse", "the address %v is already associated", item["AllocationId"])
+		}
+		for _, k := range []string{"InstanceId", "InstanceType", "NetworkInterfaceId", "PrivateIpAddress"} {
+			if v := p.String(k); v != "" {
+				item[k] = v
+			}
+		}
+		item["State"] = "associate"
+		return nil, nil
+	}
+	s.actions["DisassociateAddress"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		item, err := s.Get(KindAddress, p.String("AllocationId"))
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range []string{"InstanceId", "InstanceType", "NetworkInterfaceId", "PrivateIpAddress"} {
+			delete(item, k)
+		}
+		item["State"] = "disassociate"
+		return nil, nil
+	}
+}
@@ -0,0 +1,577 @@
+package mockapi
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// the kinds of kec resources
+const (
+	KindInstance       = "Instance"
+	KindImage          = "Image"
+	KindLaunchTemplate = "LaunchTemplate"
+)
+
+// the states of kec instances
+const (
+	InstanceStateActive  = "active"
+	InstanceStateStopped = "stopped"
+)
+
+func registerKecActions(s *Server) {
+	s.register(Kind{Name: KindInstance, SetName: "InstancesSet", IdField: "InstanceId", Ints: []string{"ProjectId"}})
+	s.register(Kind{Name: KindImage, SetName: "ImagesSet", IdField: "ImageId"})
+	s.register(Kind{Name: KindLaunchTemplate, SetName: "LaunchTemplateSet", IdField: "LaunchTemplateId"})
+
+	s.actions["RunInstances"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		for _, k := range []string{"ImageId", "InstanceType", "SubnetId"} {
+			if p.String(k) == "" {
+				return nil, badRequest("MissingParameter", "%s is required", k)
+			}
+		}
+		if err := checkSpot(p); err != nil {
+			return nil, err
+		}
+		subnet, err := s.Get(KindSubnet, p.String("SubnetId"))
+		if err != nil {
+			return nil, err
+		}
+		securityGroups := []interface{}{}
+		for _, id := range p.List("SecurityGroupId") {
+			if _, err = s.Get(KindSecurityGroup, id); err != nil {
+				return nil, err
+			}
+			securityGroups = append(securityGroups, map[string]interface{}{"SecurityGroupId": id})
+		}
+
+		count := p.Int("MaxCount", 1)
+		if count < 1 {
+			count = 1
+		}
+		if p.Int("MinCount", 1) > count {
+			return nil, badRequest("InvalidParameter", "MinCount is greater than MaxCount")
+		}
+		var instances []interface{}
+		for i := 0; i < count; i++ {
+			instance, err := s.runInstance(p, subnet, securityGroups, i)
+			if err != nil {
+				return nil, err
+			}
+			instances = append(instances, map[string]interface{}{
+				"InstanceId":   instance["InstanceId"],
+				"InstanceName": instance["InstanceName"],
+			})
+		}
+		return map[string]interface{}{"InstancesSet": instances}, nil
+	}
+	s.actions["DescribeInstances"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		resp := s.Describe(KindInstance, p)
+		resp["InstanceCount"] = resp["TotalCount"]
+		return resp, nil
+	}
+	s.actions["ModifyInstanceAttribute"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		instance, err := s.Get(KindInstance, p.String("InstanceId"))
+		if err != nil {
+			return nil, err
+		}
+		for _, k := range []string{"InstanceName", "HostName", "UserData"} {
+			if v, ok := p[k]; ok {
+				instance[k] = v
+			}
+		}
+		return nil, nil
+	}
+	s.actions["ModifyInstanceType"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		instance, err := s.Get(KindInstance, p.String("InstanceId"))
+		if err != nil {
+			return nil, err
+		}
+		if v := p.String("InstanceType"); v != "" {
+			instance["InstanceType"] = v
+		}
+		if v := p.Int("DataDiskGb", -1); v >= 0 {
+			instance["InstanceConfigure"].(map[string]interface{})["DataDiskGb"] = v
+		}
+		return nil, nil
+	}
+	s.actions["StartInstances"] = instanceStateAction(InstanceStateActive)
+	s.actions["RebootInstances"] = instanceStateAction(InstanceStateActive)
+	s.actions["StopInstances"] = instanceStateAction(InstanceStateStopped)
+	s.actions["TerminateInstances"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		var result []interface{}
+		for _, id := range ids(p, "InstanceId") {
+			if err := s.Remove(KindInstance, id); err != nil {
+				return nil, err
+			}
+			s.releaseInstanceVolumes(id)
+			for _, ni := range s.Select(KindNetworkInterface, fieldEquals("InstanceId", id)) {
+				_ = s.Remove(KindNetworkInterface, fmt.Sprintf("%v", ni["NetworkInterfaceId"]))
+			}
+			result = append(result, map[string]interface{}{"InstanceId": id, "Return": true})
+		}
+		return map[string]interface{}{"InstancesSet": result}, nil
+	}
+
+	// image, the images are active once created, they're only visible to the requests of their Region
+	s.actions["CreateImage"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		image := map[string]interface{}{
+			"ImageId":          "IMG-" + s.NewId(),
+			"Name":             p.String("Name"),
+			"ImageState":       "active",
+			"ImageSource":      "user",
+			"Platform":         "centos-7.9",
+			"CreationDate":     now(),
+			"SysDisk":          20,
+			"Progress":         "100",
+			"IsPublic":         false,
+			"IsNpe":            false,
+			"CloudInitSupport": true,
+			"Region":           s.Region(),
+		}
+		if id := p.String("InstanceId"); id != "" {
+			instance, err := s.Get(KindInstance, id)
+			if err != nil {
+				return nil, err
+			}
+			image["InstanceId"] = id
+			image["SysDisk"] = instance["SystemDisk"].(map[string]interface{})["DiskSize"]
+		} else if p.String("SnapshotIds.1") == "" {
+			return nil, badRequest("MissingParameter", "InstanceId or SnapshotIds is required")
+		}
+		image["SharePermissionSet"] = []interface{}{}
+		s.Insert(KindImage, image)
+		return map[string]interface{}{"ImageId": image["ImageId"]}, nil
+	}
+	s.actions["DescribeImages"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		if id := p.String("ImageId"); id != "" {
+			p["ImageId.1"] = id
+			delete(p, "ImageId")
+		}
+		resp := s.Describe(KindImage, p)
+		images := []interface{}{}
+		for _, image := range resp["ImagesSet"].([]interface{}) {
+			if image.(map[string]interface{})["Region"] != s.Region() {
+				continue
+			}
+			delete(image.(map[string]interface{}), "SharePermissionSet")
+			images = append(images, image)
+			// the copies are copying until they're described once
+			if stored := s.Find(KindImage, fmt.Sprintf("%v", image.(map[string]interface{})["ImageId"])); stored["ImageState"] == "copying" {
+				stored["ImageState"] = "active"
+				stored["Progress"] = "100"
+			}
+		}
+		resp["ImagesSet"] = images
+		return resp, nil
+	}
+	s.actions["ModifyImageAttribute"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		image, err := s.getImage(p.String("ImageId"))
+		if err != nil {
+			return nil, err
+		}
+		if v, ok := p["Name"]; ok {
+			image["Name"] = v
+		}
+		return nil, nil
+	}
+	s.actions["DescribeImageSharePermission"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		image, err := s.getImage(p.String("ImageId"))
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"SharePermissionSet": cloneValue(image["SharePermissionSet"])}, nil
+	}
+	s.actions["ModifyImageSharePermission"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		image, err := s.getImage(p.String("ImageId"))
+		if err != nil {
+			return nil, err
+		}
+		accounts := make(map[string]bool)
+		for _, v := range image["SharePermissionSet"].([]interface{}) {
+			accounts[v.(map[string]interface{})["AccountId"].(string)] = true
+		}
+		for _, id := range ids(p, "AccountId") {
+			switch p.String("Permission") {
+			case "share":
+				accounts[id] = true
+			case "cancel":
+				delete(accounts, id)
+			default:
+				return nil, badRequest("MalformedParameter", "the Permission %s is unsupported", p.String("Permission"))
+			}
+		}
+		set := []interface{}{}
+		for id := range accounts {
+			set = append(set, map[string]interface{}{"AccountId": id})
+		}
+		image["SharePermissionSet"] = set
+		return nil, nil
+	}
+	// CopyImage doesn't return the ids of the copies, the copies are named as DestinationImageName
+	s.actions["CopyImage"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		regions := ids(p, "DestinationRegion")
+		if len(regions) == 0 {
+			return nil, badRequest("MissingParameter", "DestinationRegion is required")
+		}
+		var sources []map[string]interface{}
+		for _, id := range ids(p, "ImageId") {
+			source, err := s.getImage(id)
+			if err != nil {
+				return nil, err
+			}
+			sources = append(sources, source)
+		}
+		for _, source := range sources {
+			for _, region := range regions {
+				copied := deepCopy(source)
+				copied["ImageId"] = "IMG-" + s.NewId()
+				copied["Name"] = p.String("DestinationImageName")
+				copied["OriginalImageId"] = source["ImageId"]
+				copied["ImageState"] = "copying"
+				copied["Progress"] = "0"
+				copied["SharePermissionSet"] = []interface{}{}
+				copied["Region"] = region
+				copied["CreationDate"] = now()
+				s.Insert(KindImage, copied)
+			}
+		}
+		return nil, nil
+	}
+	s.actions["RemoveImages"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		id := p.String("ImageId")
+		image, err := s.getImage(id)
+		if err != nil {
+			return nil, err
+		}
+		if len(image["SharePermissionSet"].([]interface{})) > 0 {
+			return nil, badRequest("ImageInUse", "the image %s is shared with other accounts", id)
+		}
+		if err = s.Remove(KindImage, id); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"ReturnSet": []interface{}{map[string]interface{}{"ImageId": id, "Return": true}}}, nil
+	}
+
+	// launch template, the versions are kept in the template and numbered from 1
+	s.actions["CreateLaunchTemplate"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		name := p.String("LaunchTemplateName")
+		if name == "" {
+			return nil, badRequest("MissingParameter", "LaunchTemplateName is required")
+		}
+		if len(s.Select(KindLaunchTemplate, fieldEquals("LaunchTemplateName", name))) > 0 {
+			return nil, badRequest("LaunchTemplateNameDuplicate", "the launch template %s already exists", name)
+		}
+		template := s.Insert(KindLaunchTemplate, map[string]interface{}{
+			"LaunchTemplateId":    s.NewId(),
+			"LaunchTemplateName":  name,
+			"LatestVersionNumber": 0,
+			"CreateTime":          now(),
+			"Versions":            []interface{}{},
+		})
+		addLaunchTemplateVersion(template, p)
+		return map[string]interface{}{"LaunchTemplateId": template["LaunchTemplateId"]}, nil
+	}
+	s.actions["CreateLaunchTemplateVersion"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		template, err := s.Get(KindLaunchTemplate, p.String("LaunchTemplateId"))
+		if err != nil {
+			return nil, err
+		}
+		version := addLaunchTemplateVersion(template, p)
+		return map[string]interface{}{"LaunchTemplateId": template["LaunchTemplateId"], "VersionNumber": version}, nil
+	}
+	s.actions["DescribeLaunchTemplates"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		resp := s.Describe(KindLaunchTemplate, p)
+		for _, template := range resp["LaunchTemplateSet"].([]interface{}) {
+			delete(template.(map[string]interface{}), "Versions")
+		}
+		return resp, nil
+	}
+	s.actions["DescribeLaunchTemplateVersions"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		template, err := s.Get(KindLaunchTemplate, p.String("LaunchTemplateId"))
+		if err != nil {
+			return nil, err
+		}
+		numbers := make(map[string]bool)
+		for _, number := range p.List("VersionNumber") {
+			numbers[number] = true
+		}
+		versions := []interface{}{}
+		for _, v := range template["Versions"].([]interface{}) {
+			version := v.(map[string]interface{})
+			if len(numbers) > 0 && !numbers[fmt.Sprintf("%v", version["VersionNumber"])] {
+				continue
+			}
+			versions = append(versions, deepCopy(version))
+		}
+		return map[string]interface{}{"LaunchTemplateVersionSet": versions}, nil
+	}
+	s.actions["DeleteLaunchTemplate"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		if err := s.Remove(KindLaunchTemplate, p.String("LaunchTemplateId")); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"Return": true}, nil
+	}
+
+	s.actions["DescribeInstanceTypeConfigs"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		var configs []interface{}
+		for _, t := range instanceTypes {
+			configs = append(configs, t.config())
+		}
+		return map[string]interface{}{"InstanceTypeConfigSet": configs}, nil
+	}
+	s.actions["DescribePrice"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		for _, t := range instanceTypes {
+			if t.name != p.String("InstanceType") {
+				continue
+			}
+			price, unit := t.price, "hour"
+			switch p.String("ChargeType") {
+			case "HourlyInstantSettlement":
+			case "Daily":
+				price, unit = t.price*24, "day"
+			default:
+				return nil, badRequest("InvalidParameter", "ChargeType %s is invalid", p.String("ChargeType"))
+			}
+			return map[string]interface{}{
+				"PriceInfo": map[string]interface{}{
+					"InstancePrice": map[string]interface{}{
+						"OriginalPrice": price,
+						"DiscountPrice": price,
+						"TradePrice":    price,
+						"PriceUnit":     unit,
+					},
+				},
+			}, nil
+		}
+		return nil, badRequest("InvalidParameter", "InstanceType %s is invalid", p.String("InstanceType"))
+	}
+}
+
+type instanceType struct {
+	name, family     string
+	cpu, memory, gpu int
+	zones            []string
+	// price is the hourly price
+	price float64
+}
+
+// instanceTypes are the instance types sold by the mock server, in no particular order as the api does
+var instanceTypes = []instanceType{
+	{name: "N3.4B", family: "N3", cpu: 4, memory: 8, zones: []string{"cn-beijing-6a", "cn-beijing-6b"}, price: 0.8},
+	{name: "N3.2B", family: "N3", cpu: 2, memory: 4, zones: []string{"cn-beijing-6a", "cn-beijing-6b"}, price: 0.4},
+	{name: "N3.1A", family: "N3", cpu: 1, memory: 1, zones: []string{"cn-beijing-6b"}, price: 0.15},
+	{name: "S6.2B", family: "S6", cpu: 2, memory: 4, zones: []string{"cn-beijing-6a"}, price: 0.35},
+	{name: "P3I.8B", family: "P3I", cpu: 8, memory: 64, gpu: 1, zones: []string{"cn-beijing-6a"}, price: 12},
+}
+
+func (t instanceType) config() map[string]interface{} {
+	zones := []interface{}{}
+	for _, zone := range t.zones {
+		zones = append(zones, map[string]interface{}{"AzCode": zone})
+	}
+	config := map[string]interface{}{
+		"InstanceType":          t.name,
+		"InstanceFamily":        t.family,
+		"InstanceFamilyName":    t.family + " general",
+		"CPU":                   t.cpu,
+		"Memory":                t.memory,
+		"GPU":                   t.gpu,
+		"NetworkInterfaceQuota": map[string]interface{}{"NetworkInterfaceCount": 4},
+		"PrivateIpQuota":        map[string]interface{}{"PrivateIpCount": 10},
+		"AvailabilityZoneSet":   zones,
+		"SystemDiskQuotaSet":    []interface{}{map[string]interface{}{"SystemDiskType": "Local_SSD"}},
+		"DataDiskQuotaSet": []interface{}{map[string]interface{}{
+			"DataDiskType":    "SSD3.0",
+			"DataDiskMinSize": 10,
+			"DataDiskMaxsize": 16000,
+			"DataDiskCount":   8,
+		}},
+	}
+	if t.gpu > 0 {
+		config["GPUspec"] = "NVIDIA T4"
+	}
+	return config
+}
+
+// checkSpot validates the spot parameters, the spot instances are billed by hour only
+func checkSpot(p Params) error {
+	switch p.String("SpotStrategy") {
+	case "":
+		if p.String("SpotPriceLimit") != "" {
+			return badRequest("MissingParameter", "SpotStrategy is required with SpotPriceLimit")
+		}
+		return nil
+	case "SpotAsPriceGo", "SpotWithPriceLimit":
+	default:
+		return badRequest("MalformedParameter", "the SpotStrategy %s is unsupported", p.String("SpotStrategy"))
+	}
+	if p.String("ChargeType") != "HourlyInstantSettlement" {
+		return badRequest("MalformedParameter", "the ChargeType %s doesn't support spot instances", p.String("ChargeType"))
+	}
+	if p.String("SpotStrategy") == "SpotWithPriceLimit" && p.String("SpotPriceLimit") == "" {
+		return badRequest("MissingParameter", "SpotPriceLimit is required with SpotWithPriceLimit")
+	}
+	return nil
+}
+
+// getImage returns the image of the request region by id, a not found error is returned if it doesn't exist.
+func (s *Server) getImage(id string) (map[string]interface{}, error) {
+	image := s.Find(KindImage, id)
+	if image == nil || image["Region"] != s.Region() {
+		return nil, notFound(KindImage, id)
+	}
+	return image, nil
+}
+
+func (s *Server) runInstance(p Params, subnet map[string]interface{}, securityGroups []interface{}, idx int) (map[string]interface{}, error) {
+	ip := p.String("PrivateIpAddress")
+	if ip == "" || idx > 0 {
+		var err error
+		if ip, err = s.allocateIp(subnet); err != nil {
+			return nil, err
+		}
+	}
+	instanceId := s.NewId()
+	name := p.String("InstanceName")
+	if name == "" {
+		name = "ksc-instance"
+	}
+	if batchNamePattern.MatchString(name) {
+		name = expandBatchName(name, idx)
+	} else if idx > 0 {
+		name = name + "-" + strconv.Itoa(idx+1)
+	}
+
+	ni := s.Insert(KindNetworkInterface, map[string]interface{}{
+		"NetworkInterfaceId":   s.NewId(),
+		"NetworkInterfaceType": "primary",
+		"NetworkInterfaceName": "primary",
+		"InstanceId":           instanceId,
+		"InstanceType":         "kec",
+		"SubnetId":             subnet["SubnetId"],
+		"VpcId":                subnet["VpcId"],
+		"PrivateIpAddress":     ip,
+		"MacAddress":           fmt.Sprintf("fa:16:3e:%02x:%02x:%02x", s.seq>>16&0xff, s.seq>>8&0xff, s.seq&0xff),
+		"SecurityGroupSet":     securityGroups,
+		"DNS1":                 subnet["Dns1"],
+		"DNS2":                 subnet["Dns2"],
+	})
+
+	systemDisk := map[string]interface{}{
+		"DiskType": "Local_SSD",
+		"DiskSize": 20,
+	}
+	if v := p.String("SystemDisk.DiskType"); v != "" {
+		systemDisk["DiskType"] = v
+	}
+	if v := p.Int("SystemDisk.DiskSize", 0); v > 0 {
+		systemDisk["DiskSize"] = v
+	}
+	dataDisks := []interface{}{}
+	for i := 1; p.String("DataDisk."+strconv.Itoa(i)+".Type") != ""; i++ {
+		prefix := "DataDisk." + strconv.Itoa(i) + "."
+		deleteWithInstance, _ := strconv.ParseBool(p.String(prefix + "DeleteWithInstance"))
+		dataDisks = append(dataDisks, map[string]interface{}{
+			"DiskId":             s.NewId(),
+			"DiskType":           p.String(prefix + "Type"),
+			"DiskSize":           p.Int(prefix+"Size", 0),
+			"DeleteWithInstance": deleteWithInstance,
+		})
+	}
+	keys := []interface{}{}
+	for _, k := range p.List("KeyId") {
+		keys = append(keys, k)
+	}
+
+	instance := map[string]interface{}{
+		"InstanceId":       instanceId,
+		"InstanceName":     name,
+		"HostName":         expandBatchName(p.String("HostName"), idx),
+		"ImageId":          p.String("ImageId"),
+		"InstanceType":     p.String("InstanceType"),
+		"ChargeType":       p.String("ChargeType"),
+		"ProjectId":        DefaultProjectId,
+		"SubnetId":         subnet["SubnetId"],
+		"VpcId":            subnet["VpcId"],
+		"PrivateIpAddress": ip,
+		"CreationDate":     now(),
+		"InstanceConfigure": map[string]interface{}{
+			"VCPU":       2,
+			"GPU":        0,
+			"MemoryGb":   4,
+			"DataDiskGb": p.Int("DataDiskGb", 0),
+		},
+		"InstanceState":       map[string]interface{}{"Name": InstanceStateActive},
+		"NetworkInterfaceSet": []interface{}{deepCopy(ni)},
+		"SystemDisk":          systemDisk,
+		"DataDisks":           dataDisks,
+		"KeySet":              keys,
+	}
+	if v := p.String("ProjectId"); v != "" {
+		instance["ProjectId"] = v
+	}
+	if v := p.String("UserData"); v != "" {
+		instance["UserData"] = v
+	}
+	s.Insert(KindInstance, instance)
+	for _, disk := range dataDisks {
+		if m := disk.(map[string]interface{}); m["DiskType"] != "Local_SSD" {
+			s.createInstanceVolume(instanceId, m)
+		}
+	}
+
+	if tags := tagParams(p, "Tag.%d.Key", "Tag.%d.Value"); len(tags) > 0 {
+		s.SetTags(instanceId, tags)
+	}
+	return instance, nil
+}
+
+// batchNamePattern is the ordered suffix `[begin,digits]` of the names of instances launched in batch
+var batchNamePattern = regexp.MustCompile(`\[(\d+),(\d+)\]`)
+
+// expandBatchName replaces the ordered suffix with the zero padded number of the idx-th instance
+func expandBatchName(name string, idx int) string {
+	return batchNamePattern.ReplaceAllStringFunc(name, func(suffix string) string {
+		m := batchNamePattern.FindStringSubmatch(suffix)
+		begin, _ := strconv.Atoi(m[1])
+		digits, _ := strconv.Atoi(m[2])
+		return fmt.Sprintf("%0*d", digits, begin+idx)
+	})
+}
+
+func instanceStateAction(state string) ActionFunc {
+	return func(s *Server, p Params) (map[string]interface{}, error) {
+		var result []interface{}
+		for _, id := range ids(p, "InstanceId") {
+			instance, err := s.Get(KindInstance, id)
+			if err != nil {
+				return nil, err
+			}
+			instance["InstanceState"] = map[string]interface{}{"Name": state}
+			result = append(result, map[string]interface{}{"InstanceId": id, "Return": true})
+		}
+		return map[string]interface{}{"InstancesSet": result}, nil
+	}
+}
+
+// addLaunchTemplateVersion adds the instance configs of the parameters as the latest version of the template
+func addLaunchTemplateVersion(template map[string]interface{}, p Params) int {
+	number := template["LatestVersionNumber"].(int) + 1
+	version := p.Nested()
+	delete(version, "LaunchTemplateName")
+	if disk, ok := version["SystemDisk"].(map[string]interface{}); ok {
+		convert(Kind{Ints: []string{"DiskSize"}}, disk)
+	}
+	if disks, ok := version["DataDisk"].([]interface{}); ok {
+		for _, disk := range disks {
+			convert(Kind{Ints: []string{"Size"}, Bools: []string{"DeleteWithInstance"}}, disk.(map[string]interface{}))
+		}
+	}
+	version["LaunchTemplateId"] = template["LaunchTemplateId"]
+	version["VersionNumber"] = number
+	version["CreateTime"] = now()
+	template["Versions"] = append(template["Versions"].([]interface{}), version)
+	template["LatestVersionNumber"] = number
+	return number
+}
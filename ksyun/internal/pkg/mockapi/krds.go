@@ -0,0 +1,78 @@
+package mockapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// KindDBInstance is the kind of krds instances
+const KindDBInstance = "DBInstance"
+
+func registerKrdsActions(s *Server) {
+	s.register(Kind{Name: KindDBInstance, SetName: "Instances", IdField: "DBInstanceIdentifier", Ints: []string{"Port", "ProjectId"}})
+
+	s.actions["CreateDBInstance"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		class := strings.Split(p.String("DBInstanceClass"), "|")
+		if len(class) != 2 {
+			return nil, badRequest("InvalidParameter", "DBInstanceClass %s is invalid", p.String("DBInstanceClass"))
+		}
+		if _, err := s.Get(KindSubnet, p.String("SubnetId")); err != nil {
+			return nil, err
+		}
+		item := map[string]interface{}{
+			"DBInstanceIdentifier": s.NewId(),
+			"DBInstanceClass": map[string]interface{}{
+				"Ram":  toInt(strings.TrimPrefix(class[0], "db.ram.")),
+				"Disk": toInt(strings.TrimPrefix(class[1], "db.disk.")),
+			},
+			"DBInstanceStatus":       "ACTIVE",
+			"Region":                 DefaultRegion,
+			"Port":                   3306,
+			"ProjectId":              0,
+			"InstanceCreateTime":     now(),
+			"MasterAvailabilityZone": p.String("AvailabilityZone.1"),
+			"SlaveAvailabilityZone":  p.String("AvailabilityZone.2"),
+			"Vip":                    fmt.Sprintf("10.0.0.%d", s.seq%250+1),
+		}
+		for _, k := range []string{"DBInstanceName", "DBInstanceType", "Engine", "EngineVersion", "MasterUserName",
+			"VpcId", "SubnetId", "BillType", "SecurityGroupId", "PreferredBackupTime"} {
+			item[k] = p.String(k)
+		}
+		if v := p.String("ProjectId"); v != "" {
+			item["ProjectId"] = v
+		}
+		item = s.Insert(KindDBInstance, item)
+		return map[string]interface{}{
+			"Data": map[string]interface{}{"DBInstance": deepCopy(item)},
+		}, nil
+	}
+	s.actions["DescribeDBInstances"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		match := func(map[string]interface{}) bool { return true }
+		if id := p.String("DBInstanceIdentifier"); id != "" {
+			match = fieldEquals("DBInstanceIdentifier", id)
+		}
+		instances := []interface{}{}
+		for _, item := range s.Select(KindDBInstance, match) {
+			instances = append(instances, deepCopy(item))
+		}
+		return map[string]interface{}{
+			"Data": map[string]interface{}{"Instances": instances, "TotalCount": len(instances)},
+		}, nil
+	}
+	s.actions["DeleteDBInstance"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return nil, s.Remove(KindDBInstance, p.String("DBInstanceIdentifier"))
+	}
+	s.actions["DescribeDBInstanceParameters"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		if _, err := s.Get(KindDBInstance, p.String("DBInstanceIdentifier")); err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"Data": map[string]interface{}{"Parameters": map[string]interface{}{}},
+		}, nil
+	}
+	s.actions["DescribeEngineDefaultParameters"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return map[string]interface{}{
+			"Data": map[string]interface{}{"Parameters": map[string]interface{}{}},
+		}, nil
+	}
+}
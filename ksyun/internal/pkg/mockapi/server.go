@@ -0,0 +1,665 @@
+// Package mockapi provides an in-process fake of the Ksyun open api, it keeps the resources in memory
+// and implements the stateful CRUD of the core VPC, EIP, KEC, SLB, CEN and KRDS actions, so that the provider
+// can be exercised without a real account.
+//
+// The provider is pointed to the server by the domain settings:
+//
+//	provider "ksyun" {
+//	  domain         = server.Domain()
+//	  ignore_service = true
+//	}
+package mockapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Params holds the flattened parameters of a request, such as DataDisk.1.Size
+type Params map[string]interface{}
+
+// ActionFunc handles an action, it's called with the server locked.
+type ActionFunc func(s *Server, p Params) (map[string]interface{}, error)
+
+// Error is the error responded by the server
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func badRequest(code, format string, args ...interface{}) *Error {
+	return &Error{StatusCode: http.StatusBadRequest, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func notFound(kind, id string) *Error {
+	return &Error{
+		StatusCode: http.StatusNotFound,
+		Code:       kind + "NotFound",
+		Message:    fmt.Sprintf("The %s '%s' is not found", kind, id),
+	}
+}
+
+// Kind describes a type of resources kept by the server
+type Kind struct {
+	// Name of the kind, such as Vpc
+	Name string
+	// SetName is the field holding the resources in the describe response, such as VpcSet
+	SetName string
+	// IdField is the field of resource id, such as VpcId
+	IdField string
+	// Ints and Bools are the top level fields converted from the string parameters
+	Ints  []string
+	Bools []string
+}
+
+type table struct {
+	kind  Kind
+	items []map[string]interface{}
+}
+
+// Server is the fake api server
+type Server struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	seq     int
+	tables  map[string]*table
+	tags    map[string]map[string]string
+	actions map[string]ActionFunc
+	// ipSeq is the last allocated host of subnets
+	ipSeq map[string]int
+	// attributes of load balancers
+	attributes map[string]map[string]string
+	// region is the region of the request being served, which is read from the credential scope of signature
+	region string
+}
+
+// NewServer starts a fake api server, the caller should Close it when done.
+func NewServer() *Server {
+	s := NewUnstartedServer()
+	s.Start()
+	return s
+}
+
+// NewUnstartedServer returns a fake api server which is not started yet, so that more
+// actions can be registered by Handle before Start.
+func NewUnstartedServer() *Server {
+	s := &Server{
+		tables:  make(map[string]*table),
+		tags:    make(map[string]map[string]string),
+		actions: make(map[string]ActionFunc),
+		ipSeq:   make(map[string]int),
+
+		attributes: make(map[string]map[string]string),
+	}
+	s.Server = httptest.NewUnstartedServer(s)
+	registerCommonActions(s)
+	registerVpcActions(s)
+	registerEipActions(s)
+	registerKecActions(s)
+	registerEbsActions(s)
+	registerSlbActions(s)
+	registerCenActions(s)
+	registerKrdsActions(s)
+	return s
+}
+
+// Domain returns the host of server, which is used as the domain of provider
+func (s *Server) Domain() string {
+	u, _ := url.Parse(s.URL)
+	return u.Host
+}
+
+// Handle registers the handler of action, the existing handler is replaced.
+func (s *Server) Handle(action string, fn ActionFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.actions[action] = fn
+}
+
+// Action returns the handler of action, it's useful to wrap the default handler in tests.
+func (s *Server) Action(action string) ActionFunc {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.actions[action]
+}
+
+// Register registers a kind of resources
+func (s *Server) Register(kind Kind) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.register(kind)
+}
+
+func (s *Server) register(kind Kind) {
+	if _, ok := s.tables[kind.Name]; !ok {
+		s.tables[kind.Name] = &table{kind: kind}
+	}
+}
+
+// Items returns a copy of the resources of kind, it's useful to check the state in tests.
+func (s *Server) Items(kind string) []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.tables[kind]
+	if t == nil {
+		return nil
+	}
+	var items []map[string]interface{}
+	for _, item := range t.items {
+		items = append(items, deepCopy(item))
+	}
+	return items
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.seq++
+	requestId := fmt.Sprintf("mock-%08d", s.seq)
+	s.mu.Unlock()
+
+	p, err := parseParams(r)
+	if err != nil {
+		writeError(w, requestId, badRequest("MalformedParameter", "%s", err))
+		return
+	}
+	action := fmt.Sprintf("%v", p["Action"])
+	delete(p, "Action")
+	delete(p, "Version")
+
+	s.mu.Lock()
+	s.region = signedRegion(r)
+	fn, ok := s.actions[action]
+	var resp map[string]interface{}
+	if ok {
+		if dryRun, _ := toBool(p["DryRun"]).(bool); dryRun {
+			delete(p, "DryRun")
+			err = s.dryRun(fn, p)
+		} else {
+			resp, err = fn(s, p)
+		}
+	}
+	s.region = ""
+	s.mu.Unlock()
+
+	if !ok {
+		writeError(w, requestId, badRequest("UnsupportedOperation", "action %s is unsupported by the mock server", action))
+		return
+	}
+	if err != nil {
+		e, ok := err.(*Error)
+		if !ok {
+			e = &Error{StatusCode: http.StatusInternalServerError, Code: "InternalError", Message: err.Error()}
+		}
+		writeError(w, requestId, e)
+		return
+	}
+	if resp == nil {
+		resp = map[string]interface{}{"Return": true}
+	}
+	resp["RequestId"] = requestId
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// dryRun runs the action and rolls back the changes, it responds 412 as the real api does
+// when the request would have succeeded.
+func (s *Server) dryRun(fn ActionFunc, p Params) error {
+	tables := make(map[string][]map[string]interface{})
+	for name, t := range s.tables {
+		tables[name] = cloneValue(t.items).([]map[string]interface{})
+	}
+	tags := cloneValue(s.tags).(map[string]map[string]string)
+	ipSeq := make(map[string]int)
+	for k, v := range s.ipSeq {
+		ipSeq[k] = v
+	}
+	attributes := cloneValue(s.attributes).(map[string]map[string]string)
+
+	_, err := fn(s, p)
+
+	for name, items := range tables {
+		s.tables[name].items = items
+	}
+	s.tags, s.ipSeq, s.attributes = tags, ipSeq, attributes
+	if err != nil {
+		return err
+	}
+	return &Error{
+		StatusCode: http.StatusPreconditionFailed,
+		Code:       "DryRunOperation",
+		Message:    "Request would have succeeded, but DryRun flag is set.",
+	}
+}
+
+func writeError(w http.ResponseWriter, requestId string, e *Error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.StatusCode)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"Error":     map[string]interface{}{"Code": e.Code, "Message": e.Message},
+		"RequestID": requestId,
+	})
+}
+
+// signedRegion returns the region in the credential scope of the v4 signature, such as
+// Credential=AK/20060102/cn-beijing-6/ebs/aws4_request
+func signedRegion(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	i := strings.Index(auth, "Credential=")
+	if i < 0 {
+		return ""
+	}
+	scope := strings.Split(strings.SplitN(auth[i+len("Credential="):], ",", 2)[0], "/")
+	if len(scope) < 3 {
+		return ""
+	}
+	return scope[2]
+}
+
+// Region returns the region of the request being served, DefaultRegion is returned if the action
+// is called out of a request, such as by tests.
+func (s *Server) Region() string {
+	if s.region == "" {
+		return DefaultRegion
+	}
+	return s.region
+}
+
+// parseParams reads the parameters from query, form body or json body
+func parseParams(r *http.Request) (Params, error) {
+	p := make(Params)
+	for k, v := range r.URL.Query() {
+		p[k] = v[0]
+	}
+	if r.Body == nil {
+		return p, nil
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return p, err
+	}
+	if len(body) == 0 {
+		return p, nil
+	}
+	if strings.Contains(r.Header.Get("Content-Type"), "json") {
+		var m map[string]interface{}
+		if err = json.Unmarshal(body, &m); err != nil {
+			return p, err
+		}
+		for k, v := range m {
+			p[k] = v
+		}
+		return p, nil
+	}
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return p, err
+	}
+	for k, v := range values {
+		p[k] = v[0]
+	}
+	return p, nil
+}
+
+// String returns the parameter as string
+func (p Params) String(key string) string {
+	if v, ok := p[key]; ok && v != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return ""
+}
+
+// Int returns the parameter as int, def is returned if it's absent or invalid
+func (p Params) Int(key string, def int) int {
+	if v, err := strconv.Atoi(p.String(key)); err == nil {
+		return v
+	}
+	return def
+}
+
+// List returns the values of the parameters key.1, key.2 ... in order
+func (p Params) List(key string) []string {
+	var result []string
+	for i := 1; ; i++ {
+		v, ok := p[key+"."+strconv.Itoa(i)]
+		if !ok {
+			break
+		}
+		result = append(result, fmt.Sprintf("%v", v))
+	}
+	return result
+}
+
+// Nested returns the parameters as nested maps and slices, DataDisk.1.Size is returned as
+// {"DataDisk": [{"Size": ...}]}
+func (p Params) Nested() map[string]interface{} {
+	root := make(map[string]interface{})
+	keys := make([]string, 0, len(p))
+	for k := range p {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts := strings.Split(k, ".")
+		node := root
+		for i, part := range parts {
+			if i == len(parts)-1 {
+				node[part] = p[k]
+				break
+			}
+			next, ok := node[part].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				node[part] = next
+			}
+			node = next
+		}
+	}
+	return toSlices(root).(map[string]interface{})
+}
+
+// toSlices converts the maps keyed by index into slices
+func toSlices(v interface{}) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	indexed := len(m) > 0
+	for k, item := range m {
+		m[k] = toSlices(item)
+		if _, err := strconv.Atoi(k); err != nil {
+			indexed = false
+		}
+	}
+	if !indexed {
+		return m
+	}
+	idx := make([]int, 0, len(m))
+	for k := range m {
+		i, _ := strconv.Atoi(k)
+		idx = append(idx, i)
+	}
+	sort.Ints(idx)
+	result := make([]interface{}, 0, len(idx))
+	for _, i := range idx {
+		result = append(result, m[strconv.Itoa(i)])
+	}
+	return result
+}
+
+// NewId returns a new resource id in uuid format
+func (s *Server) NewId() string {
+	s.seq++
+	return fmt.Sprintf("%08x-0000-4000-8000-%012x", time.Now().Unix()&0xffffffff, s.seq)
+}
+
+func now() string {
+	return time.Now().UTC().Format("2006-01-02 15:04:05")
+}
+
+// Insert adds the resource of kind, the item is converted by the Ints and Bools of kind.
+func (s *Server) Insert(kind string, item map[string]interface{}) map[string]interface{} {
+	t := s.tables[kind]
+	convert(t.kind, item)
+	t.items = append(t.items, item)
+	return item
+}
+
+// Find returns the resource of kind by id, nil is returned if it doesn't exist.
+func (s *Server) Find(kind, id string) map[string]interface{} {
+	t := s.tables[kind]
+	for _, item := range t.items {
+		if fmt.Sprintf("%v", item[t.kind.IdField]) == id {
+			return item
+		}
+	}
+	return nil
+}
+
+// Get returns the resource of kind by id, a not found error is returned if it doesn't exist.
+func (s *Server) Get(kind, id string) (map[string]interface{}, error) {
+	item := s.Find(kind, id)
+	if item == nil {
+		return nil, notFound(kind, id)
+	}
+	return item, nil
+}
+
+// Select returns the resources of kind matching fn
+func (s *Server) Select(kind string, fn func(item map[string]interface{}) bool) []map[string]interface{} {
+	var result []map[string]interface{}
+	for _, item := range s.tables[kind].items {
+		if fn(item) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// Remove deletes the resource of kind by id, a not found error is returned if it doesn't exist.
+func (s *Server) Remove(kind, id string) error {
+	t := s.tables[kind]
+	for i, item := range t.items {
+		if fmt.Sprintf("%v", item[t.kind.IdField]) == id {
+			t.items = append(t.items[:i], t.items[i+1:]...)
+			delete(s.tags, id)
+			delete(s.attributes, id)
+			return nil
+		}
+	}
+	return notFound(kind, id)
+}
+
+// Create inserts a resource of kind from the parameters, the parameters are nested and
+// merged over defaults.
+func (s *Server) Create(kind string, p Params, defaults map[string]interface{}) map[string]interface{} {
+	t := s.tables[kind]
+	item := make(map[string]interface{})
+	for k, v := range defaults {
+		item[k] = v
+	}
+	for k, v := range p.Nested() {
+		item[k] = v
+	}
+	if _, ok := item[t.kind.IdField]; !ok {
+		item[t.kind.IdField] = s.NewId()
+	}
+	if _, ok := item["CreateTime"]; !ok {
+		item["CreateTime"] = now()
+	}
+	return s.Insert(kind, item)
+}
+
+// Modify updates the resource of kind identified by the IdField parameter
+func (s *Server) Modify(kind string, p Params) (map[string]interface{}, error) {
+	t := s.tables[kind]
+	item, err := s.Get(kind, p.String(t.kind.IdField))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range p.Nested() {
+		if k == t.kind.IdField {
+			continue
+		}
+		item[k] = v
+	}
+	convert(t.kind, item)
+	return item, nil
+}
+
+var listParam = regexp.MustCompile(`^([A-Za-z]+)\.\d+$`)
+var filterName = regexp.MustCompile(`^Filter\.(\d+)\.Name$`)
+
+// Describe returns the resources of kind matching the parameters:
+//   - Field.N matches the resources whose Field is one of the values
+//   - Filter.N.Name and Filter.N.Value.M match the resources by the field of filter name, vpc-id matches VpcId
+//   - MaxResults and NextToken page the resources, the NextToken is the 1-based offset
+//
+// The conditions on fields absent in the resources are ignored.
+func (s *Server) Describe(kind string, p Params) map[string]interface{} {
+	t := s.tables[kind]
+	conditions := make(map[string][]string)
+	for k, v := range p {
+		if m := listParam.FindStringSubmatch(k); m != nil {
+			conditions[m[1]] = append(conditions[m[1]], fmt.Sprintf("%v", v))
+		}
+		if m := filterName.FindStringSubmatch(k); m != nil {
+			field := filterField(fmt.Sprintf("%v", v))
+			conditions[field] = append(conditions[field], p.List("Filter."+m[1]+".Value")...)
+		}
+	}
+
+	var matched []interface{}
+	for _, item := range t.items {
+		if matchConditions(t.kind, item, conditions) {
+			matched = append(matched, deepCopy(item))
+		}
+	}
+
+	resp := map[string]interface{}{"TotalCount": len(matched)}
+	offset := p.Int("NextToken", 1) - 1
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(matched) {
+		offset = len(matched)
+	}
+	matched = matched[offset:]
+	if limit := p.Int("MaxResults", 0); limit > 0 && limit < len(matched) {
+		matched = matched[:limit]
+		resp["NextToken"] = strconv.Itoa(offset + limit + 1)
+	}
+	if matched == nil {
+		matched = []interface{}{}
+	}
+	resp[t.kind.SetName] = matched
+	return resp
+}
+
+func matchConditions(kind Kind, item map[string]interface{}, conditions map[string][]string) bool {
+	for field, values := range conditions {
+		v, ok := item[field]
+		if !ok {
+			if field == kind.IdField {
+				return false
+			}
+			continue
+		}
+		found := false
+		for _, value := range values {
+			if fmt.Sprintf("%v", v) == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// filterField converts the filter name to field, such as vpc-id to VpcId
+func filterField(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "-") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+func convert(kind Kind, item map[string]interface{}) {
+	for _, k := range kind.Ints {
+		if v, ok := item[k]; ok {
+			item[k] = toInt(v)
+		}
+	}
+	for _, k := range kind.Bools {
+		if v, ok := item[k]; ok {
+			item[k] = toBool(v)
+		}
+	}
+}
+
+func toInt(v interface{}) interface{} {
+	switch value := v.(type) {
+	case string:
+		if i, err := strconv.Atoi(value); err == nil {
+			return i
+		}
+	case float64:
+		return int(value)
+	}
+	return v
+}
+
+func toBool(v interface{}) interface{} {
+	if value, ok := v.(string); ok {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return v
+}
+
+func deepCopy(item map[string]interface{}) map[string]interface{} {
+	b, _ := json.Marshal(item)
+	var result map[string]interface{}
+	_ = json.Unmarshal(b, &result)
+	return result
+}
+
+// cloneValue copies the maps and slices of v recursively, the other values are kept as they are
+func cloneValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for k, e := range value {
+			result[k] = cloneValue(e)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, e := range value {
+			result[i] = cloneValue(e)
+		}
+		return result
+	case []map[string]interface{}:
+		result := make([]map[string]interface{}, len(value))
+		for i, e := range value {
+			result[i] = cloneValue(e).(map[string]interface{})
+		}
+		return result
+	case map[string]map[string]string:
+		result := make(map[string]map[string]string, len(value))
+		for k, e := range value {
+			m := make(map[string]string, len(e))
+			for ek, ev := range e {
+				m[ek] = ev
+			}
+			result[k] = m
+		}
+		return result
+	default:
+		return v
+	}
+}
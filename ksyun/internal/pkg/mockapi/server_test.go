@@ -0,0 +1,166 @@
+package mockapi
+
+import (
+	"testing"
+
+	"github.com/KscSDK/ksc-sdk-go/ksc"
+	"github.com/KscSDK/ksc-sdk-go/ksc/utils"
+	"github.com/KscSDK/ksc-sdk-go/service/eip"
+	"github.com/KscSDK/ksc-sdk-go/service/kec"
+	"github.com/KscSDK/ksc-sdk-go/service/slb"
+	"github.com/KscSDK/ksc-sdk-go/service/vpc"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+)
+
+func testClients(s *Server) (*vpc.Vpc, *eip.Eip, *kec.Kec, *slb.Slb) {
+	sess := ksc.NewClient("ak", "sk")
+	cfg := &ksc.Config{Region: aws.String("cn-beijing-6")}
+	url := &utils.UrlInfo{CustomerDomain: s.Domain(), CustomerDomainIgnoreService: true}
+	return vpc.SdkNew(sess, cfg, url), eip.SdkNew(sess, cfg, url), kec.SdkNew(sess, cfg, url), slb.SdkNew(sess, cfg, url)
+}
+
+func TestVpcAndInstance(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	vpcConn, eipConn, kecConn, slbConn := testClients(s)
+
+	resp, err := vpcConn.CreateVpc(&map[string]interface{}{"VpcName": "tf-vpc", "CidrBlock": "10.7.0.0/16"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	vpcId := (*resp)["Vpc"].(map[string]interface{})["VpcId"].(string)
+
+	resp, err = vpcConn.CreateSubnet(&map[string]interface{}{
+		"VpcId":                vpcId,
+		"SubnetName":           "tf-subnet",
+		"CidrBlock":            "10.7.0.0/24",
+		"SubnetType":           "Normal",
+		"AvailabilityZoneName": "cn-beijing-6a",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	subnetId := (*resp)["Subnet"].(map[string]interface{})["SubnetId"].(string)
+
+	// describe by id and by filter
+	resp, err = vpcConn.DescribeSubnets(&map[string]interface{}{"SubnetId.1": subnetId})
+	if err != nil || len((*resp)["SubnetSet"].([]interface{})) != 1 {
+		t.Fatalf("Expected the subnet, got %v %v", resp, err)
+	}
+	resp, err = vpcConn.DescribeSubnets(&map[string]interface{}{"Filter.1.Name": "vpc-id", "Filter.1.Value.1": "another"})
+	if err != nil || len((*resp)["SubnetSet"].([]interface{})) != 0 {
+		t.Fatalf("Expected no subnet, got %v %v", resp, err)
+	}
+
+	resp, err = kecConn.RunInstances(&map[string]interface{}{
+		"ImageId":         "img-1",
+		"InstanceType":    "N3.2B",
+		"SubnetId":        subnetId,
+		"InstanceName":    "tf-instance",
+		"DataDisk.1.Type": "SSD3.0",
+		"DataDisk.1.Size": "50",
+		"MaxCount":        "1",
+		"MinCount":        "1",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	instanceId := (*resp)["InstancesSet"].([]interface{})[0].(map[string]interface{})["InstanceId"].(string)
+
+	resp, err = kecConn.DescribeInstances(&map[string]interface{}{"InstanceId.1": instanceId, "ProjectId.1": DefaultProjectId})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	instance := (*resp)["InstancesSet"].([]interface{})[0].(map[string]interface{})
+	if instance["PrivateIpAddress"] != "10.7.0.2" || instance["InstanceState"].(map[string]interface{})["Name"] != InstanceStateActive {
+		t.Errorf("Unexpected instance %v", instance)
+	}
+	if disks := instance["DataDisks"].([]interface{}); len(disks) != 1 || disks[0].(map[string]interface{})["DiskSize"] != float64(50) {
+		t.Errorf("Unexpected data disks %v", disks)
+	}
+
+	resp, err = eipConn.AllocateAddress(&map[string]interface{}{"BandWidth": "5"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	allocationId := (*resp)["AllocationId"].(string)
+	if _, err = eipConn.AssociateAddress(&map[string]interface{}{
+		"AllocationId": allocationId,
+		"InstanceId":   instanceId,
+		"InstanceType": "Ipfwd",
+	}); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if _, err = eipConn.ReleaseAddress(&map[string]interface{}{"AllocationId": allocationId}); err == nil {
+		t.Errorf("Expected error when releasing an associated address")
+	}
+
+	resp, err = slbConn.CreateLoadBalancer(&map[string]interface{}{"VpcId": vpcId, "LoadBalancerName": "tf-lb"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	lbId := (*resp)["LoadBalancerId"].(string)
+	resp, err = slbConn.DescribeLoadBalancers(&map[string]interface{}{"LoadBalancerId.1": lbId})
+	if err != nil || len((*resp)["LoadBalancerDescriptions"].([]interface{})) != 1 {
+		t.Fatalf("Expected the load balancer, got %v %v", resp, err)
+	}
+
+	// the subnet can't be deleted until the instance is terminated
+	if _, err = vpcConn.DeleteSubnet(&map[string]interface{}{"SubnetId": subnetId}); err == nil {
+		t.Errorf("Expected error when deleting a subnet in use")
+	}
+	if _, err = kecConn.TerminateInstances(&map[string]interface{}{"InstanceId.1": instanceId}); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if _, err = vpcConn.DeleteSubnet(&map[string]interface{}{"SubnetId": subnetId}); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	_, err = vpcConn.DeleteSubnet(&map[string]interface{}{"SubnetId": subnetId})
+	if e, ok := err.(awserr.RequestFailure); !ok || e.StatusCode() != 404 {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+func TestDescribePaging(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	_, eipConn, _, _ := testClients(s)
+
+	for i := 0; i < 5; i++ {
+		if _, err := eipConn.AllocateAddress(&map[string]interface{}{}); err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+	}
+	var total int
+	for token := "1"; token != ""; {
+		resp, err := eipConn.DescribeAddresses(&map[string]interface{}{"MaxResults": "2", "NextToken": token})
+		if err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+		total += len((*resp)["AddressesSet"].([]interface{}))
+		token, _ = (*resp)["NextToken"].(string)
+	}
+	if total != 5 {
+		t.Errorf("Expected 5 addresses, got %d", total)
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	vpcConn, eipConn, _, _ := testClients(s)
+
+	_, err := eipConn.AllocateAddress(&map[string]interface{}{"BandWidth": "5", "DryRun": true})
+	if e, ok := err.(awserr.RequestFailure); !ok || e.StatusCode() != 412 {
+		t.Errorf("Expected the dry run error, got %v", err)
+	}
+	if items := s.Items(KindAddress); len(items) != 0 {
+		t.Errorf("Expected no address created by dry run, got %v", items)
+	}
+
+	_, err = vpcConn.CreateSubnet(&map[string]interface{}{"VpcId": "another", "CidrBlock": "10.7.0.0/24", "DryRun": true})
+	if e, ok := err.(awserr.RequestFailure); !ok || e.StatusCode() != 404 {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
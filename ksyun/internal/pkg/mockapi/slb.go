@@ -0,0 +1,164 @@
+package mockapi
+
+import "fmt"
+
+// the kinds of slb resources
+const (
+	KindLoadBalancer = "LoadBalancer"
+	KindListener     = "Listener"
+	KindHealthCheck  = "HealthCheck"
+)
+
+func registerSlbActions(s *Server) {
+	s.register(Kind{Name: KindLoadBalancer, SetName: "LoadBalancerDescriptions", IdField: "LoadBalancerId",
+		Bools: []string{"IsWaf"}})
+	s.register(Kind{Name: KindListener, SetName: "ListenerSet", IdField: "ListenerId",
+		Ints: []string{"ListenerPort", "SessionPersistencePeriod"}, Bools: []string{"EnableHttp2"}})
+	s.register(Kind{Name: KindHealthCheck, SetName: "HealthCheckSet", IdField: "HealthCheckId",
+		Ints: []string{"HealthyThreshold", "Interval", "Timeout", "UnhealthyThreshold"}, Bools: []string{"IsDefaultHostName"}})
+
+	// load balancer
+	s.actions["CreateLoadBalancer"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		if _, err := s.Get(KindVpc, p.String("VpcId")); err != nil {
+			return nil, err
+		}
+		item := s.Create(KindLoadBalancer, p, map[string]interface{}{
+			"Type":              "public",
+			"LoadBalancerState": "start",
+			"ProjectId":         DefaultProjectId,
+			"IpVersion":         "ipv4",
+			"IsWaf":             false,
+			"LbType":            "classic",
+		})
+		if item["Type"] == "internal" {
+			subnet, err := s.Get(KindSubnet, p.String("SubnetId"))
+			if err != nil {
+				_ = s.Remove(KindLoadBalancer, fmt.Sprintf("%v", item["LoadBalancerId"]))
+				return nil, err
+			}
+			if item["PrivateIpAddress"] == nil {
+				if item["PrivateIpAddress"], err = s.allocateIp(subnet); err != nil {
+					_ = s.Remove(KindLoadBalancer, fmt.Sprintf("%v", item["LoadBalancerId"]))
+					return nil, err
+				}
+			}
+			item["PublicIp"] = item["PrivateIpAddress"]
+		} else {
+			item["PublicIp"] = fmt.Sprintf("198.51.100.%d", s.seq%250+1)
+		}
+		item["State"] = "associate"
+		return map[string]interface{}{
+			"LoadBalancerId": item["LoadBalancerId"],
+			"PublicIp":       item["PublicIp"],
+		}, nil
+	}
+	s.actions["DescribeLoadBalancers"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return s.Describe(KindLoadBalancer, p), nil
+	}
+	s.actions["ModifyLoadBalancer"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		_, err := s.Modify(KindLoadBalancer, p)
+		return nil, err
+	}
+	s.actions["DeleteLoadBalancer"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		id := p.String("LoadBalancerId")
+		if listeners := s.Select(KindListener, fieldEquals("LoadBalancerId", id)); len(listeners) > 0 {
+			return nil, badRequest("DependencyViolation", "the load balancer %s still has %d listeners", id, len(listeners))
+		}
+		return nil, s.Remove(KindLoadBalancer, id)
+	}
+	s.actions["DescribeLoadBalancerAttributes"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		id := p.String("LoadBalancerId")
+		if _, err := s.Get(KindLoadBalancer, id); err != nil {
+			return nil, err
+		}
+		set := []interface{}{}
+		for k, v := range s.attributes[id] {
+			set = append(set, map[string]interface{}{"Key": k, "Value": v})
+		}
+		return map[string]interface{}{"LoadBalancerAttributeSet": set}, nil
+	}
+	s.actions["ModifyLoadBalancerAttributes"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		id := p.String("LoadBalancerId")
+		item, err := s.Get(KindLoadBalancer, id)
+		if err != nil {
+			return nil, err
+		}
+		if s.attributes[id] == nil {
+			s.attributes[id] = make(map[string]string)
+		}
+		for k, v := range tagParams(p, "Attributes.member.%d.Key", "Attributes.member.%d.Value") {
+			s.attributes[id][k] = v
+		}
+		item["AccessLogsEnabled"] = s.attributes[id]["access_logs.s3.enabled"] == "true"
+		return nil, nil
+	}
+
+	// listener
+	s.actions["CreateListeners"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		lb, err := s.Get(KindLoadBalancer, p.String("LoadBalancerId"))
+		if err != nil {
+			return nil, err
+		}
+		port := p.String("ListenerPort")
+		for _, l := range s.Select(KindListener, fieldEquals("LoadBalancerId", p.String("LoadBalancerId"))) {
+			if fmt.Sprintf("%v", l["ListenerPort"]) == port {
+				return nil, badRequest("ListenerPortConflict", "the port %s is already used by listener %v", port, l["ListenerId"])
+			}
+		}
+		item := s.Create(KindListener, p, map[string]interface{}{
+			"ListenerState": "start",
+			"Method":        "RoundRobin",
+		})
+		item["LoadBalancerId"] = lb["LoadBalancerId"]
+		return map[string]interface{}{"ListenerId": item["ListenerId"]}, nil
+	}
+	s.actions["DescribeListeners"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		resp := s.Describe(KindListener, p)
+		for _, l := range resp["ListenerSet"].([]interface{}) {
+			listener := l.(map[string]interface{})
+			for _, hc := range s.Select(KindHealthCheck, fieldEquals("ListenerId", fmt.Sprintf("%v", listener["ListenerId"]))) {
+				listener["HealthCheck"] = deepCopy(hc)
+			}
+		}
+		return resp, nil
+	}
+	s.actions["ModifyListeners"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		_, err := s.Modify(KindListener, p)
+		return nil, err
+	}
+	s.actions["DeleteListeners"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		id := p.String("ListenerId")
+		for _, hc := range s.Select(KindHealthCheck, fieldEquals("ListenerId", id)) {
+			_ = s.Remove(KindHealthCheck, fmt.Sprintf("%v", hc["HealthCheckId"]))
+		}
+		return nil, s.Remove(KindListener, id)
+	}
+
+	// health check
+	s.actions["ConfigureHealthCheck"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		if _, err := s.Get(KindListener, p.String("ListenerId")); err != nil {
+			return nil, err
+		}
+		item := s.Create(KindHealthCheck, p, map[string]interface{}{
+			"HealthCheckState":   "start",
+			"HealthyThreshold":   5,
+			"Interval":           5,
+			"Timeout":            4,
+			"UnhealthyThreshold": 4,
+		})
+		return deepCopy(item), nil
+	}
+	s.actions["DescribeHealthChecks"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return s.Describe(KindHealthCheck, p), nil
+	}
+	s.actions["ModifyHealthCheck"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		item, err := s.Modify(KindHealthCheck, p)
+		if err != nil {
+			return nil, err
+		}
+		return deepCopy(item), nil
+	}
+	s.actions["DeleteHealthCheck"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return nil, s.Remove(KindHealthCheck, p.String("HealthCheckId"))
+	}
+}
@@ -0,0 +1,436 @@
+package mockapi
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// the kinds of vpc resources
+const (
+	KindVpc              = "Vpc"
+	KindSubnet           = "Subnet"
+	KindRoute            = "Route"
+	KindSecurityGroup    = "SecurityGroup"
+	KindNetworkInterface = "NetworkInterface"
+	KindPeering          = "VpcPeeringConnection"
+	KindNetworkAcl       = "NetworkAcl"
+)
+
+func registerVpcActions(s *Server) {
+	s.register(Kind{Name: KindVpc, SetName: "VpcSet", IdField: "VpcId", Bools: []string{"IsDefault", "ProvidedIpv6CidrBlock"}})
+	s.register(Kind{Name: KindSubnet, SetName: "SubnetSet", IdField: "SubnetId",
+		Bools: []string{"ProvidedIpv6CidrBlock", "VisitInternet"}})
+	s.register(Kind{Name: KindRoute, SetName: "RouteSet", IdField: "RouteId"})
+	s.register(Kind{Name: KindSecurityGroup, SetName: "SecurityGroupSet", IdField: "SecurityGroupId"})
+	s.register(Kind{Name: KindNetworkInterface, SetName: "NetworkInterfaceSet", IdField: "NetworkInterfaceId"})
+	s.register(Kind{Name: KindPeering, SetName: "VpcPeeringConnectionSet", IdField: "VpcPeeringConnectionId", Ints: []string{"BandWidth"}})
+	s.register(Kind{Name: KindNetworkAcl, SetName: "NetworkAclSet", IdField: "NetworkAclId"})
+
+	// vpc
+	s.actions["CreateVpc"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		if p.String("CidrBlock") == "" {
+			return nil, badRequest("MissingParameter", "CidrBlock is required")
+		}
+		item := s.Create(KindVpc, p, map[string]interface{}{
+			"IsDefault":             false,
+			"ProvidedIpv6CidrBlock": false,
+		})
+		return map[string]interface{}{"Vpc": deepCopy(item)}, nil
+	}
+	s.actions["DescribeVpcs"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return s.Describe(KindVpc, p), nil
+	}
+	s.actions["ModifyVpc"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		item, err := s.Modify(KindVpc, p)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"Vpc": deepCopy(item)}, nil
+	}
+	s.actions["DeleteVpc"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		id := p.String("VpcId")
+		if subnets := s.Select(KindSubnet, fieldEquals("VpcId", id)); len(subnets) > 0 {
+			return nil, badRequest("DependencyViolation", "the vpc %s still has %d subnets", id, len(subnets))
+		}
+		return nil, s.Remove(KindVpc, id)
+	}
+
+	// subnet
+	s.actions["CreateSubnet"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		vpc, err := s.Get(KindVpc, p.String("VpcId"))
+		if err != nil {
+			return nil, err
+		}
+		_, cidr, err := net.ParseCIDR(p.String("CidrBlock"))
+		if err != nil {
+			return nil, badRequest("InvalidParameterValue", "the CidrBlock %s is malformed", p.String("CidrBlock"))
+		}
+		// like the remote, the subnet must be inside the vpc and not overlap the others
+		if _, vpcCidr, err := net.ParseCIDR(fmt.Sprintf("%v", vpc["CidrBlock"])); err == nil && !cidrContains(vpcCidr, cidr) {
+			return nil, badRequest("InvalidParameterValue", "the CidrBlock %s is out of the vpc %v", cidr, vpc["CidrBlock"])
+		}
+		for _, subnet := range s.Select(KindSubnet, fieldEquals("VpcId", p.String("VpcId"))) {
+			_, other, err := net.ParseCIDR(fmt.Sprintf("%v", subnet["CidrBlock"]))
+			if err == nil && (cidrContains(other, cidr) || cidrContains(cidr, other)) {
+				return nil, badRequest("InvalidParameterValue.CidrConflict", "the CidrBlock %s overlaps the subnet %v", cidr, subnet["SubnetId"])
+			}
+		}
+		ones, bits := cidr.Mask.Size()
+		item := s.Create(KindSubnet, p, map[string]interface{}{
+			"SubnetType":            "Normal",
+			"GatewayIp":             hostIp(cidr, 1),
+			"Dns1":                  "198.18.254.41",
+			"Dns2":                  "198.18.254.40",
+			"AvailableIpNumber":     strconv.Itoa((1 << uint(bits-ones)) - 3),
+			"ProvidedIpv6CidrBlock": false,
+		})
+		item["VpcId"] = vpc["VpcId"]
+		return map[string]interface{}{"Subnet": deepCopy(item)}, nil
+	}
+	s.actions["DescribeSubnets"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return s.Describe(KindSubnet, p), nil
+	}
+	s.actions["ModifySubnet"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		item, err := s.Modify(KindSubnet, p)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"Subnet": deepCopy(item)}, nil
+	}
+	s.actions["DeleteSubnet"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		id := p.String("SubnetId")
+		if nis := s.Select(KindNetworkInterface, fieldEquals("SubnetId", id)); len(nis) > 0 {
+			return nil, badRequest("DependencyViolation", "the subnet %s is still in use by %d network interfaces", id, len(nis))
+		}
+		return nil, s.Remove(KindSubnet, id)
+	}
+
+	// route
+	s.actions["CreateRoute"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		if _, err := s.Get(KindVpc, p.String("VpcId")); err != nil {
+			return nil, err
+		}
+		item := s.Create(KindRoute, p, nil)
+		// the next hop of peering route is the peering connection
+		nextHops := []interface{}{}
+		if id := p.String("VpcPeeringConnectionId"); id != "" {
+			peering, err := s.Get(KindPeering, id)
+			if err != nil {
+				_ = s.Remove(KindRoute, fmt.Sprintf("%v", item["RouteId"]))
+				return nil, err
+			}
+			nextHops = append(nextHops, map[string]interface{}{
+				"GatewayId":   id,
+				"GatewayName": peering["PeeringName"],
+			})
+		}
+		item["NextHopSet"] = nextHops
+		return map[string]interface{}{"RouteId": item["RouteId"]}, nil
+	}
+	s.actions["DescribeRoutes"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return s.Describe(KindRoute, p), nil
+	}
+	s.actions["DeleteRoute"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return nil, s.Remove(KindRoute, p.String("RouteId"))
+	}
+
+	// security group
+	s.actions["CreateSecurityGroup"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		if _, err := s.Get(KindVpc, p.String("VpcId")); err != nil {
+			return nil, err
+		}
+		item := s.Create(KindSecurityGroup, p, map[string]interface{}{
+			"SecurityGroupType": "other",
+		})
+		// like the remote, a new security group allows all the outbound traffic
+		item["SecurityGroupEntrySet"] = []interface{}{
+			map[string]interface{}{
+				"SecurityGroupEntryId": s.NewId(),
+				"Direction":            "out",
+				"Protocol":             "ip",
+				"CidrBlock":            "0.0.0.0/0",
+				"Description":          "",
+			},
+		}
+		return map[string]interface{}{"SecurityGroup": deepCopy(item)}, nil
+	}
+	s.actions["DescribeSecurityGroups"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return s.Describe(KindSecurityGroup, p), nil
+	}
+	s.actions["ModifySecurityGroup"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		_, err := s.Modify(KindSecurityGroup, p)
+		return nil, err
+	}
+	s.actions["DeleteSecurityGroup"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		id := p.String("SecurityGroupId")
+		for _, ni := range s.tables[KindNetworkInterface].items {
+			for _, sg := range ni["SecurityGroupSet"].([]interface{}) {
+				if sg.(map[string]interface{})["SecurityGroupId"] == id {
+					return nil, badRequest("DependencyViolation", "the security group %s is still in use", id)
+				}
+			}
+		}
+		return nil, s.Remove(KindSecurityGroup, id)
+	}
+	s.actions["AuthorizeSecurityGroupEntry"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		sg, err := s.Get(KindSecurityGroup, p.String("SecurityGroupId"))
+		if err != nil {
+			return nil, err
+		}
+		entry := map[string]interface{}{"SecurityGroupEntryId": s.NewId()}
+		for k, v := range p.Nested() {
+			if k == "SecurityGroupId" {
+				continue
+			}
+			entry[k] = v
+		}
+		for _, k := range []string{"IcmpType", "IcmpCode", "PortRangeFrom", "PortRangeTo"} {
+			if v, ok := entry[k]; ok {
+				entry[k] = toInt(v)
+			}
+		}
+		sg["SecurityGroupEntrySet"] = append(sg["SecurityGroupEntrySet"].([]interface{}), entry)
+		return map[string]interface{}{
+			"Return":                  true,
+			"SecurityGroupEntryIdSet": []interface{}{entry["SecurityGroupEntryId"]},
+		}, nil
+	}
+	s.actions["ModifySecurityGroupEntry"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		sg, err := s.Get(KindSecurityGroup, p.String("SecurityGroupId"))
+		if err != nil {
+			return nil, err
+		}
+		entry := findEntry(sg, p.String("SecurityGroupEntryId"))
+		if entry == nil {
+			return nil, notFound("SecurityGroupEntry", p.String("SecurityGroupEntryId"))
+		}
+		if v, ok := p["Description"]; ok {
+			entry["Description"] = v
+		}
+		return nil, nil
+	}
+	s.actions["RevokeSecurityGroupEntry"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		sg, err := s.Get(KindSecurityGroup, p.String("SecurityGroupId"))
+		if err != nil {
+			return nil, err
+		}
+		id := p.String("SecurityGroupEntryId")
+		entries := sg["SecurityGroupEntrySet"].([]interface{})
+		for i, entry := range entries {
+			if entry.(map[string]interface{})["SecurityGroupEntryId"] == id {
+				sg["SecurityGroupEntrySet"] = append(entries[:i:i], entries[i+1:]...)
+				return nil, nil
+			}
+		}
+		return nil, notFound("SecurityGroupEntry", id)
+	}
+
+	// vpc peering connection, the connections are pending acceptance once created
+	s.actions["CreateVpcPeeringConnection"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		requester, err := s.Get(KindVpc, p.String("VpcId"))
+		if err != nil {
+			return nil, err
+		}
+		accepter, err := s.Get(KindVpc, p.String("PeerVpcId"))
+		if err != nil {
+			return nil, err
+		}
+		region, account := DefaultRegion, DefaultAccountId
+		if v := p.String("PeerRegion"); v != "" {
+			region = v
+		}
+		if v := p.String("PeerAccountId"); v != "" {
+			account = v
+		}
+		connectionType := "SameRegion"
+		if region != DefaultRegion {
+			if p.Int("BandWidth", 0) < 1 {
+				return nil, badRequest("MissingParameter", "BandWidth is required by the cross-region peering connection")
+			}
+			connectionType = "CrossRegion"
+		}
+		item := s.Create(KindPeering, p, map[string]interface{}{
+			"State":                    "pending-acceptance",
+			"VpcPeeringConnectionType": connectionType,
+			"ProjectId":                DefaultProjectId,
+		})
+		for _, k := range []string{"PeerVpcId", "PeerRegion", "PeerAccountId", "PurchaseTime"} {
+			delete(item, k)
+		}
+		item["RequesterVpcInfo"] = peeringVpcInfo(item, requester, DefaultRegion, DefaultAccountId)
+		item["AccepterVpcInfo"] = peeringVpcInfo(item, accepter, region, account)
+		return map[string]interface{}{"VpcPeeringConnection": deepCopy(item)}, nil
+	}
+	s.actions["DescribeVpcPeeringConnections"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return s.Describe(KindPeering, p), nil
+	}
+	s.actions["AcceptVpcPeeringConnection"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		item, err := s.Get(KindPeering, p.String("VpcPeeringConnectionId"))
+		if err != nil {
+			return nil, err
+		}
+		if item["State"] != "pending-acceptance" {
+			return nil, badRequest("InvalidState", "the vpc peering connection %s is %v", item["VpcPeeringConnectionId"], item["State"])
+		}
+		item["State"] = "active"
+		return map[string]interface{}{"VpcPeeringConnection": deepCopy(item)}, nil
+	}
+	s.actions["ModifyVpcPeeringConnection"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		item, err := s.Modify(KindPeering, p)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"VpcPeeringConnection": deepCopy(item)}, nil
+	}
+	s.actions["DeleteVpcPeeringConnection"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		id := p.String("VpcPeeringConnectionId")
+		if routes := s.Select(KindRoute, fieldEquals("VpcPeeringConnectionId", id)); len(routes) > 0 {
+			return nil, badRequest("DependencyViolation", "the vpc peering connection %s is still in use by %d routes", id, len(routes))
+		}
+		return nil, s.Remove(KindPeering, id)
+	}
+
+	// network acl, the rule numbers of the entries are unique per direction
+	s.actions["CreateNetworkAcl"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		if _, err := s.Get(KindVpc, p.String("VpcId")); err != nil {
+			return nil, err
+		}
+		item := s.Create(KindNetworkAcl, p, nil)
+		item["NetworkAclEntrySet"] = []interface{}{}
+		return map[string]interface{}{"NetworkAclId": item["NetworkAclId"]}, nil
+	}
+	s.actions["DescribeNetworkAcls"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return s.Describe(KindNetworkAcl, p), nil
+	}
+	s.actions["ModifyNetworkAcl"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		_, err := s.Modify(KindNetworkAcl, p)
+		return nil, err
+	}
+	s.actions["DeleteNetworkAcl"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return nil, s.Remove(KindNetworkAcl, p.String("NetworkAclId"))
+	}
+	s.actions["CreateNetworkAclEntry"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		acl, err := s.Get(KindNetworkAcl, p.String("NetworkAclId"))
+		if err != nil {
+			return nil, err
+		}
+		entry := map[string]interface{}{"NetworkAclEntryId": s.NewId(), "Description": ""}
+		for k, v := range p.Nested() {
+			entry[k] = v
+		}
+		for _, k := range []string{"RuleNumber", "IcmpType", "IcmpCode", "PortRangeFrom", "PortRangeTo"} {
+			if v, ok := entry[k]; ok {
+				entry[k] = toInt(v)
+			}
+		}
+		for _, v := range acl["NetworkAclEntrySet"].([]interface{}) {
+			if m := v.(map[string]interface{}); m["Direction"] == entry["Direction"] && m["RuleNumber"] == entry["RuleNumber"] {
+				return nil, badRequest("InvalidParameter.Duplicate", "the rule number %v of direction %v already exists",
+					entry["RuleNumber"], entry["Direction"])
+			}
+		}
+		acl["NetworkAclEntrySet"] = append(acl["NetworkAclEntrySet"].([]interface{}), entry)
+		return map[string]interface{}{"Return": true}, nil
+	}
+	s.actions["ModifyNetworkAclEntry"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		for _, acl := range s.tables[KindNetworkAcl].items {
+			for _, v := range acl["NetworkAclEntrySet"].([]interface{}) {
+				if m := v.(map[string]interface{}); m["NetworkAclEntryId"] == p.String("NetworkAclEntryId") {
+					if description, ok := p["Description"]; ok {
+						m["Description"] = description
+					}
+					return map[string]interface{}{"Return": true}, nil
+				}
+			}
+		}
+		return nil, notFound("NetworkAclEntry", p.String("NetworkAclEntryId"))
+	}
+	s.actions["DeleteNetworkAclEntry"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		acl, err := s.Get(KindNetworkAcl, p.String("NetworkAclId"))
+		if err != nil {
+			return nil, err
+		}
+		id := p.String("NetworkAclEntryId")
+		entries := acl["NetworkAclEntrySet"].([]interface{})
+		for i, entry := range entries {
+			if entry.(map[string]interface{})["NetworkAclEntryId"] == id {
+				acl["NetworkAclEntrySet"] = append(entries[:i:i], entries[i+1:]...)
+				return map[string]interface{}{"Return": true}, nil
+			}
+		}
+		return nil, notFound("NetworkAclEntry", id)
+	}
+
+	// network interface, the primary network interfaces are created with instances
+	s.actions["DescribeNetworkInterfaces"] = func(s *Server, p Params) (map[string]interface{}, error) {
+		return s.Describe(KindNetworkInterface, p), nil
+	}
+}
+
+func peeringVpcInfo(peering, vpc map[string]interface{}, region, account string) map[string]interface{} {
+	return map[string]interface{}{
+		"VpcPeeringConnectionId": peering["VpcPeeringConnectionId"],
+		"AccountId":              account,
+		"Region":                 region,
+		"VpcId":                  vpc["VpcId"],
+		"VpcName":                vpc["VpcName"],
+		"CidrBlock":              vpc["CidrBlock"],
+	}
+}
+
+// cidrContains returns true if the cidr block b is inside a
+func cidrContains(a, b *net.IPNet) bool {
+	onesA, _ := a.Mask.Size()
+	onesB, _ := b.Mask.Size()
+	return onesA <= onesB && a.Contains(b.IP)
+}
+
+func findEntry(sg map[string]interface{}, id string) map[string]interface{} {
+	for _, entry := range sg["SecurityGroupEntrySet"].([]interface{}) {
+		if m := entry.(map[string]interface{}); m["SecurityGroupEntryId"] == id {
+			return m
+		}
+	}
+	return nil
+}
+
+func fieldEquals(field, value string) func(item map[string]interface{}) bool {
+	return func(item map[string]interface{}) bool {
+		return fmt.Sprintf("%v", item[field]) == value
+	}
+}
+
+// hostIp returns the nth host of the cidr
+func hostIp(cidr *net.IPNet, n int) string {
+	ip := cidr.IP.To4()
+	if ip == nil {
+		return ""
+	}
+	v := binary.BigEndian.Uint32(ip) + uint32(n)
+	result := make(net.IP, 4)
+	binary.BigEndian.PutUint32(result, v)
+	return result.String()
+}
+
+// allocateIp returns a free private ip of the subnet, the first hosts are reserved as real subnets do.
+func (s *Server) allocateIp(subnet map[string]interface{}) (string, error) {
+	_, cidr, err := net.ParseCIDR(fmt.Sprintf("%v", subnet["CidrBlock"]))
+	if err != nil {
+		return "", err
+	}
+	id := fmt.Sprintf("%v", subnet["SubnetId"])
+	ones, bits := cidr.Mask.Size()
+	if s.ipSeq[id] == 0 {
+		s.ipSeq[id] = 1
+	}
+	s.ipSeq[id]++
+	if s.ipSeq[id] >= (1<<uint(bits-ones))-1 {
+		return "", badRequest("InsufficientIpAddress", "no free ip address in subnet %s", id)
+	}
+	if n, err := strconv.Atoi(fmt.Sprintf("%v", subnet["AvailableIpNumber"])); err == nil {
+		subnet["AvailableIpNumber"] = strconv.Itoa(n - 1)
+	}
+	return hostIp(cidr, s.ipSeq[id]), nil
+}
@@ -0,0 +1,103 @@
+package network
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// TokenBucket is a token bucket rate limiter, the tokens are refilled at Rate per second
+// and at most Burst tokens can be accumulated.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket returns a full token bucket
+func NewTokenBucket(rate float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Reserve takes a token from the bucket, and returns how long the caller should wait
+// before the token is available.
+func (b *TokenBucket) Reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	b.tokens--
+	if b.tokens >= 0 || b.rate <= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// Wait blocks until a token is available or the context is done
+func (b *TokenBucket) Wait(ctx aws.Context) error {
+	delay := b.Reserve()
+	if delay <= 0 {
+		return nil
+	}
+	return aws.SleepWithContext(ctx, delay)
+}
+
+// RateLimiter holds the token buckets of services
+type RateLimiter struct {
+	buckets map[string]*TokenBucket
+}
+
+// NewRateLimiter returns an empty rate limiter, which doesn't limit any service
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{buckets: make(map[string]*TokenBucket)}
+}
+
+// SetLimit limits the requests of service to rate per second with burst
+func (l *RateLimiter) SetLimit(service string, rate float64, burst int) {
+	l.buckets[strings.ToLower(service)] = NewTokenBucket(rate, burst)
+}
+
+// Bucket returns the token bucket of service, nil is returned if the service is not limited
+func (l *RateLimiter) Bucket(service string) *TokenBucket {
+	if l == nil {
+		return nil
+	}
+	return l.buckets[strings.ToLower(service)]
+}
+
+// Handler returns a handler that waits for the token of request's service before sending it,
+// every retry attempt of the request takes a token as well.
+func (l *RateLimiter) Handler() request.NamedHandler {
+	return request.NamedHandler{
+		Name: "ksyun.RateLimitHandler",
+		Fn: func(r *request.Request) {
+			bucket := l.Bucket(r.ClientInfo.ServiceName)
+			if bucket == nil {
+				return
+			}
+			if err := bucket.Wait(r.Context()); err != nil {
+				r.Error = awserr.New(request.CanceledErrorCode, "request context canceled while waiting for rate limit", err)
+			}
+		},
+	}
+}
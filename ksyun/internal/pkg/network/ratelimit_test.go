@@ -0,0 +1,51 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+func TestTokenBucketReserve(t *testing.T) {
+	bucket := NewTokenBucket(10, 2)
+	for i := 0; i < 2; i++ {
+		if d := bucket.Reserve(); d != 0 {
+			t.Errorf("Expected burst request %d not to wait, got %s", i, d)
+		}
+	}
+	// the third request waits for about 100ms, the fourth for about 200ms
+	if d := bucket.Reserve(); d <= 50*time.Millisecond || d > 100*time.Millisecond {
+		t.Errorf("Expected to wait about 100ms, got %s", d)
+	}
+	if d := bucket.Reserve(); d <= 150*time.Millisecond || d > 200*time.Millisecond {
+		t.Errorf("Expected to wait about 200ms, got %s", d)
+	}
+}
+
+func TestRateLimiterHandler(t *testing.T) {
+	limiter := NewRateLimiter()
+	limiter.SetLimit("VPC", 20, 1)
+	handler := limiter.Handler()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		r := &request.Request{ClientInfo: metadata.ClientInfo{ServiceName: "vpc"}}
+		handler.Fn(r)
+		if r.Error != nil {
+			t.Fatalf("Expected no error, got %s", r.Error)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("Expected requests to be limited, elapsed %s", elapsed)
+	}
+
+	start = time.Now()
+	for i := 0; i < 10; i++ {
+		handler.Fn(&request.Request{ClientInfo: metadata.ClientInfo{ServiceName: "slb"}})
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Millisecond {
+		t.Errorf("Expected requests of unlimited service not to wait, elapsed %s", elapsed)
+	}
+}
@@ -1,9 +1,13 @@
 package network
 
 import (
+	"math/rand"
 	"net"
+	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws/awserr"
@@ -22,37 +26,118 @@ const (
 	RequestTimeoutException = "RequestTimeoutException"
 )
 
+const (
+	// DefaultMinRetryDelay is the minimum delay of retrying a request
+	DefaultMinRetryDelay = 500 * time.Millisecond
+
+	// DefaultMaxRetryDelay is the maximum delay of retrying a request
+	DefaultMaxRetryDelay = 30 * time.Second
+)
+
 // retryableErrorCodes is a list of retryable error code
 var retryableErrorCodes = []string{ServiceTimeout, RequestTimeout, ErrCodeResponseTimeout, RequestTimeoutException}
 
+// throttleErrorCodes is a list of error code that indicates the request is throttled
+var throttleErrorCodes = []string{
+	"Throttling",
+	"ThrottlingException",
+	"Throttled",
+	"RequestThrottled",
+	"RequestLimitExceeded",
+	"TooManyRequests",
+	"TooManyRequestsException",
+	"ServiceUnavailable",
+	"ServiceUnavailableException",
+}
+
+// throttleStatusCodes is a list of http status code that indicates the request is throttled
+var throttleStatusCodes = []int{http.StatusTooManyRequests, http.StatusServiceUnavailable}
+
 type temporary interface {
 	Temporary() bool
 }
 
 // custom retry
 type KsyunRetryer struct {
-	// ...
 	NumMaxRetries int
+
+	// NumMaxThrottleRetries is the max retries of the throttled requests, they're never processed
+	// by the server, so they're safe to retry even if NumMaxRetries is 0.
+	NumMaxThrottleRetries int
+
+	// MinRetryDelay is the base delay of exponential backoff
+	MinRetryDelay time.Duration
+
+	// MaxRetryDelay caps the delay of exponential backoff
+	MaxRetryDelay time.Duration
 }
 
 var _ request.Retryer = (*KsyunRetryer)(nil)
 
-func GetKsyunRetryer(maxRetries int) request.Retryer {
+var (
+	seededRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	randMutex  sync.Mutex
+)
+
+func GetKsyunRetryer(maxRetries, maxThrottleRetries int, minDelay, maxDelay time.Duration) request.Retryer {
+	if minDelay <= 0 {
+		minDelay = DefaultMinRetryDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultMaxRetryDelay
+	}
+	if maxDelay < minDelay {
+		maxDelay = minDelay
+	}
 	return &KsyunRetryer{
-		NumMaxRetries: maxRetries,
+		NumMaxRetries:         maxRetries,
+		NumMaxThrottleRetries: maxThrottleRetries,
+		MinRetryDelay:         minDelay,
+		MaxRetryDelay:         maxDelay,
 	}
 }
 
+// RetryRules returns the delay of next retry. The delay hinted by server is preferred but
+// capped by MaxRetryDelay, otherwise it's an exponential backoff with jitter, which is
+// between the half and the whole of min(MinRetryDelay * 2^RetryCount, MaxRetryDelay).
 func (k *KsyunRetryer) RetryRules(r *request.Request) time.Duration {
-	// retry delay
-	return 500 * time.Millisecond
+	minDelay, maxDelay := k.MinRetryDelay, k.MaxRetryDelay
+	if minDelay <= 0 {
+		minDelay = DefaultMinRetryDelay
+	}
+	if maxDelay < minDelay {
+		maxDelay = minDelay
+	}
+
+	if delay, ok := getRetryAfterDelay(r); ok {
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+		return delay
+	}
+
+	delay := maxDelay
+	// avoid overflow of large retry count
+	if r.RetryCount < 32 {
+		if d := minDelay << uint(r.RetryCount); d > 0 && d < maxDelay {
+			delay = d
+		}
+	}
+
+	half := int64(delay / 2)
+	return time.Duration(half + randInt63n(half+1))
 }
 
 func (k *KsyunRetryer) ShouldRetry(r *request.Request) bool {
 	// indicates whether retry the request
 
-	// ShouldRetry returns false if number of max retries is 0.
-	if k.NumMaxRetries == 0 {
+	// throttled requests are retried after backing off within their own budget
+	if IsThrottleError(r) {
+		return r.RetryCount < k.NumMaxThrottleRetries
+	}
+
+	// the other errors are retried within NumMaxRetries, the requests may have been processed
+	if r.RetryCount >= k.NumMaxRetries {
 		return false
 	}
 
@@ -66,13 +151,71 @@ func (k *KsyunRetryer) ShouldRetry(r *request.Request) bool {
 		return true
 	}
 
+	if r.Error == nil {
+		return false
+	}
+
 	// customs retry condition
 	return shouldRetryError(r.Error) || isErrConnectionReset(r.Error)
 }
+
+// MaxRetries returns the larger of the budgets, ShouldRetry checks the budget of each kind of errors.
 func (k *KsyunRetryer) MaxRetries() int {
+	if k.NumMaxThrottleRetries > k.NumMaxRetries {
+		return k.NumMaxThrottleRetries
+	}
 	return k.NumMaxRetries
 }
 
+// IsThrottleError returns whether the request is throttled by server
+func IsThrottleError(r *request.Request) bool {
+	if r.HTTPResponse != nil {
+		for _, code := range throttleStatusCodes {
+			if r.HTTPResponse.StatusCode == code {
+				return true
+			}
+		}
+	}
+	if r.Error == nil {
+		return false
+	}
+	return isErrCode(r.Error, throttleErrorCodes) || infraerrs.IsExpectError(r.Error, throttleErrorCodes)
+}
+
+// getRetryAfterDelay returns the delay hinted by Retry-After header,
+// the header value can be either seconds or a http date.
+func getRetryAfterDelay(r *request.Request) (time.Duration, bool) {
+	if r.HTTPResponse == nil {
+		return 0, false
+	}
+	v := strings.TrimSpace(r.HTTPResponse.Header.Get("Retry-After"))
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if delay := time.Until(t); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+func randInt63n(n int64) int64 {
+	if n <= 0 {
+		return 0
+	}
+	randMutex.Lock()
+	defer randMutex.Unlock()
+	return seededRand.Int63n(n)
+}
+
 func isErrConnectionReset(err error) bool {
 	if strings.Contains(err.Error(), "read: connection reset") {
 		return false
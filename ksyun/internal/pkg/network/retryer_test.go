@@ -1,13 +1,20 @@
 package network
 
 import (
+	"io/ioutil"
 	"net"
+	"net/http"
 	"net/url"
 	"os"
+	"strings"
 	"syscall"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/corehandlers"
 	"github.com/aws/aws-sdk-go/aws/request"
 )
 
@@ -36,3 +43,152 @@ func TestShouldRetry(t *testing.T) {
 	}
 
 }
+
+// newFakeRequest returns a request whose send handler responds with the given responses in turn,
+// the delays of retries are recorded instead of sleeping.
+func newFakeRequest(retryer request.Retryer, responses []*http.Response, delays *[]time.Duration) (*request.Request, *int) {
+	count := 0
+	handlers := request.Handlers{}
+	handlers.Send.PushBack(func(r *request.Request) {
+		resp := responses[len(responses)-1]
+		if count < len(responses) {
+			resp = responses[count]
+		}
+		count++
+		r.HTTPResponse = &http.Response{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+		}
+	})
+	handlers.ValidateResponse.PushBackNamed(corehandlers.ValidateResponseHandler)
+	handlers.UnmarshalError.PushBack(func(r *request.Request) {
+		code := "InternalError"
+		if r.HTTPResponse.StatusCode == http.StatusTooManyRequests {
+			code = "Throttling"
+		}
+		r.Error = awserr.NewRequestFailure(awserr.New(code, "fake error", nil), r.HTTPResponse.StatusCode, "")
+	})
+	handlers.AfterRetry.PushBackNamed(corehandlers.AfterRetryHandler)
+
+	cfg := aws.NewConfig().WithMaxRetries(retryer.MaxRetries())
+	cfg.SleepDelay = func(d time.Duration) {
+		*delays = append(*delays, d)
+	}
+	req := request.New(*cfg, metadata.ClientInfo{ServiceName: "fake", Endpoint: "localhost"}, handlers, retryer,
+		&request.Operation{Name: "op", HTTPMethod: "GET", HTTPPath: "/"}, &struct{}{}, &struct{}{})
+	return req, &count
+}
+
+func TestRetryThrottledRequest(t *testing.T) {
+	var delays []time.Duration
+	retryer := GetKsyunRetryer(0, 3, 100*time.Millisecond, 5*time.Second)
+	req, count := newFakeRequest(retryer, []*http.Response{
+		{StatusCode: http.StatusTooManyRequests, Header: http.Header{}},
+		{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Retry-After": []string{"2"}}},
+		{StatusCode: http.StatusOK, Header: http.Header{}},
+	}, &delays)
+
+	if err := req.Send(); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if *count != 3 {
+		t.Errorf("Expected 3 attempts, got %d", *count)
+	}
+	if len(delays) != 2 {
+		t.Fatalf("Expected 2 delays, got %d", len(delays))
+	}
+	if delays[0] < 50*time.Millisecond || delays[0] > 100*time.Millisecond {
+		t.Errorf("Expected first delay between 50ms and 100ms, got %s", delays[0])
+	}
+	if delays[1] != 2*time.Second {
+		t.Errorf("Expected the delay of Retry-After to be honoured, got %s", delays[1])
+	}
+}
+
+func TestRetryAfterCapped(t *testing.T) {
+	var delays []time.Duration
+	retryer := GetKsyunRetryer(0, 2, 100*time.Millisecond, time.Second)
+	req, _ := newFakeRequest(retryer, []*http.Response{
+		{StatusCode: http.StatusServiceUnavailable, Header: http.Header{"Retry-After": []string{"3600"}}},
+		{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)}}},
+		{StatusCode: http.StatusOK, Header: http.Header{}},
+	}, &delays)
+
+	if err := req.Send(); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if len(delays) != 2 {
+		t.Fatalf("Expected 2 delays, got %d", len(delays))
+	}
+	for i, delay := range delays {
+		if delay != time.Second {
+			t.Errorf("Expected delay %d of Retry-After to be capped by 1s, got %s", i, delay)
+		}
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	var delays []time.Duration
+	retryer := GetKsyunRetryer(5, 2, 100*time.Millisecond, time.Second)
+	req, count := newFakeRequest(retryer, []*http.Response{
+		{StatusCode: http.StatusTooManyRequests, Header: http.Header{}},
+	}, &delays)
+
+	err := req.Send()
+	if aerr, ok := err.(awserr.Error); !ok || aerr.Code() != "Throttling" {
+		t.Errorf("Expected Throttling error, got %v", err)
+	}
+	if *count != 3 {
+		t.Errorf("Expected 3 attempts, got %d", *count)
+	}
+}
+
+func TestRetryNonRetryableError(t *testing.T) {
+	var delays []time.Duration
+	retryer := GetKsyunRetryer(3, 3, 0, 0)
+	req, count := newFakeRequest(retryer, []*http.Response{
+		{StatusCode: http.StatusBadRequest, Header: http.Header{}},
+	}, &delays)
+
+	if err := req.Send(); err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+	if *count != 1 {
+		t.Errorf("Expected 1 attempt, got %d", *count)
+	}
+}
+
+func TestNoRetryOfServerErrorByDefault(t *testing.T) {
+	var delays []time.Duration
+	// the throttle budget doesn't apply to the other errors
+	retryer := GetKsyunRetryer(0, 3, 100*time.Millisecond, time.Second)
+	req, count := newFakeRequest(retryer, []*http.Response{
+		{StatusCode: http.StatusInternalServerError, Header: http.Header{}},
+		{StatusCode: http.StatusOK, Header: http.Header{}},
+	}, &delays)
+
+	if err := req.Send(); err == nil {
+		t.Errorf("Expected error, got nil")
+	}
+	if *count != 1 {
+		t.Errorf("Expected 1 attempt, got %d", *count)
+	}
+}
+
+func TestRetryRulesBackoff(t *testing.T) {
+	retryer := &KsyunRetryer{NumMaxRetries: 10, MinRetryDelay: 100 * time.Millisecond, MaxRetryDelay: time.Second}
+	for i, max := range []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	} {
+		r := &request.Request{RetryCount: i}
+		if d := retryer.RetryRules(r); d < max/2 || d > max {
+			t.Errorf("Expected delay of retry %d between %s and %s, got %s", i, max/2, max, d)
+		}
+	}
+}
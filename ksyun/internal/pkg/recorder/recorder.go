@@ -0,0 +1,360 @@
+// Package recorder provides a http transport which records the api calls into cassette files
+// and replays them offline, so that the acceptance tests can run without a real account.
+//
+// Only the clients sharing the http client of ksc-sdk-go are recorded, the clients of KS3, klog and kmr
+// have their own http clients and always call the real api.
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	// ModeEnv is the environment variable holding the mode of recorder, record or replay.
+	ModeEnv = "KSYUN_FIXTURE_MODE"
+	// CassetteEnv is the environment variable holding the path of cassette file.
+	CassetteEnv = "KSYUN_FIXTURE_CASSETTE"
+)
+
+// Mode is the mode of recorder
+type Mode string
+
+const (
+	ModeDisabled Mode = ""
+	ModeRecord   Mode = "record"
+	ModeReplay   Mode = "replay"
+)
+
+// ScrubbedValue replaces the credentials and signatures in cassettes
+const ScrubbedValue = "SCRUBBED"
+
+// the headers carrying signature or credentials, they are never recorded
+var scrubbedHeaders = []string{
+	"Authorization",
+	"X-Amz-Date",
+	"X-Amz-Security-Token",
+	"X-Amz-Content-Sha256",
+	"X-Ksc-Security-Token",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// the parameters carrying signature or credentials are recorded as ScrubbedValue
+var scrubbedParams = []string{"accesskey", "secretkey", "signature", "securitytoken", "password"}
+
+// the parameters changing in every request, they're not matched on replay
+var volatileParams = []string{"signature", "timestamp", "nonce", "x-amz-"}
+
+// the response fields carrying credentials, such as the temporary credentials of AssumeRole,
+// their values are recorded as ScrubbedValue in both json and xml bodies
+var scrubbedFields = regexp.MustCompile(`(?i)("(?:SecretAccessKey|SecretKey|AccessKeySecret|SessionToken|SecurityToken|Password)"\s*:\s*)"[^"]*"` +
+	`|(<(?:SecretAccessKey|SecretKey|AccessKeySecret|SessionToken|SecurityToken|Password)>)[^<]*</`)
+
+// Interaction is a recorded api call
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the recorded request
+type Request struct {
+	Method string `json:"method"`
+	Host   string `json:"host"`
+	Action string `json:"action"`
+	Query  string `json:"query,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response is the recorded response
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// Cassette holds the interactions recorded in a file
+type Cassette struct {
+	mu           sync.Mutex
+	path         string
+	Interactions []*Interaction `json:"interactions"`
+	// replayed marks the interactions already replayed
+	replayed []bool
+}
+
+// LoadCassette loads the cassette file, an empty cassette is returned if the file doesn't exist.
+func LoadCassette(path string) (*Cassette, error) {
+	c := &Cassette{path: path}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %s", path, err)
+	}
+	c.replayed = make([]bool, len(c.Interactions))
+	return c, nil
+}
+
+// Save writes the cassette into its file
+func (c *Cassette) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.save()
+}
+
+func (c *Cassette) save() error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.path, b, 0644)
+}
+
+func (c *Cassette) add(i *Interaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Interactions = append(c.Interactions, i)
+	c.replayed = append(c.replayed, false)
+	// save every interaction, the provider process may exit at any time
+	return c.save()
+}
+
+// next returns the first interaction not replayed yet which matches the request,
+// the interactions of the same action are replayed in the recorded order.
+func (c *Cassette) next(r *Request) *Interaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for idx, i := range c.Interactions {
+		if c.replayed[idx] {
+			continue
+		}
+		if i.Request.Method == r.Method && i.Request.Host == r.Host && i.Request.Action == r.Action &&
+			i.Request.params() == r.params() {
+			c.replayed[idx] = true
+			return i
+		}
+	}
+	return nil
+}
+
+// params returns the sorted parameters of the query and form body, the volatile parameters
+// such as the signature and timestamp are excluded.
+func (r *Request) params() string {
+	values, _ := url.ParseQuery(r.Query)
+	if form, err := url.ParseQuery(r.Body); err == nil && r.formBody() {
+		for k, v := range form {
+			values[k] = append(values[k], v...)
+		}
+	}
+	for k := range values {
+		lower := strings.ToLower(k)
+		for _, p := range volatileParams {
+			if strings.Contains(lower, p) {
+				delete(values, k)
+			}
+		}
+	}
+	return values.Encode()
+}
+
+// formBody returns whether the body is form encoded, the json bodies are not matched
+func (r *Request) formBody() bool {
+	body := strings.TrimSpace(r.Body)
+	return body != "" && !strings.HasPrefix(body, "{") && !strings.HasPrefix(body, "[") && !strings.HasPrefix(body, "<")
+}
+
+// Transport records or replays the api calls
+type Transport struct {
+	Mode     Mode
+	Cassette *Cassette
+	// Transport sends the requests in record mode, http.DefaultTransport is used if nil.
+	Transport http.RoundTripper
+	// Secrets are replaced with ScrubbedValue wherever they are found in cassette, such as the access key.
+	Secrets []string
+}
+
+var (
+	cassettesMu sync.Mutex
+	cassettes   = make(map[string]*Cassette)
+)
+
+// NewTransport returns the recorder transport wrapping transport, the cassettes are shared by
+// the transports with the same path, so that the interactions are replayed in order across
+// provider configurations.
+func NewTransport(mode Mode, path string, transport http.RoundTripper) (*Transport, error) {
+	if mode != ModeRecord && mode != ModeReplay {
+		return nil, fmt.Errorf("unsupported fixture mode %q, it must be %s or %s", mode, ModeRecord, ModeReplay)
+	}
+	if path == "" {
+		return nil, fmt.Errorf("the cassette of fixture mode %s is not set, please set %s", mode, CassetteEnv)
+	}
+
+	cassettesMu.Lock()
+	defer cassettesMu.Unlock()
+	c, ok := cassettes[path]
+	if !ok {
+		var err error
+		if mode == ModeRecord {
+			// record from scratch
+			c = &Cassette{path: path}
+		} else if c, err = LoadCassette(path); err != nil {
+			return nil, err
+		}
+		cassettes[path] = c
+	}
+	return &Transport{Mode: mode, Cassette: c, Transport: transport}, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := t.recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Mode == ModeReplay {
+		i := t.Cassette.next(recorded)
+		if i == nil {
+			return nil, fmt.Errorf("no recorded interaction for %s %s action %s with parameters %q in cassette %s",
+				recorded.Method, recorded.Host, recorded.Action, recorded.params(), t.Cassette.path)
+		}
+		return i.Response.toHttpResponse(req), nil
+	}
+
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	header := resp.Header.Clone()
+	for _, h := range scrubbedHeaders {
+		header.Del(h)
+	}
+	i := &Interaction{
+		Request: *recorded,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       t.scrub(scrubFields(string(body))),
+		},
+	}
+	if err = t.Cassette.add(i); err != nil {
+		return nil, fmt.Errorf("failed to save cassette %s: %s", t.Cassette.path, err)
+	}
+	return resp, nil
+}
+
+func (t *Transport) recordRequest(req *http.Request) (*Request, error) {
+	r := &Request{
+		Method: req.Method,
+		Host:   req.URL.Host,
+		Action: req.URL.Query().Get("Action"),
+		Query:  t.scrub(scrubQuery(req.URL.RawQuery)),
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		r.Body = string(body)
+		if strings.Contains(req.Header.Get("Content-Type"), "x-www-form-urlencoded") {
+			r.Body = scrubQuery(r.Body)
+			if r.Action == "" {
+				if values, err := url.ParseQuery(r.Body); err == nil {
+					r.Action = values.Get("Action")
+				}
+			}
+		}
+		r.Body = t.scrub(r.Body)
+	}
+	return r, nil
+}
+
+func (t *Transport) scrub(s string) string {
+	for _, secret := range t.Secrets {
+		if secret != "" {
+			s = strings.Replace(s, secret, ScrubbedValue, -1)
+		}
+	}
+	return s
+}
+
+// scrubFields replaces the values of credential fields in the response body
+func scrubFields(body string) string {
+	return scrubbedFields.ReplaceAllStringFunc(body, func(field string) string {
+		m := scrubbedFields.FindStringSubmatch(field)
+		if m[1] != "" {
+			return m[1] + `"` + ScrubbedValue + `"`
+		}
+		return m[2] + ScrubbedValue + "</"
+	})
+}
+
+// scrubQuery replaces the values of credential parameters, the parameters are encoded
+// in sorted order so that the cassettes are stable.
+func scrubQuery(query string) string {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return query
+	}
+	for k := range values {
+		lower := strings.ToLower(k)
+		for _, p := range scrubbedParams {
+			if strings.Contains(lower, p) {
+				values[k] = []string{ScrubbedValue}
+			}
+		}
+	}
+	return values.Encode()
+}
+
+func (r Response) toHttpResponse(req *http.Request) *http.Response {
+	header := r.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// ModeFromEnv returns the mode of recorder set by ModeEnv
+func ModeFromEnv() Mode {
+	return Mode(strings.ToLower(strings.TrimSpace(os.Getenv(ModeEnv))))
+}
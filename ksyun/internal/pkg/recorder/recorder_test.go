@@ -0,0 +1,134 @@
+package recorder
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"RequestId":"req-%d","Action":"%s","Owner":"ak-real"}`, calls, r.URL.Query().Get("Action"))
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "ksyun-cassette")
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "fixtures", "TestRecordAndReplay.json")
+
+	rt, err := NewTransport(ModeRecord, path, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	rt.Secrets = []string{"ak-real"}
+	client := &http.Client{Transport: rt}
+	send := func(client *http.Client, action string) (string, error) {
+		req, _ := http.NewRequest("GET", srv.URL+"/?Action="+action+"&AccessKeyId=ak-real", nil)
+		req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential=ak-real/20200101/cn-beijing-6/vpc/aws4_request")
+		resp, err := client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		b, err := ioutil.ReadAll(resp.Body)
+		return string(b), err
+	}
+	for _, action := range []string{"DescribeVpcs", "CreateVpc", "DescribeVpcs"} {
+		if _, err = send(client, action); err != nil {
+			t.Fatalf("Expected no error, got %s", err)
+		}
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Expected the cassette to be saved, got %s", err)
+	}
+	if strings.Contains(string(b), "ak-real") || strings.Contains(string(b), "AWS4-HMAC-SHA256") {
+		t.Errorf("Expected credentials and signatures to be scrubbed, got %s", b)
+	}
+
+	// replay offline in the recorded order
+	srv.Close()
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	client = &http.Client{Transport: &Transport{Mode: ModeReplay, Cassette: cassette}}
+	if body, err := send(client, "CreateVpc"); err != nil || !strings.Contains(body, "req-2") {
+		t.Errorf("Expected req-2 to be replayed, got %s %v", body, err)
+	}
+	for _, expected := range []string{"req-1", "req-3"} {
+		body, err := send(client, "DescribeVpcs")
+		if err != nil || !strings.Contains(body, expected) {
+			t.Errorf("Expected %s to be replayed, got %s %v", expected, body, err)
+		}
+	}
+	if _, err = send(client, "DescribeVpcs"); err == nil {
+		t.Errorf("Expected error when the interactions are exhausted")
+	}
+}
+
+func TestReplayMatchesParams(t *testing.T) {
+	cassette := &Cassette{
+		Interactions: []*Interaction{
+			{
+				Request:  Request{Method: "GET", Host: "vpc.api.ksyun.com", Action: "DescribeVpcs", Query: "Action=DescribeVpcs&VpcId.1=vpc-a"},
+				Response: Response{StatusCode: 200, Body: `{"RequestId":"req-a"}`},
+			},
+			{
+				Request:  Request{Method: "POST", Host: "vpc.api.ksyun.com", Action: "CreateVpc", Body: "Action=CreateVpc&CidrBlock=10.0.0.0%2F16&Signature=old"},
+				Response: Response{StatusCode: 200, Body: `{"RequestId":"req-b"}`},
+			},
+			{
+				Request:  Request{Method: "GET", Host: "vpc.api.ksyun.com", Action: "DescribeVpcs", Query: "Action=DescribeVpcs&VpcId.1=vpc-b"},
+				Response: Response{StatusCode: 200, Body: `{"RequestId":"req-c"}`},
+			},
+		},
+		replayed: make([]bool, 3),
+	}
+	client := &http.Client{Transport: &Transport{Mode: ModeReplay, Cassette: cassette}}
+
+	// the interactions are matched by the parameters, not only the order of the action
+	resp, err := client.Get("http://vpc.api.ksyun.com/?VpcId.1=vpc-b&Action=DescribeVpcs&X-Amz-Date=20200101T000000Z")
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if b, _ := ioutil.ReadAll(resp.Body); !strings.Contains(string(b), "req-c") {
+		t.Errorf("Expected req-c to be replayed, got %s", b)
+	}
+	resp, err = client.Post("http://vpc.api.ksyun.com/", "application/x-www-form-urlencoded",
+		strings.NewReader("Action=CreateVpc&CidrBlock=10.0.0.0%2F16&Signature=new&Timestamp=2020-01-01T00%3A00%3A00Z"))
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if b, _ := ioutil.ReadAll(resp.Body); !strings.Contains(string(b), "req-b") {
+		t.Errorf("Expected req-b to be replayed, got %s", b)
+	}
+	if _, err = client.Get("http://vpc.api.ksyun.com/?Action=DescribeVpcs&VpcId.1=vpc-c"); err == nil {
+		t.Errorf("Expected error when no interaction has the parameters")
+	}
+}
+
+func TestScrubFields(t *testing.T) {
+	cases := map[string]string{
+		`{"Credentials":{"AccessKeyId":"ak-tmp","SecretAccessKey":"sk-tmp", "SessionToken" : "token-tmp"}}`:            `{"Credentials":{"AccessKeyId":"ak-tmp","SecretAccessKey":"SCRUBBED", "SessionToken" : "SCRUBBED"}}`,
+		`<Credentials><SecretAccessKey>sk-tmp</SecretAccessKey><SecurityToken>token-tmp</SecurityToken></Credentials>`: `<Credentials><SecretAccessKey>SCRUBBED</SecretAccessKey><SecurityToken>SCRUBBED</SecurityToken></Credentials>`,
+		`{"VpcName":"tf-test"}`: `{"VpcName":"tf-test"}`,
+	}
+	for body, expected := range cases {
+		if scrubbed := scrubFields(body); scrubbed != expected {
+			t.Errorf("Expected %s, got %s", expected, scrubbed)
+		}
+	}
+}
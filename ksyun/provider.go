@@ -58,6 +58,7 @@ VPC
 		ksyun_nats
 		ksyun_network_acls
 		ksyun_subnets
+		ksyun_vpc_cidr_plan
 		ksyun_network_interfaces
 		ksyun_routes
 		ksyun_security_groups
@@ -67,9 +68,12 @@ VPC
 		ksyun_private_dns_records
 		ksyun_private_dns_zones
 		ksyun_direct_connects
+		ksyun_vpc_peering_connections
 
 	Resource
 		ksyun_vpc
+		ksyun_vpc_peering_connection
+		ksyun_vpc_peering_connection_accepter
 		ksyun_subnet
 		ksyun_nat
 		ksyun_nat_associate
@@ -78,10 +82,12 @@ VPC
 		ksyun_network_acl
 		ksyun_network_acl_entry
 		ksyun_network_acl_associate
+		ksyun_network_acl_rules
 		ksyun_route
 		ksyun_security_group
 		ksyun_security_group_entry
 		ksyun_security_group_entry_lite
+		ksyun_security_group_entry_set
 		ksyun_kec_network_interface
 		ksyun_private_dns_zone
 		ksyun_private_dns_record
@@ -154,9 +160,14 @@ CEN
 
 	Data Source
 		ksyun_cens
+		ksyun_cen_route_entries
 
 	Resource
 		ksyun_cen
+		ksyun_cen_grant
+		ksyun_cen_instance_attachment
+		ksyun_cen_bandwidth_package
+		ksyun_cen_region_bandwidth
 
 SSH key
 
@@ -171,6 +182,8 @@ Instance(KEC)
 	Data Source
 		ksyun_images
 		ksyun_instances
+		ksyun_instance_types
+		ksyun_cloudinit_config
 		ksyun_local_volumes
 		ksyun_local_snapshots
 		ksyun_auto_snapshot_policy
@@ -179,6 +192,9 @@ Instance(KEC)
 
 	Resource
 		ksyun_instance
+		ksyun_instance_group
+		ksyun_image
+		ksyun_launch_template
 		ksyun_kec_network_interface_attachment
 		ksyun_auto_snapshot_policy
 		ksyun_auto_snapshot_volume_association
@@ -194,6 +210,7 @@ Volume(EBS)
 		ksyun_volume
 		ksyun_volume_attach
 		ksyun_snapshot
+		ksyun_snapshot_copy
 
 Bare Metal
 
@@ -398,16 +415,20 @@ KLog
 package ksyun
 
 import (
+	"fmt"
 	"net/url"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/terraform-providers/terraform-provider-ksyun/logger"
 )
 
 // Provider returns a terraform.ResourceProvider.
 func Provider() terraform.ResourceProvider {
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"access_key": {
 				Type:        schema.TypeString,
@@ -421,6 +442,58 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("KSYUN_SECRET_KEY", nil),
 				Description: descriptions["secret_key"],
 			},
+			"security_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("KSYUN_SECURITY_TOKEN", nil),
+				Description: descriptions["security_token"],
+			},
+			"assume_role": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: descriptions["assume_role"],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"role_krn": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: descriptions["assume_role_role_krn"],
+						},
+						"session_name": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "terraform",
+							Description: descriptions["assume_role_session_name"],
+						},
+						"duration": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      3600,
+							ValidateFunc: validation.IntBetween(900, 43200),
+							Description:  descriptions["assume_role_duration"],
+						},
+						"policy": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ValidateFunc: validation.ValidateJsonString,
+							Description:  descriptions["assume_role_policy"],
+						},
+					},
+				},
+			},
+			"shared_credentials_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("KSYUN_SHARED_CREDENTIALS_FILE", ""),
+				Description: descriptions["shared_credentials_file"],
+			},
+			"profile": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("KSYUN_PROFILE", ""),
+				Description: descriptions["profile"],
+			},
 			"region": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -452,6 +525,12 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("KSYUN_DRY_RUN", false),
 				Description: descriptions["dry_run"],
 			},
+			"dry_run_on_plan": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("KSYUN_DRY_RUN_ON_PLAN", false),
+				Description: descriptions["dry_run_on_plan"],
+			},
 			"ignore_service": {
 				Type:        schema.TypeBool,
 				Optional:    true,
@@ -475,6 +554,95 @@ func Provider() terraform.ResourceProvider {
 				Optional:     true,
 				Default:      0,
 				ValidateFunc: validation.IntBetween(0, 99),
+				Description:  descriptions["max_retries"],
+			},
+			"max_throttle_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3,
+				ValidateFunc: validation.IntBetween(0, 99),
+				Description:  descriptions["max_throttle_retries"],
+			},
+			"retry_min_delay": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      500,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  descriptions["retry_min_delay"],
+			},
+			"retry_max_delay": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      30000,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  descriptions["retry_max_delay"],
+			},
+			"rate_limit": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: descriptions["rate_limit"],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"service": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: descriptions["rate_limit_service"],
+						},
+						"rate": {
+							Type:         schema.TypeFloat,
+							Required:     true,
+							ValidateFunc: validation.FloatAtLeast(0.01),
+							Description:  descriptions["rate_limit_rate"],
+						},
+						"burst": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							Default:      1,
+							ValidateFunc: validation.IntAtLeast(1),
+							Description:  descriptions["rate_limit_burst"],
+						},
+					},
+				},
+			},
+			"default_tags": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: descriptions["default_tags"],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"tags": {
+							Type:        schema.TypeMap,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: descriptions["default_tags_tags"],
+						},
+					},
+				},
+			},
+			"ignore_tags": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: descriptions["ignore_tags"],
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"keys": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Set:         schema.HashString,
+							Description: descriptions["ignore_tags_keys"],
+						},
+						"key_prefixes": {
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Set:         schema.HashString,
+							Description: descriptions["ignore_tags_key_prefixes"],
+						},
+					},
+				},
 			},
 			"http_proxy": {
 				Type:     schema.TypeString,
@@ -508,11 +676,15 @@ func Provider() terraform.ResourceProvider {
 			"ksyun_network_interfaces":               dataSourceKsyunNetworkInterfaces(),
 			"ksyun_network_acls":                     dataSourceKsyunNetworkAcls(),
 			"ksyun_vpcs":                             dataSourceKsyunVpcs(),
+			"ksyun_vpc_peering_connections":          dataSourceKsyunVpcPeeringConnections(),
 			"ksyun_subnets":                          dataSourceKsyunSubnets(),
+			"ksyun_vpc_cidr_plan":                    dataSourceKsyunVpcCidrPlan(),
 			"ksyun_subnet_available_addresses":       dataSourceKsyunSubnetAvailableAddresses(),
 			"ksyun_subnet_allocated_ip_addresses":    dataSourceKsyunSubnetAllocatedIpAddresses(),
 			"ksyun_security_groups":                  dataSourceKsyunSecurityGroups(),
 			"ksyun_instances":                        dataSourceKsyunInstances(),
+			"ksyun_instance_types":                   dataSourceKsyunInstanceTypes(),
+			"ksyun_cloudinit_config":                 dataSourceKsyunCloudinitConfig(),
 			"ksyun_local_volumes":                    dataSourceKsyunLocalVolumes(),
 			"ksyun_local_snapshots":                  dataSourceKsyunLocalSnapshots(),
 			"ksyun_images":                           dataSourceKsyunImages(),
@@ -594,7 +766,8 @@ func Provider() terraform.ResourceProvider {
 			// clickhouse
 			"ksyun_clickhouse": dataSourceKsyunClickhouse(),
 			// cen
-			"ksyun_cens": dataSourceKsyunCens(),
+			"ksyun_cens":              dataSourceKsyunCens(),
+			"ksyun_cen_route_entries": dataSourceKsyunCenRouteEntries(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"ksyun_alb":                              resourceKsyunAlb(),
@@ -611,8 +784,13 @@ func Provider() terraform.ResourceProvider {
 			"ksyun_lb_acl_entry":                     resourceKsyunLoadBalancerAclEntry(),
 			"ksyun_lb_listener_associate_acl":        resourceKsyunListenerAssociateAcl(),
 			"ksyun_vpc":                              resourceKsyunVpc(),
+			"ksyun_vpc_peering_connection":           resourceKsyunVpcPeeringConnection(),
+			"ksyun_vpc_peering_connection_accepter":  resourceKsyunVpcPeeringConnectionAccepter(),
 			"ksyun_subnet":                           resourceKsyunSubnet(),
 			"ksyun_instance":                         resourceKsyunInstance(),
+			"ksyun_instance_group":                   resourceKsyunInstanceGroup(),
+			"ksyun_image":                            resourceKsyunImage(),
+			"ksyun_launch_template":                  resourceKsyunLaunchTemplate(),
 			"ksyun_sqlserver":                        resourceKsyunSqlServer(),
 			"ksyun_kec_network_interface":            resourceKsyunKecNetworkInterface(),
 			"ksyun_kec_network_interface_attachment": resourceKsyunKecNetworkInterfaceAttachment(),
@@ -634,6 +812,7 @@ func Provider() terraform.ResourceProvider {
 			"ksyun_volume":                           resourceKsyunVolume(),
 			"ksyun_volume_attach":                    resourceKsyunVolumeAttach(),
 			"ksyun_snapshot":                         resourceKsyunSnapshot(),
+			"ksyun_snapshot_copy":                    resourceKsyunSnapshotCopy(),
 			"ksyun_lb_rule":                          resourceKsyunSlbRule(),
 			"ksyun_lb_host_header":                   resourceKsyunListenerHostHeader(),
 			"ksyun_lb_backend_server_group":          resourceKsyunBackendServerGroup(),
@@ -652,6 +831,7 @@ func Provider() terraform.ResourceProvider {
 			"ksyun_network_acl":                      resourceKsyunNetworkAcl(),
 			"ksyun_network_acl_entry":                resourceKsyunNetworkAclEntry(),
 			"ksyun_network_acl_associate":            resourceKsyunNetworkAclAssociate(),
+			"ksyun_network_acl_rules":                resourceKsyunNetworkAclRules(),
 			"ksyun_vpn_gateway":                      resourceKsyunVpnGateway(),
 			"ksyun_vpn_customer_gateway":             resourceKsyunVpnCustomerGateway(),
 			"ksyun_vpn_tunnel":                       resourceKsyunVpnTunnel(),
@@ -707,7 +887,7 @@ func Provider() terraform.ResourceProvider {
 			"ksyun_security_group":            resourceKsyunSecurityGroup(),
 			"ksyun_security_group_entry":      resourceKsyunSecurityGroupEntry(),
 			"ksyun_security_group_entry_lite": resourceKsyunSecurityGroupEntryLite(),
-			// "ksyun_security_group_entry_set":  resourceKsyunSecurityGroupEntrySet(),
+			"ksyun_security_group_entry_set":  resourceKsyunSecurityGroupEntrySet(),
 
 			"ksyun_bare_metal_hot_standby_action": resourceKsyunBareMetalHotStandbyAction(),
 			// lb
@@ -732,10 +912,40 @@ func Provider() terraform.ResourceProvider {
 			// monitor
 			"ksyun_monitor_alarm_policy": resourceKsyunMonitorAlarmPolicy(),
 			// cen
-			"ksyun_cen": resourceKsyunCen(),
+			"ksyun_cen":                     resourceKsyunCen(),
+			"ksyun_cen_grant":               resourceKsyunCenGrant(),
+			"ksyun_cen_instance_attachment": resourceKsyunCenInstanceAttachment(),
+			"ksyun_cen_bandwidth_package":   resourceKsyunCenBandwidthPackage(),
+			"ksyun_cen_region_bandwidth":    resourceKsyunCenRegionBandwidth(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
+	withDefaultTags(provider.ResourcesMap)
+	withPlanDryRun(provider.ResourcesMap)
+	registerSensitiveFields(provider)
+	return provider
+}
+
+// registerSensitiveFields makes the logger redact the api parameters of the sensitive fields
+func registerSensitiveFields(provider *schema.Provider) {
+	var walk func(map[string]*schema.Schema)
+	walk = func(m map[string]*schema.Schema) {
+		for k, v := range m {
+			if v.Sensitive {
+				logger.RegisterSensitiveFields(k)
+			}
+			if elem, ok := v.Elem.(*schema.Resource); ok {
+				walk(elem.Schema)
+			}
+		}
+	}
+	walk(provider.Schema)
+	for _, r := range provider.ResourcesMap {
+		walk(r.Schema)
+	}
+	for _, r := range provider.DataSourcesMap {
+		walk(r.Schema)
+	}
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
@@ -743,19 +953,85 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	if mr, ok := d.GetOk("max_retries"); ok {
 		retryNum = mr.(int)
 	}
+	if d.Get("retry_max_delay").(int) < d.Get("retry_min_delay").(int) {
+		return nil, fmt.Errorf("retry_max_delay must not be less than retry_min_delay")
+	}
 	config := Config{
 		AccessKey:     d.Get("access_key").(string),
 		SecretKey:     d.Get("secret_key").(string),
+		SecurityToken: d.Get("security_token").(string),
 		Region:        d.Get("region").(string),
 		Insecure:      d.Get("insecure").(bool),
 		Domain:        d.Get("domain").(string),
 		Endpoint:      d.Get("endpoint").(string),
 		DryRun:        d.Get("dry_run").(bool),
+		DryRunOnPlan:  d.Get("dry_run_on_plan").(bool),
 		IgnoreService: d.Get("ignore_service").(bool),
 		HttpKeepAlive: d.Get("http_keepalive").(bool),
 		MaxRetries:    retryNum,
+		RetryMinDelay: time.Duration(d.Get("retry_min_delay").(int)) * time.Millisecond,
+		RetryMaxDelay: time.Duration(d.Get("retry_max_delay").(int)) * time.Millisecond,
 		HttpProxy:     d.Get("http_proxy").(string),
 		UseSSL:        d.Get("force_https").(bool),
+
+		MaxThrottleRetries: d.Get("max_throttle_retries").(int),
+
+		SharedCredentialsFile: d.Get("shared_credentials_file").(string),
+		Profile:               d.Get("profile").(string),
+	}
+	if v, ok := d.GetOk("assume_role"); ok {
+		for _, raw := range v.([]interface{}) {
+			assumeRole, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			config.AssumeRole = &AssumeRoleConfig{
+				RoleKrn:     assumeRole["role_krn"].(string),
+				SessionName: assumeRole["session_name"].(string),
+				Duration:    assumeRole["duration"].(int),
+				Policy:      assumeRole["policy"].(string),
+			}
+		}
+	}
+	if v, ok := d.GetOk("rate_limit"); ok {
+		services := make(map[string]bool)
+		for _, raw := range v.([]interface{}) {
+			rateLimit, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			service := strings.ToLower(rateLimit["service"].(string))
+			if services[service] {
+				return nil, fmt.Errorf("rate_limit of service %s is duplicated", service)
+			}
+			services[service] = true
+			config.RateLimits = append(config.RateLimits, RateLimitConfig{
+				Service: service,
+				Rate:    rateLimit["rate"].(float64),
+				Burst:   rateLimit["burst"].(int),
+			})
+		}
+	}
+	if v, ok := d.GetOk("default_tags"); ok {
+		for _, raw := range v.([]interface{}) {
+			defaultTags, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			config.DefaultTags = defaultTags["tags"].(map[string]interface{})
+		}
+	}
+	if v, ok := d.GetOk("ignore_tags"); ok {
+		for _, raw := range v.([]interface{}) {
+			ignoreTags, ok := raw.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			config.IgnoreTags = &IgnoreTagsConfig{
+				Keys:        SchemaSetToStringSlice(ignoreTags["keys"]),
+				KeyPrefixes: SchemaSetToStringSlice(ignoreTags["key_prefixes"]),
+			}
+		}
 	}
 	client, err := config.Client()
 	return client, err
@@ -767,11 +1043,40 @@ func init() {
 	descriptions = map[string]string{
 		"access_key":     "ak",
 		"secret_key":     "sk",
+		"security_token": "The security token of temporary credentials, it can also be sourced from the `KSYUN_SECURITY_TOKEN` environment variable.",
 		"region":         "cn-beijing-6",
 		"insecure":       "true",
 		"domain":         "",
 		"endpoint":       "",
 		"dry_run":        "false",
 		"ignore_service": "false",
+
+		"dry_run_on_plan": "Whether to send the creation calls of instances, eips and vpc resources with DryRun during plan, the permission and quota errors are reported before apply. It can also be sourced from the `KSYUN_DRY_RUN_ON_PLAN` environment variable.",
+
+		"max_retries":          "The max retry attempts number of a request failed with a temporary network error, it's retried with exponential backoff. The request may have been processed by the server, so a creation may be repeated when it's retried.",
+		"max_throttle_retries": "The max retry attempts number of a throttled request, it's retried with exponential backoff and independent of `max_retries`.",
+		"retry_min_delay":      "The minimum delay, in milliseconds, of the exponential backoff between retries.",
+		"retry_max_delay":      "The maximum delay, in milliseconds, of the exponential backoff between retries. The delay hinted by the `Retry-After` header of the server is capped by it as well.",
+
+		"rate_limit":         "The client-side rate limits of services, the requests exceeding the limit wait until they're allowed instead of being throttled by server.",
+		"rate_limit_service": "The service to limit, such as `vpc`, `slb`, `kec`, `krds`.",
+		"rate_limit_rate":    "The number of requests per second allowed to send to the service.",
+		"rate_limit_burst":   "The maximum number of requests allowed to send at once.",
+
+		"default_tags":      "The configuration of default tags applied to all resources having `tags` attribute.",
+		"default_tags_tags": "The default tags, the tags of resource win on conflict.",
+
+		"ignore_tags":              "The configuration of tags ignored by all resources having `tags` attribute, the ignored tags are neither read into state nor removed on update.",
+		"ignore_tags_keys":         "The tag keys to ignore.",
+		"ignore_tags_key_prefixes": "The tag key prefixes to ignore.",
+
+		"profile":                 "The profile name in the shared credentials file, it can also be sourced from the `KSYUN_PROFILE` environment variable. Default is `default`.",
+		"shared_credentials_file": "The path to the shared credentials file, it can also be sourced from the `KSYUN_SHARED_CREDENTIALS_FILE` environment variable. Default is `~/.ksyun/credentials`.",
+
+		"assume_role":              "The configuration of assuming an iam role. The provider uses the temporary credentials of the role and refreshes them before they expire.",
+		"assume_role_role_krn":     "The krn of the role to assume.",
+		"assume_role_session_name": "The session name to use when assuming the role.",
+		"assume_role_duration":     "The duration, in seconds, of the role session. Valid value range: 900-43200.",
+		"assume_role_policy":       "A more restrictive policy in json format to apply to the role session.",
 	}
 }
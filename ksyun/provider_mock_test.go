@@ -0,0 +1,107 @@
+package ksyun
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/mockapi"
+)
+
+// testMockProviderConfig points the provider to the mock api server, the extra arguments are
+// appended to the provider block.
+func testMockProviderConfig(s *mockapi.Server, extra ...string) string {
+	return fmt.Sprintf(`
+provider "ksyun" {
+  access_key     = "mock-access-key"
+  secret_key     = "mock-secret-key"
+  region         = "cn-beijing-6"
+  domain         = "%s"
+  ignore_service = true
+  max_retries    = 0
+  %s
+}
+`, s.Domain(), strings.Join(extra, "\n  "))
+}
+
+func testMockCheckDestroy(s *mockapi.Server, kinds ...string) resource.TestCheckFunc {
+	return func(*terraform.State) error {
+		for _, kind := range kinds {
+			if items := s.Items(kind); len(items) > 0 {
+				return fmt.Errorf("%d %s still exist", len(items), kind)
+			}
+		}
+		return nil
+	}
+}
+
+func testMockNetworkConfig(name string) string {
+	return fmt.Sprintf(`
+resource "ksyun_vpc" "foo" {
+  vpc_name   = "%[1]s"
+  cidr_block = "192.168.0.0/16"
+}
+
+resource "ksyun_subnet" "foo" {
+  subnet_name       = "%[1]s"
+  cidr_block        = "192.168.1.0/24"
+  subnet_type       = "Normal"
+  availability_zone = "cn-beijing-6a"
+  vpc_id            = ksyun_vpc.foo.id
+}
+
+resource "ksyun_security_group" "foo" {
+  vpc_id              = ksyun_vpc.foo.id
+  security_group_name = "tf-mock"
+}
+
+resource "ksyun_eip" "foo" {
+  band_width  = 1
+  charge_type = "PostPaidByDay"
+  tags = {
+    env = "test"
+  }
+}
+
+resource "ksyun_lb" "foo" {
+  vpc_id             = ksyun_vpc.foo.id
+  load_balancer_name = "%[1]s"
+  type               = "public"
+}
+`, name)
+}
+
+// testMockInstanceNetworkConfig is the vpc, subnet and security group of the instances
+const testMockInstanceNetworkConfig = `
+resource "ksyun_vpc" "foo" {
+  vpc_name   = "tf-mock"
+  cidr_block = "192.168.0.0/16"
+}
+
+resource "ksyun_subnet" "foo" {
+  subnet_name       = "tf-mock"
+  cidr_block        = "192.168.1.0/24"
+  subnet_type       = "Normal"
+  availability_zone = "cn-beijing-6a"
+  vpc_id            = ksyun_vpc.foo.id
+}
+
+resource "ksyun_security_group" "foo" {
+  vpc_id              = ksyun_vpc.foo.id
+  security_group_name = "tf-mock"
+}
+`
+
+func testMockInstanceConfig(name string) string {
+	return testMockInstanceNetworkConfig + fmt.Sprintf(`
+resource "ksyun_instance" "foo" {
+  image_id          = "IMG-mock"
+  instance_type     = "N3.2B"
+  subnet_id         = ksyun_subnet.foo.id
+  security_group_id = [ksyun_security_group.foo.id]
+  charge_type       = "Daily"
+  instance_name     = "%s"
+}
+`, name)
+}
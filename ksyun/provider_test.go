@@ -7,7 +7,12 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 	"log"
 	"os"
+	"path/filepath"
+	"regexp"
 	"testing"
+
+	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/mockapi"
+	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/recorder"
 )
 
 var testAccProviders map[string]terraform.ResourceProvider
@@ -31,6 +36,7 @@ func TestProvider_impl(t *testing.T) {
 }
 
 func testAccPreCheck(t *testing.T) {
+	testAccPreCheckFixture(t)
 	if v := os.Getenv("KSYUN_ACCESS_KEY"); v == "" {
 		t.Fatal("KSYUN_ACCESS_KEY must be set for acceptance tests")
 	}
@@ -61,3 +67,75 @@ func testAccCheckIDExists(n string) resource.TestCheckFunc {
 		return nil
 	}
 }
+
+// testAccPreCheckFixture points the provider to the cassette of the test when KSYUN_FIXTURE_MODE is set,
+// the cassettes are kept in testdata/fixtures and replayed with fake credentials.
+func testAccPreCheckFixture(t *testing.T) {
+	mode := recorder.ModeFromEnv()
+	if mode == recorder.ModeDisabled {
+		return
+	}
+	os.Setenv(recorder.CassetteEnv, filepath.Join("testdata", "fixtures", t.Name()+".json"))
+	if mode == recorder.ModeReplay {
+		os.Setenv("KSYUN_ACCESS_KEY", "fixture-access-key")
+		os.Setenv("KSYUN_SECRET_KEY", "fixture-secret-key")
+	}
+}
+
+func TestMockKsyunDryRunOnPlan(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testMockCheckDestroy(s, mockapi.KindVpc, mockapi.KindSubnet, mockapi.KindAddress),
+		Steps: []resource.TestStep{
+			{
+				// the subnet of an absent vpc fails during plan, nothing is created
+				Config: testMockProviderConfig(s, "dry_run_on_plan = true") + `
+resource "ksyun_eip" "foo" {
+  band_width  = 1
+  charge_type = "PostPaidByDay"
+}
+
+resource "ksyun_subnet" "foo" {
+  subnet_name       = "tf-mock"
+  cidr_block        = "192.168.1.0/24"
+  subnet_type       = "Normal"
+  availability_zone = "cn-beijing-6a"
+  vpc_id            = "absent-vpc"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("the dry run of CreateSubnet failed"),
+			},
+			{
+				Config: testMockProviderConfig(s, "dry_run_on_plan = true") + testMockNetworkConfig("tf-mock"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_eip.foo", "band_width", "1"),
+					resource.TestCheckResourceAttrPair("ksyun_subnet.foo", "vpc_id", "ksyun_vpc.foo", "id"),
+				),
+			},
+			{
+				// the cidr_block known after apply skips the dry run
+				Config: testMockProviderConfig(s, "dry_run_on_plan = true") + testMockNetworkConfig("tf-mock") + `
+resource "ksyun_vpc" "bar" {
+  vpc_name   = "tf-mock-bar"
+  cidr_block = "10.0.0.0/16"
+}
+
+resource "ksyun_subnet" "bar" {
+  subnet_name       = "tf-mock-bar"
+  cidr_block        = ksyun_vpc.bar.id == "" ? "" : "192.168.2.0/24"
+  subnet_type       = "Normal"
+  availability_zone = "cn-beijing-6a"
+  vpc_id            = ksyun_vpc.foo.id
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_subnet.bar", "cidr_block", "192.168.2.0/24"),
+				),
+			},
+		},
+	})
+}
@@ -214,7 +214,7 @@ func TestConnectionReset(t *testing.T) {
 				*cfg,
 				meta,
 				handlers,
-				network.GetKsyunRetryer(*cfg.MaxRetries),
+				network.GetKsyunRetryer(*cfg.MaxRetries, 0, 0, 0),
 				op,
 				&struct{}{},
 				&struct{}{},
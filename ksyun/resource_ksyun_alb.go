@@ -218,7 +218,8 @@ func resourceKsyunAlb() *schema.Resource {
 				},
 			},
 
-			"tags": tagsSchema(),
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 
 			// computed values
 			"create_time": {
@@ -9,10 +9,19 @@ Provides an auto snapshot policy resource.
 	  name   = "your auto snapshot policy name"
 	  auto_snapshot_date = [1,3,4,5]
 	  auto_snapshot_time = [1,3,4,5,9,22]
+	  retention_time     = 30
+	}
+
+	resource "ksyun_auto_snapshot_volume_association" "foo" {
+	  attach_volume_id        = "your volume id"
+	  auto_snapshot_policy_id = ksyun_auto_snapshot_policy.foo.id
 	}
 
 ```
 
+**Note** The snapshots created by the policy stay in the region of the volumes, use `ksyun_snapshot_copy` to copy the
+snapshots to another region.
+
 # Import
 
 `ksyun_auto_snapshot_policy` can be imported using the `id`, e.g.
@@ -84,7 +93,13 @@ func resourceKsyunAutoSnapshotPolicy() *schema.Resource {
 				Type:         schema.TypeInt,
 				Optional:     true,
 				ValidateFunc: validation.IntBetween(0, 9999),
-				Description:  "the snapshot will be reserved for when, the cap is 9999.",
+				Description:  "The retention days of the snapshots created by the policy, the cap is 9999. The snapshots are retained until they're deleted when it's unset.",
+			},
+			"volume_ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The ID list of the volumes which the policy is applied to. The associations created in the same apply are read on the next refresh.",
 			},
 			"creation_date": {
 				Type:        schema.TypeString,
@@ -149,7 +164,11 @@ func resourceKsyunAutoSnapshotPolicyRead(d *schema.ResourceData, meta interface{
 	}
 	SdkResponseAutoResourceData(d, r, result, extra)
 
-	return nil
+	volumeIds, err := snapshotSrv.readAutoSnapshotPolicyVolumeIds(d.Id())
+	if err != nil {
+		return fmt.Errorf("while query volumes of snapshot policy have encountered an error detail: %s", err)
+	}
+	return d.Set("volume_ids", volumeIds)
 }
 
 func resourceKsyunAutoSnapshotPolicyCreate(d *schema.ResourceData, meta interface{}) error {
@@ -7,6 +7,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 	_ "github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/mockapi"
 )
 
 func TestAccKsyunAutoSnapshot_basic(t *testing.T) {
@@ -118,3 +119,71 @@ resource "ksyun_auto_snapshot_policy" "foo" {
 }
 
 `
+
+func TestMockKsyunAutoSnapshotPolicy_basic(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testMockCheckDestroy(s, mockapi.KindAutoSnapshotPolicy, mockapi.KindInstance),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockProviderConfig(s) + testMockAutoSnapshotPolicyConfig(30),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_auto_snapshot_policy.foo", "retention_time", "30"),
+					func(state *terraform.State) error {
+						policyId := state.RootModule().Resources["ksyun_auto_snapshot_policy.foo"].Primary.ID
+						volumeId := state.RootModule().Resources["ksyun_instance.foo"].Primary.Attributes["data_disks.0.disk_id"]
+						for _, volume := range s.Items(mockapi.KindVolume) {
+							if volume["VolumeId"] == volumeId && volume["AutoSnapshotPolicyId"] == policyId {
+								return nil
+							}
+						}
+						return fmt.Errorf("the policy %s isn't applied to the volume %s", policyId, volumeId)
+					},
+				),
+			},
+			{
+				// the associated volumes are read once the association exists
+				Config: testMockProviderConfig(s) + testMockAutoSnapshotPolicyConfig(7),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_auto_snapshot_policy.foo", "retention_time", "7"),
+					resource.TestCheckResourceAttr("ksyun_auto_snapshot_policy.foo", "volume_ids.#", "1"),
+					resource.TestCheckResourceAttrPair("ksyun_auto_snapshot_policy.foo", "volume_ids.0",
+						"ksyun_instance.foo", "data_disks.0.disk_id"),
+				),
+			},
+		},
+	})
+}
+
+func testMockAutoSnapshotPolicyConfig(retention int) string {
+	return testMockInstanceNetworkConfig + fmt.Sprintf(`
+resource "ksyun_instance" "foo" {
+  image_id          = "IMG-mock"
+  instance_type     = "N3.2B"
+  subnet_id         = ksyun_subnet.foo.id
+  security_group_id = [ksyun_security_group.foo.id]
+  charge_type       = "Daily"
+  instance_name     = "tf-mock-auto-snapshot"
+  data_disks {
+    disk_type            = "SSD3.0"
+    disk_size            = 40
+    delete_with_instance = true
+  }
+}
+
+resource "ksyun_auto_snapshot_policy" "foo" {
+  name               = "tf-mock-auto-snapshot"
+  auto_snapshot_date = [1, 4]
+  auto_snapshot_time = [2]
+  retention_time     = %d
+}
+
+resource "ksyun_auto_snapshot_volume_association" "foo" {
+  attach_volume_id        = ksyun_instance.foo.data_disks.0.disk_id
+  auto_snapshot_policy_id = ksyun_auto_snapshot_policy.foo.id
+}
+`, retention)
+}
@@ -491,7 +491,8 @@ func resourceKsyunBareMetal() *schema.Resource {
 				Optional:    true,
 				Description: "Trial timed conversion to regular status, when charge_type is `Trial`. Valid Values: `support`, `unsupported`.",
 			},
-			"tags": tagsSchema(),
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 
 			"hot_standby": {
 				Type:     schema.TypeSet,
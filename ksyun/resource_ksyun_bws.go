@@ -77,7 +77,8 @@ func resourceKsyunBandWidthShare() *schema.Resource {
 				Default:     0,
 				Description: "ID of the project.",
 			},
-			"tags": tagsSchema(),
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 		},
 	}
 }
@@ -0,0 +1,155 @@
+/*
+Provides a cen bandwidth package resource, which purchases the bandwidth between two areas for the cen.
+The bandwidth of the package is allocated to the pairs of regions by `ksyun_cen_region_bandwidth`.
+
+# Example Usage
+
+```hcl
+
+	resource "ksyun_cen" "default" {
+	  cen_name = "tf-cen"
+	}
+
+	resource "ksyun_cen_bandwidth_package" "default" {
+	  cen_band_width_package_name = "tf-cen-bwp"
+	  cen_id                      = ksyun_cen.default.id
+	  local_area_id               = "the id of the local area"
+	  remote_area_id              = "the id of the remote area"
+	  package_band_width          = 10
+	  charge_type                 = "Monthly"
+	  purchase_time               = 1
+	}
+
+```
+
+# Import
+
+Cen bandwidth package can be imported using the `id`, e.g.
+
+```
+$ terraform import ksyun_cen_bandwidth_package.default xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+```
+*/
+
+package ksyun
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func resourceKsyunCenBandwidthPackage() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKsyunCenBandwidthPackageCreate,
+		Read:   resourceKsyunCenBandwidthPackageRead,
+		Update: resourceKsyunCenBandwidthPackageUpdate,
+		Delete: resourceKsyunCenBandwidthPackageDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			"cen_band_width_package_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The name of the bandwidth package.",
+			},
+			"cen_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The ID of the cen which the bandwidth package is attached to. The package is detached from the cen when it's removed.",
+			},
+			"local_area_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the local area.",
+			},
+			"remote_area_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the remote area.",
+			},
+			"package_band_width": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The bandwidth of the package, unit is 'Mbps'.",
+			},
+			"charge_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"Monthly",
+					"Daily",
+					"Peak",
+				}, false),
+				Description: "The charge type of the bandwidth package, valid values: 'Monthly', 'Daily', 'Peak'.",
+			},
+			"purchase_time": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.IntBetween(0, 36),
+				DiffSuppressFunc: purchaseTimeDiffSuppressFunc,
+				Description:      "The purchase time of the bandwidth package, value range [1, 36]. If charge_type is Monthly this Field is Required.",
+			},
+			"project_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "ID of the project.",
+			},
+			"create_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The time of creation.",
+			},
+		},
+	}
+}
+
+func resourceKsyunCenBandwidthPackageCreate(d *schema.ResourceData, meta interface{}) (err error) {
+	cenService := CenService{meta.(*KsyunClient)}
+	err = cenService.CreateCenBandwidthPackage(d, resourceKsyunCenBandwidthPackage())
+	if err != nil {
+		return fmt.Errorf("error on creating cen bandwidth package %q, %s", d.Id(), err)
+	}
+	return resourceKsyunCenBandwidthPackageRead(d, meta)
+}
+
+func resourceKsyunCenBandwidthPackageRead(d *schema.ResourceData, meta interface{}) (err error) {
+	cenService := CenService{meta.(*KsyunClient)}
+	err = cenService.ReadAndSetCenBandwidthPackage(d, resourceKsyunCenBandwidthPackage())
+	if err != nil {
+		if notFoundError(err) {
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("error on reading cen bandwidth package %q, %s", d.Id(), err)
+	}
+	return err
+}
+
+func resourceKsyunCenBandwidthPackageUpdate(d *schema.ResourceData, meta interface{}) (err error) {
+	cenService := CenService{meta.(*KsyunClient)}
+	err = cenService.ModifyCenBandwidthPackage(d, resourceKsyunCenBandwidthPackage())
+	if err != nil {
+		return fmt.Errorf("error on updating cen bandwidth package %q, %s", d.Id(), err)
+	}
+	return resourceKsyunCenBandwidthPackageRead(d, meta)
+}
+
+func resourceKsyunCenBandwidthPackageDelete(d *schema.ResourceData, meta interface{}) (err error) {
+	cenService := CenService{meta.(*KsyunClient)}
+	err = cenService.RemoveCenBandwidthPackage(d)
+	if err != nil {
+		return fmt.Errorf("error on deleting cen bandwidth package %q, %s", d.Id(), err)
+	}
+	return err
+}
@@ -0,0 +1,67 @@
+package ksyun
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/mockapi"
+)
+
+func TestMockKsyunCenBandwidthPackage_basic(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testMockCheckDestroy(s, mockapi.KindCen, mockapi.KindCenBandwidthPackage, mockapi.KindCenRegionBandwidth),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockProviderConfig(s) + testMockCenBandwidthPackageConfig(10, 5),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("ksyun_cen_bandwidth_package.foo", "cen_id", "ksyun_cen.foo", "id"),
+					resource.TestCheckResourceAttr("ksyun_cen_bandwidth_package.foo", "package_band_width", "10"),
+					resource.TestCheckResourceAttrPair("ksyun_cen_region_bandwidth.foo", "cen_id", "ksyun_cen.foo", "id"),
+					resource.TestCheckResourceAttr("ksyun_cen_region_bandwidth.foo", "inter_band_width", "5"),
+				),
+			},
+			{
+				// the region bandwidth is limited by the package, both are updated in place
+				Config: testMockProviderConfig(s) + testMockCenBandwidthPackageConfig(20, 15),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_cen_bandwidth_package.foo", "package_band_width", "20"),
+					resource.TestCheckResourceAttr("ksyun_cen_region_bandwidth.foo", "inter_band_width", "15"),
+				),
+			},
+			{
+				Config:      testMockProviderConfig(s) + testMockCenBandwidthPackageConfig(20, 25),
+				ExpectError: regexp.MustCompile("QuotaExceeded"),
+			},
+		},
+	})
+}
+
+func testMockCenBandwidthPackageConfig(packageBandWidth, interBandWidth int) string {
+	return fmt.Sprintf(`
+resource "ksyun_cen" "foo" {
+  cen_name = "tf-mock-cen-foo"
+}
+
+resource "ksyun_cen_bandwidth_package" "foo" {
+  cen_band_width_package_name = "tf-mock-bwp"
+  cen_id                      = ksyun_cen.foo.id
+  local_area_id               = "china"
+  remote_area_id              = "china"
+  package_band_width          = %d
+  charge_type                 = "Daily"
+}
+
+resource "ksyun_cen_region_bandwidth" "foo" {
+  cen_band_width_package_id = ksyun_cen_bandwidth_package.foo.id
+  local_region              = "cn-beijing-6"
+  remote_region             = "cn-shanghai-2"
+  inter_band_width          = %d
+}
+`, packageBandWidth, interBandWidth)
+}
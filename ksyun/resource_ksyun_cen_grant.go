@@ -0,0 +1,113 @@
+/*
+Provides a resource to grant a network instance to the cen of another account, so that the instance can be attached
+to the cen by the `ksyun_cen_instance_attachment` of that account.
+
+**Note** The grant is created by the account of the network instance.
+
+# Example Usage
+
+```hcl
+
+	resource "ksyun_cen_grant" "default" {
+	  cen_id              = "the id of the cen"
+	  cen_account_id      = "the account id of the cen"
+	  instance_type       = "Vpc"
+	  network_instance_id = ksyun_vpc.default.id
+	}
+
+```
+
+# Import
+
+Cen grant can be imported using the `id`, e.g.
+
+```
+$ terraform import ksyun_cen_grant.default xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+```
+*/
+
+package ksyun
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func resourceKsyunCenGrant() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKsyunCenGrantCreate,
+		Read:   resourceKsyunCenGrantRead,
+		Delete: resourceKsyunCenGrantDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			"cen_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the cen which the network instance is granted to.",
+			},
+			"cen_account_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The account ID of the cen.",
+			},
+			"instance_type": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"Vpc",
+					"DirectConnectGateway",
+				}, false),
+				Description: "The type of the network instance, valid values: 'Vpc', 'DirectConnectGateway'.",
+			},
+			"network_instance_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the network instance, which is the ID of vpc or direct connect gateway.",
+			},
+			"create_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The time of creation.",
+			},
+		},
+	}
+}
+
+func resourceKsyunCenGrantCreate(d *schema.ResourceData, meta interface{}) (err error) {
+	cenService := CenService{meta.(*KsyunClient)}
+	err = cenService.CreateCenGrant(d, resourceKsyunCenGrant())
+	if err != nil {
+		return fmt.Errorf("error on creating cen grant %q, %s", d.Id(), err)
+	}
+	return resourceKsyunCenGrantRead(d, meta)
+}
+
+func resourceKsyunCenGrantRead(d *schema.ResourceData, meta interface{}) (err error) {
+	cenService := CenService{meta.(*KsyunClient)}
+	err = cenService.ReadAndSetCenGrant(d, resourceKsyunCenGrant())
+	if err != nil {
+		if notFoundError(err) {
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("error on reading cen grant %q, %s", d.Id(), err)
+	}
+	return err
+}
+
+func resourceKsyunCenGrantDelete(d *schema.ResourceData, meta interface{}) (err error) {
+	cenService := CenService{meta.(*KsyunClient)}
+	err = cenService.RemoveCenGrant(d)
+	if err != nil {
+		return fmt.Errorf("error on deleting cen grant %q, %s", d.Id(), err)
+	}
+	return err
+}
@@ -0,0 +1,152 @@
+/*
+Provides a resource to attach a network instance, which is a VPC or a direct connect gateway, to the cen.
+
+**Note** The network instance of another account must be granted to the cen by the `ksyun_cen_grant` of that account
+before it's attached, and `instance_account_id` is required then.
+
+# Example Usage
+
+## same account
+
+```hcl
+
+	resource "ksyun_cen" "default" {
+	  cen_name = "tf-cen"
+	}
+
+	resource "ksyun_cen_instance_attachment" "default" {
+	  cen_id              = ksyun_cen.default.id
+	  instance_type       = "Vpc"
+	  network_instance_id = ksyun_vpc.default.id
+	}
+
+```
+
+## cross account
+
+```hcl
+
+	provider "ksyun" {
+	  alias = "owner"
+	  # the credentials of the account which owns the vpc
+	}
+
+	resource "ksyun_cen_grant" "default" {
+	  provider            = ksyun.owner
+	  cen_id              = ksyun_cen.default.id
+	  cen_account_id      = "the account id of the cen"
+	  instance_type       = "Vpc"
+	  network_instance_id = "the vpc id of the owner account"
+	}
+
+	resource "ksyun_cen_instance_attachment" "default" {
+	  cen_id              = ksyun_cen_grant.default.cen_id
+	  instance_type       = "Vpc"
+	  network_instance_id = ksyun_cen_grant.default.network_instance_id
+	  instance_account_id = "the id of the owner account"
+	}
+
+```
+
+# Import
+
+Cen instance attachment can be imported using the `id` which is formatted as `cen_id:network_instance_id`, e.g.
+
+```
+$ terraform import ksyun_cen_instance_attachment.default ${cen_id}:${network_instance_id}
+```
+*/
+
+package ksyun
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func resourceKsyunCenInstanceAttachment() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKsyunCenInstanceAttachmentCreate,
+		Read:   resourceKsyunCenInstanceAttachmentRead,
+		Delete: resourceKsyunCenInstanceAttachmentDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			"cen_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the cen.",
+			},
+			"instance_type": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"Vpc",
+					"DirectConnectGateway",
+				}, false),
+				Description: "The type of the network instance, valid values: 'Vpc', 'DirectConnectGateway'.",
+			},
+			"network_instance_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the network instance, which is the ID of vpc or direct connect gateway.",
+			},
+			"instance_region": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The region of the network instance. Default is the region of the provider.",
+			},
+			"instance_account_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The account ID of the network instance, it's required by the instance of another account.",
+			},
+			"create_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The time of creation.",
+			},
+		},
+	}
+}
+
+func resourceKsyunCenInstanceAttachmentCreate(d *schema.ResourceData, meta interface{}) (err error) {
+	cenService := CenService{meta.(*KsyunClient)}
+	err = cenService.CreateCenInstanceAttachment(d, resourceKsyunCenInstanceAttachment())
+	if err != nil {
+		return fmt.Errorf("error on creating cen instance attachment %q, %s", d.Id(), err)
+	}
+	return resourceKsyunCenInstanceAttachmentRead(d, meta)
+}
+
+func resourceKsyunCenInstanceAttachmentRead(d *schema.ResourceData, meta interface{}) (err error) {
+	cenService := CenService{meta.(*KsyunClient)}
+	err = cenService.ReadAndSetCenInstanceAttachment(d, resourceKsyunCenInstanceAttachment())
+	if err != nil {
+		if notFoundError(err) {
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("error on reading cen instance attachment %q, %s", d.Id(), err)
+	}
+	return err
+}
+
+func resourceKsyunCenInstanceAttachmentDelete(d *schema.ResourceData, meta interface{}) (err error) {
+	cenService := CenService{meta.(*KsyunClient)}
+	err = cenService.RemoveCenInstanceAttachment(d)
+	if err != nil {
+		return fmt.Errorf("error on deleting cen instance attachment %q, %s", d.Id(), err)
+	}
+	return err
+}
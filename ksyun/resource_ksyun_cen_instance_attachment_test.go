@@ -0,0 +1,83 @@
+package ksyun
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/mockapi"
+)
+
+func TestMockKsyunCenInstanceAttachment_basic(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		CheckDestroy: testMockCheckDestroy(s, mockapi.KindCen, mockapi.KindCenGrant, mockapi.KindCenNetworkInstance,
+			mockapi.KindCenRoute, mockapi.KindVpc),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockProviderConfig(s) + testMockCenInstanceAttachmentConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_cen_instance_attachment.foo", "instance_region", mockapi.DefaultRegion),
+					resource.TestCheckResourceAttr("ksyun_cen_instance_attachment.foo", "instance_account_id", mockapi.DefaultAccountId),
+					resource.TestCheckResourceAttr("ksyun_cen_instance_attachment.bar", "instance_account_id", "2000000001"),
+					resource.TestCheckResourceAttrPair("ksyun_cen_grant.bar", "network_instance_id", "ksyun_vpc.bar", "id"),
+				),
+			},
+			{
+				Config: testMockProviderConfig(s) + testMockCenInstanceAttachmentConfig + `
+data "ksyun_cen_route_entries" "foo" {
+  cen_id = ksyun_cen.foo.id
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ksyun_cen_route_entries.foo", "total_count", "2"),
+				),
+			},
+			{
+				Config:            testMockProviderConfig(s) + testMockCenInstanceAttachmentConfig,
+				ResourceName:      "ksyun_cen_instance_attachment.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+const testMockCenInstanceAttachmentConfig = `
+resource "ksyun_cen" "foo" {
+  cen_name = "tf-mock-cen"
+}
+
+resource "ksyun_vpc" "foo" {
+  vpc_name   = "tf-mock-foo"
+  cidr_block = "192.168.0.0/16"
+}
+
+resource "ksyun_vpc" "bar" {
+  vpc_name   = "tf-mock-bar"
+  cidr_block = "10.1.0.0/16"
+}
+
+resource "ksyun_cen_instance_attachment" "foo" {
+  cen_id              = ksyun_cen.foo.id
+  instance_type       = "Vpc"
+  network_instance_id = ksyun_vpc.foo.id
+}
+
+# the vpc of another account is granted to the cen before it's attached
+resource "ksyun_cen_grant" "bar" {
+  cen_id              = ksyun_cen.foo.id
+  cen_account_id      = "2000000000"
+  instance_type       = "Vpc"
+  network_instance_id = ksyun_vpc.bar.id
+}
+
+resource "ksyun_cen_instance_attachment" "bar" {
+  cen_id              = ksyun_cen_grant.bar.cen_id
+  instance_type       = "Vpc"
+  network_instance_id = ksyun_cen_grant.bar.network_instance_id
+  instance_account_id = "2000000001"
+}
+`
@@ -0,0 +1,118 @@
+/*
+Provides a cen region bandwidth resource, which allocates the bandwidth of the package to a pair of regions.
+
+**Note** The bandwidth package must be attached to the cen, and the regions must be in the areas of the package.
+
+# Example Usage
+
+```hcl
+
+	resource "ksyun_cen_region_bandwidth" "default" {
+	  cen_band_width_package_id = ksyun_cen_bandwidth_package.default.id
+	  local_region              = "cn-beijing-6"
+	  remote_region             = "cn-shanghai-2"
+	  inter_band_width          = 5
+	}
+
+```
+
+# Import
+
+Cen region bandwidth can be imported using the `id`, e.g.
+
+```
+$ terraform import ksyun_cen_region_bandwidth.default xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+```
+*/
+
+package ksyun
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func resourceKsyunCenRegionBandwidth() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKsyunCenRegionBandwidthCreate,
+		Read:   resourceKsyunCenRegionBandwidthRead,
+		Update: resourceKsyunCenRegionBandwidthUpdate,
+		Delete: resourceKsyunCenRegionBandwidthDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			"cen_band_width_package_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the bandwidth package.",
+			},
+			"local_region": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The local region.",
+			},
+			"remote_region": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The remote region.",
+			},
+			"inter_band_width": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The bandwidth between the regions, unit is 'Mbps'. It's limited by the bandwidth of the package.",
+			},
+			"cen_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the cen.",
+			},
+		},
+	}
+}
+
+func resourceKsyunCenRegionBandwidthCreate(d *schema.ResourceData, meta interface{}) (err error) {
+	cenService := CenService{meta.(*KsyunClient)}
+	err = cenService.CreateCenRegionBandwidth(d, resourceKsyunCenRegionBandwidth())
+	if err != nil {
+		return fmt.Errorf("error on creating cen region bandwidth %q, %s", d.Id(), err)
+	}
+	return resourceKsyunCenRegionBandwidthRead(d, meta)
+}
+
+func resourceKsyunCenRegionBandwidthRead(d *schema.ResourceData, meta interface{}) (err error) {
+	cenService := CenService{meta.(*KsyunClient)}
+	err = cenService.ReadAndSetCenRegionBandwidth(d, resourceKsyunCenRegionBandwidth())
+	if err != nil {
+		if notFoundError(err) {
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("error on reading cen region bandwidth %q, %s", d.Id(), err)
+	}
+	return err
+}
+
+func resourceKsyunCenRegionBandwidthUpdate(d *schema.ResourceData, meta interface{}) (err error) {
+	cenService := CenService{meta.(*KsyunClient)}
+	err = cenService.ModifyCenRegionBandwidth(d, resourceKsyunCenRegionBandwidth())
+	if err != nil {
+		return fmt.Errorf("error on updating cen region bandwidth %q, %s", d.Id(), err)
+	}
+	return resourceKsyunCenRegionBandwidthRead(d, meta)
+}
+
+func resourceKsyunCenRegionBandwidthDelete(d *schema.ResourceData, meta interface{}) (err error) {
+	cenService := CenService{meta.(*KsyunClient)}
+	err = cenService.RemoveCenRegionBandwidth(d)
+	if err != nil {
+		return fmt.Errorf("error on deleting cen region bandwidth %q, %s", d.Id(), err)
+	}
+	return err
+}
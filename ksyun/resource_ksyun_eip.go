@@ -106,7 +106,8 @@ func resourceKsyunEip() *schema.Resource {
 				Description: "The id of the project.",
 				// Computed:    true,
 			},
-			"tags": tagsSchema(),
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 
 			"instance_id": {
 				Type:        schema.TypeString,
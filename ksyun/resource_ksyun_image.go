@@ -0,0 +1,198 @@
+/*
+Provides a custom KEC image resource, the image is created from an instance or a system disk snapshot.
+
+~> **NOTE:** The copies of `copy_to_regions` are tracked by `copied_image_ids`, the copies made out of Terraform or
+before the image is imported are not tracked, and they're not deleted with the image. The copies are named as the image,
+so the image can't be copied to a region which has an image of the same name.
+
+# Example Usage
+
+```hcl
+
+	resource "ksyun_image" "default" {
+	  name        = "tf-golden-image"
+	  instance_id = ksyun_instance.default.id
+
+	  share_account_ids = ["2000000001"]
+	  copy_to_regions   = ["cn-shanghai-2"]
+	}
+
+```
+
+# Import
+
+Image can be imported using the `id`, e.g.
+
+```
+$ terraform import ksyun_image.default IMG-xxxxxxxx
+```
+*/
+package ksyun
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func resourceKsyunImage() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKsyunImageCreate,
+		Read:   resourceKsyunImageRead,
+		Update: resourceKsyunImageUpdate,
+		Delete: resourceKsyunImageDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the image.",
+			},
+			"instance_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"snapshot_id"},
+				Description:   "The ID of the instance which the image is created from. The instance should be stopped to keep the data consistent.",
+			},
+			"data_disk_ids": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set:           schema.HashString,
+				ConflictsWith: []string{"snapshot_id"},
+				Description:   "The ID of the data disks of the instance included in the image.",
+			},
+			"snapshot_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"instance_id"},
+				Description:   "The ID of the system disk snapshot which the image is created from.",
+			},
+			"share_account_ids": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set:         schema.HashString,
+				Description: "The ID of the accounts which the image is shared with.",
+			},
+			"copy_to_regions": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set:         schema.HashString,
+				Description: "The regions which the image is copied to. The image is copied once a region is added, the copy is deleted when the region is removed or the image is destroyed.",
+			},
+			"copied_image_ids": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The ID of the copies of the image, keyed by the region.",
+			},
+
+			"image_state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the image.",
+			},
+			"creation_date": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The creation time of the image.",
+			},
+			"platform": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The platform type of the image system.",
+			},
+			"image_source": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The source of the image.",
+			},
+			"sys_disk": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The size of the system disk.",
+			},
+			"progress": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The creation progress percentage of the image.",
+			},
+			"is_public": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the image is provided by ksyun.",
+			},
+			"is_npe": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether networking enhancement is supported.",
+			},
+			"cloud_init_support": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether cloud-init is supported.",
+			},
+			"real_image_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The real ID of the image.",
+			},
+		},
+	}
+}
+
+func resourceKsyunImageCreate(d *schema.ResourceData, meta interface{}) (err error) {
+	imageService := ImageService{meta.(*KsyunClient)}
+	err = imageService.CreateImage(d, resourceKsyunImage())
+	if err != nil {
+		return fmt.Errorf("error on creating image %q, %s", d.Id(), err)
+	}
+	return resourceKsyunImageRead(d, meta)
+}
+
+func resourceKsyunImageRead(d *schema.ResourceData, meta interface{}) (err error) {
+	imageService := ImageService{meta.(*KsyunClient)}
+	err = imageService.ReadAndSetImage(d, resourceKsyunImage())
+	if err != nil {
+		return fmt.Errorf("error on reading image %q, %s", d.Id(), err)
+	}
+	return err
+}
+
+func resourceKsyunImageUpdate(d *schema.ResourceData, meta interface{}) (err error) {
+	imageService := ImageService{meta.(*KsyunClient)}
+	err = imageService.ModifyImage(d, resourceKsyunImage())
+	if err != nil {
+		return fmt.Errorf("error on updating image %q, %s", d.Id(), err)
+	}
+	return resourceKsyunImageRead(d, meta)
+}
+
+func resourceKsyunImageDelete(d *schema.ResourceData, meta interface{}) (err error) {
+	imageService := ImageService{meta.(*KsyunClient)}
+	err = imageService.RemoveImage(d)
+	if err != nil {
+		return fmt.Errorf("error on deleting image %q, %s", d.Id(), err)
+	}
+	return err
+}
@@ -0,0 +1,220 @@
+package ksyun
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/mockapi"
+)
+
+func TestAccKsyunImage_basic(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		IDRefreshName: "ksyun_image.foo",
+		Providers:     testAccProviders,
+		CheckDestroy:  testAccCheckImageDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccImageConfig + `
+resource "ksyun_image" "foo" {
+  name        = "tf-acc-image"
+  instance_id = ksyun_instance.foo.id
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckImageExists("ksyun_image.foo"),
+					resource.TestCheckResourceAttr("ksyun_image.foo", "name", "tf-acc-image"),
+					resource.TestCheckResourceAttr("ksyun_image.foo", "image_state", "active"),
+				),
+			},
+			{
+				Config: testAccImageConfig + `
+resource "ksyun_image" "foo" {
+  name        = "tf-acc-image-update"
+  instance_id = ksyun_instance.foo.id
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckImageExists("ksyun_image.foo"),
+					resource.TestCheckResourceAttr("ksyun_image.foo", "name", "tf-acc-image-update"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckImageExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("not found: %s", n)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("image id is empty")
+		}
+		imageService := ImageService{testAccProvider.Meta().(*KsyunClient)}
+		_, err := imageService.ReadImage(nil, rs.Primary.ID)
+		return err
+	}
+}
+
+func testAccCheckImageDestroy(s *terraform.State) error {
+	imageService := ImageService{testAccProvider.Meta().(*KsyunClient)}
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "ksyun_image" {
+			continue
+		}
+		_, err := imageService.ReadImage(nil, rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("image %s still exist", rs.Primary.ID)
+		}
+		if !notFoundError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+const testAccImageConfig = `
+data "ksyun_images" "centos-7_5" {
+  platform = "centos-7.5"
+}
+data "ksyun_availability_zones" "default" {
+}
+resource "ksyun_vpc" "default" {
+  vpc_name   = "tf-acc-image-vpc"
+  cidr_block = "10.7.0.0/21"
+}
+resource "ksyun_subnet" "default" {
+  subnet_name       = "tf-acc-image-subnet"
+  cidr_block        = "10.7.0.0/21"
+  subnet_type       = "Normal"
+  vpc_id            = ksyun_vpc.default.id
+  gateway_ip        = "10.7.0.1"
+  availability_zone = data.ksyun_availability_zones.default.availability_zones.0.availability_zone_name
+}
+resource "ksyun_security_group" "default" {
+  vpc_id              = ksyun_vpc.default.id
+  security_group_name = "tf-acc-image-sg"
+}
+resource "ksyun_instance" "foo" {
+  image_id          = data.ksyun_images.centos-7_5.images.0.image_id
+  instance_type     = "N3.2B"
+  subnet_id         = ksyun_subnet.default.id
+  security_group_id = [ksyun_security_group.default.id]
+  charge_type       = "Daily"
+  instance_name     = "tf-acc-image-instance"
+  instance_status   = "stopped"
+}
+`
+
+func TestMockKsyunImage_basic(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testMockCheckDestroy(s, mockapi.KindImage, mockapi.KindInstance),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockProviderConfig(s) + testMockInstanceConfig("tf-mock") + `
+resource "ksyun_image" "foo" {
+  name              = "tf-mock-image"
+  instance_id       = ksyun_instance.foo.id
+  share_account_ids = ["2000000001"]
+  copy_to_regions   = ["cn-shanghai-2"]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_image.foo", "name", "tf-mock-image"),
+					resource.TestCheckResourceAttr("ksyun_image.foo", "image_state", "active"),
+					resource.TestCheckResourceAttrPair("ksyun_image.foo", "instance_id", "ksyun_instance.foo", "id"),
+					resource.TestCheckResourceAttr("ksyun_image.foo", "share_account_ids.#", "1"),
+					resource.TestCheckResourceAttr("ksyun_image.foo", "copied_image_ids.%", "1"),
+					testMockCheckImageCopies(s, "ksyun_image.foo", "cn-shanghai-2"),
+				),
+			},
+			{
+				Config: testMockProviderConfig(s) + testMockInstanceConfig("tf-mock") + `
+resource "ksyun_image" "foo" {
+  name              = "tf-mock-image-update"
+  instance_id       = ksyun_instance.foo.id
+  share_account_ids = ["2000000002", "2000000003"]
+  copy_to_regions   = ["cn-guangzhou-1"]
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_image.foo", "name", "tf-mock-image-update"),
+					resource.TestCheckResourceAttr("ksyun_image.foo", "share_account_ids.#", "2"),
+					resource.TestCheckResourceAttr("ksyun_image.foo", "copied_image_ids.%", "1"),
+					testMockCheckImageCopies(s, "ksyun_image.foo", "cn-guangzhou-1"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockKsyunImage_copyNameConflict(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		CheckDestroy: func(state *terraform.State) error {
+			// the unrelated image in the destination region is kept
+			images := s.Items(mockapi.KindImage)
+			if len(images) != 1 || images[0]["ImageId"] != "IMG-unrelated" {
+				return fmt.Errorf("expected only the unrelated image to be kept, got %v", images)
+			}
+			return testMockCheckDestroy(s, mockapi.KindInstance)(state)
+		},
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() {
+					s.Insert(mockapi.KindImage, map[string]interface{}{
+						"ImageId":            "IMG-unrelated",
+						"Name":               "tf-mock-image",
+						"ImageState":         "active",
+						"Region":             "cn-shanghai-2",
+						"SharePermissionSet": []interface{}{},
+					})
+				},
+				Config: testMockProviderConfig(s) + testMockInstanceConfig("tf-mock") + `
+resource "ksyun_image" "foo" {
+  name            = "tf-mock-image"
+  instance_id     = ksyun_instance.foo.id
+  copy_to_regions = ["cn-shanghai-2"]
+}
+`,
+				ExpectError: regexp.MustCompile(`the image named "tf-mock-image" already exists in region cn-shanghai-2`),
+			},
+		},
+	})
+}
+
+// testMockCheckImageCopies checks the copies of the image are exactly the ones tracked in the regions
+func testMockCheckImageCopies(s *mockapi.Server, name string, regions ...string) resource.TestCheckFunc {
+	return func(state *terraform.State) error {
+		attributes := state.RootModule().Resources[name].Primary.Attributes
+		copies := make(map[string]interface{})
+		for _, image := range s.Items(mockapi.KindImage) {
+			if image["OriginalImageId"] == attributes["id"] {
+				copies[image["Region"].(string)] = image["ImageId"]
+			}
+		}
+		if len(copies) != len(regions) {
+			return fmt.Errorf("expected the copies in %v, got %v", regions, copies)
+		}
+		for _, region := range regions {
+			if copies[region] == nil || copies[region] != attributes["copied_image_ids."+region] {
+				return fmt.Errorf("expected the copy in %s to be tracked, got %v", region, copies)
+			}
+		}
+		return nil
+	}
+}
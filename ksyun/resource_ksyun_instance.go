@@ -91,6 +91,22 @@ func instanceConfig() map[string]*schema.Schema {
 			}, false),
 			Description: "The state of instance.",
 		},
+		"stopped_mode": {
+			Type:     schema.TypeString,
+			Optional: true,
+			ValidateFunc: validation.StringInSlice([]string{
+				"KeepCharging",
+				"StopCharging",
+			}, false),
+			Description: "The mode of stopping the instance when `instance_status` is changed to `stopped`. Valid Values: 'KeepCharging', 'StopCharging'. " +
+				"`StopCharging` stops the billing of cpu and memory of the pay-as-you-go instance, they're not reserved and starting the instance may fail when the stock is insufficient.",
+		},
+		"stop_timeout": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "The seconds to wait for the instance to shut down gracefully when it's stopped, the instance is stopped forcibly after the timeout. The default value 0 means never stopping forcibly.",
+		},
 		"instance_type": {
 			Type:        schema.TypeString,
 			Optional:    true,
@@ -150,7 +166,6 @@ func instanceConfig() map[string]*schema.Schema {
 						Type:     schema.TypeString,
 						Optional: true,
 						Computed: true,
-						ForceNew: true,
 						ValidateFunc: validation.StringInSlice([]string{
 							"SSD3.0",
 							"EHDD",
@@ -160,15 +175,14 @@ func instanceConfig() map[string]*schema.Schema {
 							"ESSD_PL2",
 							"ESSD_PL3",
 						}, false),
-						Description: "Data disk type.",
+						Description: "Data disk type. The type of EBS data disk can be changed in place, changing from or to `Local_SSD` creates a new instance.",
 					},
 					"disk_size": {
 						Type:         schema.TypeInt,
 						Optional:     true,
 						Computed:     true,
-						ForceNew:     true,
 						ValidateFunc: validation.IntBetween(10, 16000),
-						Description:  "Data disk size. value range: [10, 16000].",
+						Description:  "Data disk size. value range: [10, 16000]. The EBS data disk is expanded online when the size grows, shrinking the disk creates a new instance.",
 					},
 					// 快照建盘（API不返回这个值，所以diff时忽略这个值）
 					"disk_snapshot_id": {
@@ -265,6 +279,24 @@ func instanceConfig() map[string]*schema.Schema {
 			ValidateFunc:     validation.IntBetween(0, 36),
 			Description:      "The duration that you will buy the resource.",
 		},
+		"spot_strategy": {
+			Type:     schema.TypeString,
+			Optional: true,
+			ForceNew: true,
+			ValidateFunc: validation.StringInSlice([]string{
+				"SpotAsPriceGo",
+				"SpotWithPriceLimit",
+			}, false),
+			Description: "The bidding strategy of the spot instance, only valid when `charge_type` is `HourlyInstantSettlement`. Valid Values: 'SpotAsPriceGo', 'SpotWithPriceLimit'. " +
+				"The spot instance is released when the market price exceeds the bid or the capacity is reclaimed.",
+		},
+		"spot_price_limit": {
+			Type:         schema.TypeFloat,
+			Optional:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.FloatAtLeast(0),
+			Description:  "The max hourly price of the spot instance, required when `spot_strategy` is `SpotWithPriceLimit`.",
+		},
 		"security_group_id": {
 			Type:        schema.TypeSet,
 			Elem:        &schema.Schema{Type: schema.TypeString},
@@ -325,7 +357,8 @@ func instanceConfig() map[string]*schema.Schema {
 			Optional: true,
 			// ForceNew:         true,
 			DiffSuppressFunc: kecImportDiffSuppress,
-			Description:      "The user data to be specified into this instance. Must be encrypted in base64 format and limited in 16 KB. only effective when image support cloud-init.",
+			ValidateFunc:     validation.StringLenBetween(0, kecUserDataMaxSize),
+			Description:      "The user data to be specified into this instance. Must be encrypted in base64 format and limited in 16 KB. only effective when image support cloud-init. The `ksyun_cloudinit_config` data source can render it.",
 		},
 		"iam_role_name": {
 			Type:        schema.TypeString,
@@ -350,7 +383,8 @@ func instanceConfig() map[string]*schema.Schema {
 			Computed:    true,
 			Description: "DNS2 of the primary network interface.",
 		},
-		"tags": tagsSchema(),
+		"tags":     tagsSchema(),
+		"tags_all": tagsAllSchema(),
 		// "has_init_info": {
 		//	Type:     schema.TypeBool,
 		//	Computed: true,
@@ -419,6 +453,27 @@ func instanceConfig() map[string]*schema.Schema {
 }
 
 func resourceKsyunInstance() *schema.Resource {
+	m := instanceConfig()
+	// the instance launched from a template takes these configs from the template
+	for _, k := range []string{"image_id", "subnet_id", "security_group_id"} {
+		m[k].Required = false
+		m[k].Optional = true
+		m[k].Computed = true
+		m[k].Description += " It's required unless `launch_template_id` is set."
+	}
+	m["launch_template_id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		ForceNew:    true,
+		Description: "The ID of the launch template to launch the instance. The configs of the template version are used unless they're set on the instance, including `image_id`, `instance_type`, `subnet_id`, `security_group_id`, `key_id`, `user_data`, `system_disk` and `data_disks`.",
+	}
+	m["launch_template_version"] = &schema.Schema{
+		Type:         schema.TypeInt,
+		Optional:     true,
+		ForceNew:     true,
+		ValidateFunc: validation.IntAtLeast(1),
+		Description:  "The version of the launch template, the latest version is used if it's not set.",
+	}
 	return &schema.Resource{
 		Create: resourceKsyunInstanceCreate,
 		Update: resourceKsyunInstanceUpdate,
@@ -427,12 +482,13 @@ func resourceKsyunInstance() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: kecInstanceCustomizeDiff,
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(30 * time.Minute),
 			Update: schema.DefaultTimeout(20 * time.Minute),
 			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
-		Schema: instanceConfig(),
+		Schema: m,
 	}
 }
 
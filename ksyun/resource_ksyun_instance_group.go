@@ -0,0 +1,381 @@
+/*
+Provides a group of identical KEC instances, which are launched in batch by one api call.
+
+**Note** The `instance_name` and `host_name` support the ordered suffix pattern `[begin,digits]`, e.g. `web-[1,3]`
+names the instances `web-001`, `web-002` and so on. The suffix keeps increasing from the highest one of the existing
+instances when the group scales out, so the names of the instances terminated before are never reused.
+Changing `instance_count` launches or terminates instances in place, the newest instances are terminated first
+when the group scales in. The instances terminated outside are launched again on the next apply. Changing any other
+argument creates a new group.
+
+# Example Usage
+
+```hcl
+
+	resource "ksyun_instance_group" "web" {
+	  image_id          = "IMG-xxxxxxxx"
+	  instance_type     = "N3.2B"
+	  subnet_id         = ksyun_subnet.default.id
+	  security_group_id = [ksyun_security_group.default.id]
+	  charge_type       = "HourlyInstantSettlement"
+	  instance_count    = 50
+	  instance_name     = "web-[1,3]"
+	  host_name         = "web-[1,3]"
+	  key_id            = [ksyun_ssh_key.default.id]
+
+	  system_disk {
+	    disk_type = "ESSD_SYSTEM_PL0"
+	    disk_size = 40
+	  }
+	}
+
+	output "web_private_ips" {
+	  value = ksyun_instance_group.web.instances.*.private_ip_address
+	}
+
+```
+*/
+
+package ksyun
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func resourceKsyunInstanceGroup() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceKsyunInstanceGroupCreate,
+		Read:          resourceKsyunInstanceGroupRead,
+		Update:        resourceKsyunInstanceGroupUpdate,
+		Delete:        resourceKsyunInstanceGroupDelete,
+		CustomizeDiff: kecInstanceGroupCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(15 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"instance_count": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The number of instances in the group. The instances are launched or terminated in place when it's changed.",
+			},
+			"min_instance_count": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description: "The minimum number of instances the group must have after launching, the launching fails when the stock can't satisfy it. " +
+					"Default is `instance_count`, which means all or nothing. The missing instances are planned again on the next apply.",
+			},
+			"image_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID for the image to use for the instances.",
+			},
+			"instance_type": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The type of the instances.",
+			},
+			"subnet_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of subnet.",
+			},
+			"security_group_id": {
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Required:    true,
+				ForceNew:    true,
+				Set:         schema.HashString,
+				MinItems:    1,
+				Description: "Security Group to associate with.",
+			},
+			"charge_type": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"Daily",
+					"HourlyInstantSettlement",
+				}, false),
+				Description: "charge type of the instances.",
+			},
+			"purchase_time": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: purchaseTimeDiffSuppressFunc,
+				ValidateFunc:     validation.IntBetween(0, 36),
+				Description:      "The duration that you will buy the resource.",
+			},
+			"instance_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The name of the instances, the ordered suffix pattern `[begin,digits]` is supported, e.g. `web-[1,3]`.",
+			},
+			"host_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The hostname of the instances, the ordered suffix pattern `[begin,digits]` is supported. only effective when image support cloud-init.",
+			},
+			"instance_password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Sensitive:   true,
+				Description: "Password to the instances is a string of 8 to 32 characters.",
+			},
+			"key_id": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				ForceNew: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Set:         schema.HashString,
+				Description: "The certificate id of the instances.",
+			},
+			"keep_image_login": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Keep the initial settings of the custom image.",
+			},
+			"system_disk": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MaxItems:    1,
+				Description: "System disk parameters.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"disk_type": {
+							Type:     schema.TypeString,
+							Optional: true,
+							ForceNew: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"SSD3.0",
+								"EHDD",
+								"Local_SSD",
+								"ESSD_SYSTEM_PL0",
+								"ESSD_SYSTEM_PL1",
+								"ESSD_SYSTEM_PL2",
+							}, false),
+							Description: "System disk type.",
+						},
+						"disk_size": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ForceNew:     true,
+							ValidateFunc: validation.IntBetween(20, 500),
+							Description:  "The size of the system disk. value range: [20, 500].",
+						},
+					},
+				},
+			},
+			"data_disk_gb": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(1, 16000),
+				Description:  "The size of the local SSD disk.",
+			},
+			"data_disks": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				MaxItems:    8,
+				Description: "The list of data disks created with each instance.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"disk_type": {
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"SSD3.0",
+								"EHDD",
+								"Local_SSD",
+								"ESSD_PL0",
+								"ESSD_PL1",
+								"ESSD_PL2",
+								"ESSD_PL3",
+							}, false),
+							Description: "Data disk type.",
+						},
+						"disk_size": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ForceNew:     true,
+							ValidateFunc: validation.IntBetween(10, 16000),
+							Description:  "Data disk size. value range: [10, 16000].",
+						},
+						"disk_snapshot_id": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							ForceNew:    true,
+							Description: "The snapshot id to create the data disk.",
+						},
+						"delete_with_instance": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+							ForceNew:    true,
+							Description: "Delete this data disk when the instance is destroyed. It only works on EBS disk. Default is `true`.",
+						},
+					},
+				},
+			},
+			"auto_create_ebs": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+				Description: "Whether to create EBS volumes from snapshots in the custom image, default is false.",
+			},
+			"sriov_net_support": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"true",
+					"false",
+				}, false),
+				Description: "whether support networking enhancement.",
+			},
+			"project_id": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The project the instances belong to.",
+			},
+			"data_guard_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Add the instances to a disaster tolerance group.",
+			},
+			"user_data": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringLenBetween(0, kecUserDataMaxSize),
+				Description:  "The user data of the instances. Must be encrypted in base64 format and limited in 16 KB. The `ksyun_cloudinit_config` data source can render it.",
+			},
+			"iam_role_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "name of iam role.",
+			},
+			"tags": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "the tags of the instances, they're attached when the instances are launched.",
+			},
+			"sync_tag": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				ForceNew:    true,
+				Description: "Indicate whether to sync tags to the disks of instances.",
+			},
+
+			"instance_ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The ID list of the instances, in the order of launching.",
+			},
+			"instances": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The instances of the group, in the order of launching.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"instance_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the instance.",
+						},
+						"instance_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the instance.",
+						},
+						"host_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The hostname of the instance.",
+						},
+						"private_ip_address": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The private IP address of the instance.",
+						},
+						"network_interface_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the primary network interface.",
+						},
+						"instance_state": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The state of the instance.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceKsyunInstanceGroupCreate(d *schema.ResourceData, meta interface{}) (err error) {
+	kecService := KecService{meta.(*KsyunClient)}
+	err = kecService.createKecInstanceGroup(d, resourceKsyunInstanceGroup())
+	if err != nil {
+		return fmt.Errorf("error on creating instance group: %s", err)
+	}
+	return resourceKsyunInstanceGroupRead(d, meta)
+}
+
+func resourceKsyunInstanceGroupRead(d *schema.ResourceData, meta interface{}) (err error) {
+	kecService := KecService{meta.(*KsyunClient)}
+	err = kecService.readAndSetKecInstanceGroup(d)
+	if err != nil {
+		return fmt.Errorf("error on reading instance group %q, %s", d.Id(), err)
+	}
+	return err
+}
+
+func resourceKsyunInstanceGroupUpdate(d *schema.ResourceData, meta interface{}) (err error) {
+	kecService := KecService{meta.(*KsyunClient)}
+	err = kecService.modifyKecInstanceGroup(d, resourceKsyunInstanceGroup())
+	if err != nil {
+		return fmt.Errorf("error on updating instance group %q, %s", d.Id(), err)
+	}
+	return resourceKsyunInstanceGroupRead(d, meta)
+}
+
+func resourceKsyunInstanceGroupDelete(d *schema.ResourceData, meta interface{}) (err error) {
+	kecService := KecService{meta.(*KsyunClient)}
+	err = kecService.removeKecInstanceGroup(d)
+	if err != nil {
+		return fmt.Errorf("error on deleting instance group %q, %s", d.Id(), err)
+	}
+	return err
+}
@@ -0,0 +1,238 @@
+package ksyun
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/mockapi"
+)
+
+func TestAccKsyunInstanceGroup_basic(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckInstanceGroupDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccInstanceGroupConfig(2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_instance_group.foo", "instance_ids.#", "2"),
+					resource.TestCheckResourceAttr("ksyun_instance_group.foo", "instances.1.instance_name", "tf-acc-group-002"),
+				),
+			},
+			{
+				Config: testAccInstanceGroupConfig(3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_instance_group.foo", "instance_ids.#", "3"),
+					resource.TestCheckResourceAttr("ksyun_instance_group.foo", "instances.2.instance_name", "tf-acc-group-003"),
+				),
+			},
+		},
+	})
+}
+
+func TestKecBatchName(t *testing.T) {
+	cases := []struct {
+		name   string
+		offset int
+		expect string
+	}{
+		{"web-[1,3]", 0, "web-[1,3]"},
+		{"web-[1,3]", 5, "web-[6,3]"},
+		{"web-[10,2]-node", 3, "web-[13,2]-node"},
+		{"web", 3, "web"},
+	}
+	for _, c := range cases {
+		if got := kecBatchName(c.name, c.offset); got != c.expect {
+			t.Errorf("kecBatchName(%q, %d) = %q, expect %q", c.name, c.offset, got, c.expect)
+		}
+	}
+}
+
+func TestKecBatchOffset(t *testing.T) {
+	cases := []struct {
+		name   string
+		names  []string
+		expect int
+	}{
+		{"web-[1,3]", nil, 0},
+		{"web-[1,3]", []string{"web-001", "web-002", "web-003"}, 3},
+		// the highest suffix wins even if a former instance is gone
+		{"web-[1,3]", []string{"web-001", "web-003"}, 3},
+		{"web-[10,2]-node", []string{"web-10-node", "web-123-node", "db-200-node"}, 114},
+		{"web", []string{"web", "web"}, 0},
+	}
+	for _, c := range cases {
+		if got := kecBatchOffset(c.name, c.names); got != c.expect {
+			t.Errorf("kecBatchOffset(%q, %v) = %d, expect %d", c.name, c.names, got, c.expect)
+		}
+	}
+}
+
+func testAccCheckInstanceGroupDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*KsyunClient)
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "ksyun_instance_group" {
+			continue
+		}
+		req := make(map[string]interface{})
+		for k, v := range rs.Primary.Attributes {
+			if strings.HasPrefix(k, "instance_ids.") && k != "instance_ids.#" {
+				req[fmt.Sprintf("InstanceId.%d", len(req)+1)] = v
+			}
+		}
+		if len(req) == 0 {
+			continue
+		}
+		resp, err := client.kecconn.DescribeInstances(&req)
+		if err != nil {
+			return err
+		}
+		if l, ok := (*resp)["InstancesSet"].([]interface{}); ok && len(l) > 0 {
+			return fmt.Errorf("instances of group %s still exist", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+func testAccInstanceGroupConfig(count int) string {
+	return fmt.Sprintf(`
+provider "ksyun" {
+	region =  "cn-beijing-6"
+}
+data "ksyun_images" "centos-7_5" {
+  platform= "centos-7.5"
+}
+data "ksyun_availability_zones" "default" {
+}
+resource "ksyun_vpc" "default" {
+  vpc_name   = "tf-acc-group-vpc"
+  cidr_block = "10.7.0.0/21"
+}
+resource "ksyun_subnet" "default" {
+  subnet_name       = "tf-acc-group-subnet"
+  cidr_block        = "10.7.0.0/21"
+  subnet_type       = "Normal"
+  vpc_id            = ksyun_vpc.default.id
+  availability_zone = data.ksyun_availability_zones.default.availability_zones.0.availability_zone_name
+}
+resource "ksyun_security_group" "default" {
+  vpc_id              = ksyun_vpc.default.id
+  security_group_name = "tf-acc-group-sg"
+}
+resource "ksyun_instance_group" "foo" {
+  image_id          = data.ksyun_images.centos-7_5.images.0.image_id
+  instance_type     = "N3.2B"
+  subnet_id         = ksyun_subnet.default.id
+  security_group_id = [ksyun_security_group.default.id]
+  charge_type       = "HourlyInstantSettlement"
+  instance_password = "Xuan663222"
+  instance_count    = %d
+  instance_name     = "tf-acc-group-[1,3]"
+  host_name         = "tf-acc-group-[1,3]"
+}
+`, count)
+}
+
+func TestMockKsyunInstanceGroup_basic(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	// the dry run requests are recorded as well, they're the same as the real ones
+	var runRequests []mockapi.Params
+	run := s.Action("RunInstances")
+	s.Handle("RunInstances", func(s *mockapi.Server, p mockapi.Params) (map[string]interface{}, error) {
+		runRequests = append(runRequests, p)
+		return run(s, p)
+	})
+	checkBatch := func(maxCount, instanceName string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			for _, p := range runRequests {
+				if p.String("MaxCount") != maxCount || p.String("InstanceName") != instanceName {
+					return fmt.Errorf("the instances aren't launched in one batch, %v", runRequests)
+				}
+			}
+			runRequests = nil
+			return nil
+		}
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testMockCheckDestroy(s, mockapi.KindInstance),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockProviderConfig(s) + testMockInstanceGroupConfig(3),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_instance_group.foo", "instance_ids.#", "3"),
+					resource.TestCheckResourceAttr("ksyun_instance_group.foo", "instances.0.instance_name", "web-001"),
+					resource.TestCheckResourceAttr("ksyun_instance_group.foo", "instances.2.instance_name", "web-003"),
+					resource.TestCheckResourceAttr("ksyun_instance_group.foo", "instances.2.host_name", "web-003"),
+					resource.TestCheckResourceAttr("ksyun_instance_group.foo", "instances.0.instance_state", mockapi.InstanceStateActive),
+					resource.TestCheckResourceAttrSet("ksyun_instance_group.foo", "instances.1.private_ip_address"),
+					checkBatch("3", "web-[1,3]"),
+				),
+			},
+			{
+				Config: testMockProviderConfig(s) + testMockInstanceGroupConfig(5),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_instance_group.foo", "instance_ids.#", "5"),
+					resource.TestCheckResourceAttr("ksyun_instance_group.foo", "instances.3.instance_name", "web-004"),
+					resource.TestCheckResourceAttr("ksyun_instance_group.foo", "instances.4.instance_name", "web-005"),
+					checkBatch("2", "web-[4,3]"),
+				),
+			},
+			{
+				PreConfig: func() {
+					// web-002 is terminated outside
+					for _, instance := range s.Items(mockapi.KindInstance) {
+						if instance["InstanceName"] == "web-002" {
+							_, _ = s.Action("TerminateInstances")(s, mockapi.Params{"InstanceId.1": instance["InstanceId"]})
+						}
+					}
+				},
+				// the missing instance is launched again after the highest suffix
+				Config: testMockProviderConfig(s) + testMockInstanceGroupConfig(5),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_instance_group.foo", "instance_count", "5"),
+					resource.TestCheckResourceAttr("ksyun_instance_group.foo", "instance_ids.#", "5"),
+					resource.TestCheckResourceAttr("ksyun_instance_group.foo", "instances.4.instance_name", "web-006"),
+					checkBatch("1", "web-[6,3]"),
+				),
+			},
+			{
+				Config: testMockProviderConfig(s) + testMockInstanceGroupConfig(2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_instance_group.foo", "instance_ids.#", "2"),
+					resource.TestCheckResourceAttr("ksyun_instance_group.foo", "instances.1.instance_name", "web-003"),
+					func(*terraform.State) error {
+						if n := len(s.Items(mockapi.KindInstance)); n != 2 {
+							return fmt.Errorf("expect 2 instances after scaling in, got %d", n)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func testMockInstanceGroupConfig(count int) string {
+	return testMockInstanceNetworkConfig + fmt.Sprintf(`
+resource "ksyun_instance_group" "foo" {
+  image_id          = "IMG-mock"
+  instance_type     = "N3.2B"
+  subnet_id         = ksyun_subnet.foo.id
+  security_group_id = [ksyun_security_group.foo.id]
+  charge_type       = "HourlyInstantSettlement"
+  instance_count    = %d
+  instance_name     = "web-[1,3]"
+  host_name         = "web-[1,3]"
+}
+`, count)
+}
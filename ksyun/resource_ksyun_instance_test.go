@@ -2,10 +2,12 @@ package ksyun
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/mockapi"
 )
 
 func TestAccKsyunInstance_basic(t *testing.T) {
@@ -374,3 +376,208 @@ resource "ksyun_instance" "foo" {
 }
 
 `
+
+func TestMockKsyunInstance_basic(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testMockCheckDestroy(s, mockapi.KindInstance, mockapi.KindNetworkInterface, mockapi.KindSubnet),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockProviderConfig(s) + testMockInstanceConfig("tf-mock"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_instance.foo", "instance_name", "tf-mock"),
+					resource.TestCheckResourceAttr("ksyun_instance.foo", "instance_status", mockapi.InstanceStateActive),
+					resource.TestCheckResourceAttr("ksyun_instance.foo", "private_ip_address", "192.168.1.2"),
+					resource.TestCheckResourceAttrPair("ksyun_instance.foo", "subnet_id", "ksyun_subnet.foo", "id"),
+				),
+			},
+			{
+				Config: testMockProviderConfig(s) + testMockInstanceConfig("tf-mock-update"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_instance.foo", "instance_name", "tf-mock-update"),
+				),
+			},
+		},
+	})
+}
+
+func TestMockKsyunInstance_dataDisks(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	var instanceId string
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testMockCheckDestroy(s, mockapi.KindInstance, mockapi.KindVolume),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockProviderConfig(s) + testMockDataDisksInstanceConfig("SSD3.0", 50),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_instance.foo", "data_disks.0.disk_size", "50"),
+					resource.TestCheckResourceAttrSet("ksyun_instance.foo", "data_disks.0.disk_id"),
+					func(st *terraform.State) error {
+						instanceId = st.RootModule().Resources["ksyun_instance.foo"].Primary.ID
+						return nil
+					},
+				),
+			},
+			{
+				// the data disk is upgraded and expanded in place
+				Config: testMockProviderConfig(s) + testMockDataDisksInstanceConfig("ESSD_PL1", 100),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_instance.foo", "data_disks.0.disk_type", "ESSD_PL1"),
+					resource.TestCheckResourceAttr("ksyun_instance.foo", "data_disks.0.disk_size", "100"),
+					func(st *terraform.State) error {
+						if id := st.RootModule().Resources["ksyun_instance.foo"].Primary.ID; id != instanceId {
+							return fmt.Errorf("the instance is recreated, %s != %s", id, instanceId)
+						}
+						volumes := s.Items(mockapi.KindVolume)
+						if len(volumes) != 1 || volumes[0]["Size"] != float64(100) {
+							return fmt.Errorf("the volume is not expanded, %v", volumes)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// shrinking the data disk creates a new instance
+				Config:             testMockProviderConfig(s) + testMockDataDisksInstanceConfig("ESSD_PL1", 60),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testMockDataDisksInstanceConfig(diskType string, diskSize int) string {
+	return testMockInstanceNetworkConfig + fmt.Sprintf(`
+resource "ksyun_instance" "foo" {
+  image_id          = "IMG-mock"
+  instance_type     = "N3.2B"
+  subnet_id         = ksyun_subnet.foo.id
+  security_group_id = [ksyun_security_group.foo.id]
+  charge_type       = "Daily"
+  instance_name     = "tf-mock-disks"
+  data_disks {
+    disk_type            = "%s"
+    disk_size            = %d
+    delete_with_instance = true
+  }
+}
+`, diskType, diskSize)
+}
+
+func TestMockKsyunInstance_stop(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	// the instance doesn't shut down until it's stopped forcibly
+	var stopRequests []mockapi.Params
+	s.Handle("StopInstances", func(s *mockapi.Server, p mockapi.Params) (map[string]interface{}, error) {
+		stopRequests = append(stopRequests, p)
+		instance, err := s.Get(mockapi.KindInstance, p.String("InstanceId.1"))
+		if err != nil {
+			return nil, err
+		}
+		state := "stopping"
+		if p.String("ForceStop") == "true" {
+			state = mockapi.InstanceStateStopped
+		}
+		instance["InstanceState"] = map[string]interface{}{"Name": state}
+		return map[string]interface{}{"InstancesSet": []interface{}{}}, nil
+	})
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testMockCheckDestroy(s, mockapi.KindInstance),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockProviderConfig(s) + testMockStopInstanceConfig("active"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_instance.foo", "instance_status", mockapi.InstanceStateActive),
+				),
+			},
+			{
+				Config: testMockProviderConfig(s) + testMockStopInstanceConfig("stopped"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_instance.foo", "instance_status", mockapi.InstanceStateStopped),
+					func(*terraform.State) error {
+						if len(stopRequests) != 2 || stopRequests[1].String("ForceStop") != "true" {
+							return fmt.Errorf("the instance isn't stopped forcibly after timeout, %v", stopRequests)
+						}
+						for _, p := range stopRequests {
+							if p.String("StoppedMode") != "StopCharging" {
+								return fmt.Errorf("the stopped mode isn't sent, %v", p)
+							}
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func testMockStopInstanceConfig(status string) string {
+	return testMockInstanceNetworkConfig + fmt.Sprintf(`
+resource "ksyun_instance" "foo" {
+  image_id          = "IMG-mock"
+  instance_type     = "N3.2B"
+  subnet_id         = ksyun_subnet.foo.id
+  security_group_id = [ksyun_security_group.foo.id]
+  charge_type       = "Daily"
+  instance_name     = "tf-mock-stop"
+  instance_status   = "%s"
+  stopped_mode      = "StopCharging"
+  stop_timeout      = 1
+}
+`, status)
+}
+
+func TestMockKsyunInstance_spot(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testMockCheckDestroy(s, mockapi.KindInstance, mockapi.KindSubnet),
+		Steps: []resource.TestStep{
+			{
+				Config:      testMockProviderConfig(s) + testMockSpotInstanceConfig("Daily", "SpotWithPriceLimit", 0.5),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("spot_strategy is only supported when charge_type is HourlyInstantSettlement"),
+			},
+			{
+				Config:      testMockProviderConfig(s) + testMockSpotInstanceConfig("HourlyInstantSettlement", "SpotWithPriceLimit", 0),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("spot_price_limit is required when spot_strategy is SpotWithPriceLimit"),
+			},
+			{
+				Config: testMockProviderConfig(s) + testMockSpotInstanceConfig("HourlyInstantSettlement", "SpotWithPriceLimit", 0.5),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_instance.foo", "spot_strategy", "SpotWithPriceLimit"),
+					resource.TestCheckResourceAttr("ksyun_instance.foo", "spot_price_limit", "0.5"),
+					resource.TestCheckResourceAttr("ksyun_instance.foo", "instance_status", mockapi.InstanceStateActive),
+				),
+			},
+		},
+	})
+}
+
+func testMockSpotInstanceConfig(chargeType, spotStrategy string, spotPriceLimit float64) string {
+	return testMockInstanceNetworkConfig + fmt.Sprintf(`
+resource "ksyun_instance" "foo" {
+  image_id          = "IMG-mock"
+  instance_type     = "N3.2B"
+  subnet_id         = ksyun_subnet.foo.id
+  security_group_id = [ksyun_security_group.foo.id]
+  charge_type       = "%s"
+  spot_strategy     = "%s"
+  spot_price_limit  = %v
+  instance_name     = "tf-mock-spot"
+}
+`, chargeType, spotStrategy, spotPriceLimit)
+}
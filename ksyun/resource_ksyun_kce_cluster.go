@@ -371,6 +371,22 @@ func instanceForNode() map[string]*schema.Schema {
 
 	m["security_group_id"].MaxItems = 1
 
+	// the nodes are stopped by kce
+	delete(m, "stopped_mode")
+	delete(m, "stop_timeout")
+	// the spot instances are not supported as nodes
+	delete(m, "spot_strategy")
+	delete(m, "spot_price_limit")
+	// the tags of the nodes are managed by kce
+	delete(m, "tags_all")
+
+	// the data disks of nodes can't be modified in place as the instances do
+	dataDisk := m["data_disks"].Elem.(*schema.Resource).Schema
+	dataDisk["disk_type"].ForceNew = true
+	dataDisk["disk_type"].Description = "Data disk type."
+	dataDisk["disk_size"].ForceNew = true
+	dataDisk["disk_size"].Description = "Data disk size. value range: [10, 16000]."
+
 	m["role"] = &schema.Schema{
 		Type:     schema.TypeString,
 		Optional: true,
@@ -1,6 +1,7 @@
 package ksyun
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
@@ -360,3 +361,16 @@ func TestHandleAdvancedSetting(t *testing.T) {
 	m := handleAdvancedSetting2Map(advanced)
 	t.Logf("%+v", m)
 }
+
+func TestFormatKceInstanceParaTagsAll(t *testing.T) {
+	if _, ok := instanceForNode()["tags_all"]; ok {
+		t.Errorf("Expected tags_all to be removed from the node config")
+	}
+	para := formatKceInstancePara(map[string]interface{}{
+		"instance_type": "S6.1A",
+		"tags_all":      map[string]interface{}{"env": "test"},
+	})
+	if strings.Contains(para, "TagsAll") {
+		t.Errorf("Expected tags_all to be ignored, got %s", para)
+	}
+}
@@ -377,7 +377,8 @@ func resourceKsyunKrds() *schema.Resource {
 				Description: "Set it to true to make some parameter efficient when modifying them. Default to false.",
 			},
 
-			"tags": tagsSchema(),
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 		},
 	}
 }
@@ -396,7 +397,7 @@ func resourceKsyunKrdsCreate(d *schema.ResourceData, meta interface{}) (err erro
 		return fmt.Errorf("error on creating instance , error is %e", err)
 	}
 	client := meta.(*KsyunClient)
-	if d.HasChange("tags") {
+	if hasTagsChange(d) {
 		tagService := TagService{client}
 		tagCall, err := tagService.ReplaceResourcesTagsWithResourceCall(d, resourceKsyunKrds(), "krds", false, true)
 		if err != nil {
@@ -431,7 +432,7 @@ func resourceKsyunKrdsUpdate(d *schema.ResourceData, meta interface{}) (err erro
 		return fmt.Errorf("error on updating instance , error is %e", err)
 	}
 	client := meta.(*KsyunClient)
-	if d.HasChange("tags") {
+	if hasTagsChange(d) {
 		tagService := TagService{client}
 		tagCall, err := tagService.ReplaceResourcesTagsWithResourceCall(d, resourceKsyunKrds(), "krds", false, true)
 		if err != nil {
@@ -128,7 +128,7 @@ func resourceKsyunKrdsRrCreate(d *schema.ResourceData, meta interface{}) (err er
 	}
 
 	client := meta.(*KsyunClient)
-	if d.HasChange("tags") {
+	if hasTagsChange(d) {
 		tagService := TagService{client}
 		tagCall, err := tagService.ReplaceResourcesTagsWithResourceCall(d, resourceKsyunKrds(), "krds", false, true)
 		if err != nil {
@@ -153,7 +153,7 @@ func resourceKsyunKrdsRrUpdate(d *schema.ResourceData, meta interface{}) (err er
 	}
 
 	client := meta.(*KsyunClient)
-	if d.HasChange("tags") {
+	if hasTagsChange(d) {
 		tagService := TagService{client}
 		tagCall, err := tagService.ReplaceResourcesTagsWithResourceCall(d, resourceKsyunKrds(), "krds", false, true)
 		if err != nil {
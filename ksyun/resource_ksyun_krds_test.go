@@ -7,6 +7,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/mockapi"
 )
 
 func TestAccKsyunKrds_basic(t *testing.T) {
@@ -228,3 +229,92 @@ resource "ksyun_tag" "test_tag" {
     resource_id = "${ksyun_krds.rds_terraform_3.id}"
 }
 `
+
+func TestMockKsyunKrds_ignoreTags(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	ignoreTags := `ignore_tags {
+    key_prefixes = ["ksc:scanner-"]
+  }`
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testMockCheckDestroy(s, mockapi.KindDBInstance, mockapi.KindSubnet, mockapi.KindVpc),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockProviderConfig(s, ignoreTags) + testMockKrdsConfig("test"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_krds.foo", "tags.%", "1"),
+					resource.TestCheckResourceAttr("ksyun_krds.foo", "tags.env", "test"),
+				),
+			},
+			{
+				PreConfig: func() {
+					// a tag added by the scanner out of band
+					krds := s.Items(mockapi.KindDBInstance)[0]
+					_, _ = s.Action("ReplaceResourcesTags")(s, mockapi.Params{
+						"ReplaceTags": []interface{}{map[string]interface{}{"ResourceUuids": krds["DBInstanceIdentifier"]}},
+						"Tag_1_Key":   "env",
+						"Tag_1_Value": "test",
+						"Tag_2_Key":   "ksc:scanner-id",
+						"Tag_2_Value": "s-1",
+					})
+				},
+				Config: testMockProviderConfig(s, ignoreTags) + testMockKrdsConfig("prod"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_krds.foo", "tags.%", "1"),
+					resource.TestCheckResourceAttr("ksyun_krds.foo", "tags.env", "prod"),
+					func(*terraform.State) error {
+						krds := s.Items(mockapi.KindDBInstance)[0]
+						resp, _ := s.Action("ListTagsByResourceIds")(s, mockapi.Params{
+							"ResourceUuids": krds["DBInstanceIdentifier"],
+						})
+						tags := make(map[string]interface{})
+						for _, tag := range resp["Tags"].([]interface{}) {
+							tags[tag.(map[string]interface{})["TagKey"].(string)] = tag.(map[string]interface{})["TagValue"]
+						}
+						if tags["env"] != "prod" || tags["ksc:scanner-id"] != "s-1" {
+							return fmt.Errorf("expected the ignored tag to be kept on update, got %v", tags)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func testMockKrdsConfig(env string) string {
+	return fmt.Sprintf(`
+resource "ksyun_vpc" "foo" {
+  vpc_name   = "tf-mock-krds"
+  cidr_block = "10.7.0.0/21"
+}
+
+resource "ksyun_subnet" "foo" {
+  subnet_name       = "tf-mock-krds"
+  cidr_block        = "10.7.0.0/21"
+  subnet_type       = "Reserve"
+  availability_zone = "cn-beijing-6a"
+  vpc_id            = ksyun_vpc.foo.id
+}
+
+resource "ksyun_krds" "foo" {
+  db_instance_class     = "db.ram.2|db.disk.21"
+  db_instance_name      = "tf-mock-krds"
+  db_instance_type      = "HRDS"
+  engine                = "mysql"
+  engine_version        = "5.7"
+  master_user_name      = "admin"
+  master_user_password  = "123qweASD123"
+  vpc_id                = ksyun_vpc.foo.id
+  subnet_id             = ksyun_subnet.foo.id
+  preferred_backup_time = "01:00-02:00"
+  availability_zone_1   = "cn-beijing-6a"
+  availability_zone_2   = "cn-beijing-6b"
+  tags = {
+    env = "%s"
+  }
+}
+`, env)
+}
@@ -421,7 +421,8 @@ func resourceKsyunKs3Bucket() *schema.Resource {
 				Description: "Bucket Policy is an authorization policy for Bucket introduced by KS3. You can authorize other users to access the KS3 resources you specify through the space policy. If you want to turn off this setting, just leave it blank in the configuration.",
 			},
 
-			"tags": tagsSchema(),
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 		},
 	}
 }
@@ -732,7 +733,7 @@ func resourceKsyunKs3BucketUpdate(d *schema.ResourceData, meta interface{}) erro
 		d.SetPartial("policy")
 	}
 
-	if d.HasChange("tags") {
+	if hasTagsChange(d) {
 		if err := resourceKsyunKs3BucketTaggingUpdate(client, d); err != nil {
 			return WrapError(err)
 		}
@@ -1007,8 +1008,24 @@ func resourceKsyunKs3BucketPolicyUpdate(client *KsyunClient, d *schema.ResourceD
 }
 
 func resourceKsyunKs3BucketTaggingUpdate(client *KsyunClient, d *schema.ResourceData) error {
-	tagsMap := d.Get("tags").(map[string]interface{})
+	tagsMap := client.mergeDefaultTags(d.Get("tags").(map[string]interface{}))
 	var requestInfo *ks3.Client
+
+	// keep the ignored tags, which are managed outside
+	if client.hasIgnoreTags() {
+		raw, err := client.WithKs3Client(func(ks3Client *ks3.Client) (interface{}, error) {
+			return ks3Client.GetBucketTagging(d.Id())
+		})
+		if err != nil {
+			return WrapErrorf(err, DefaultErrorMsg, d.Id(), "GetBucketTagging", KsyunKs3GoSdk)
+		}
+		tagging, _ := raw.(ks3.GetBucketTaggingResult)
+		for _, t := range tagging.Tags {
+			if _, ok := tagsMap[t.Key]; !ok && client.isIgnoredTag(t.Key) {
+				tagsMap[t.Key] = t.Value
+			}
+		}
+	}
 	if tagsMap == nil || len(tagsMap) == 0 {
 		raw, err := client.WithKs3Client(func(ks3Client *ks3.Client) (interface{}, error) {
 			requestInfo = ks3Client
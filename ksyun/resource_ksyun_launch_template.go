@@ -0,0 +1,217 @@
+/*
+Provides a launch template resource, which keeps the versions of the configs to launch KEC instances.
+
+Changing the instance configs of the template creates a new version, the former versions are kept and
+`latest_version_number` is updated. The `ksyun_instance` can be launched from the template by `launch_template_id`.
+
+# Example Usage
+
+```hcl
+
+	resource "ksyun_launch_template" "default" {
+	  launch_template_name = "tf-launch-template"
+	  image_id             = "IMG-xxxxxxxx"
+	  instance_type        = "N3.2B"
+	  subnet_id            = "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx"
+	  security_group_id    = ["xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx"]
+	  system_disk {
+	    disk_type = "Local_SSD"
+	    disk_size = 20
+	  }
+	  data_disks {
+	    disk_type            = "SSD3.0"
+	    disk_size            = 50
+	    delete_with_instance = true
+	  }
+	}
+
+	resource "ksyun_instance" "default" {
+	  launch_template_id = ksyun_launch_template.default.id
+	  charge_type        = "Daily"
+	  instance_name      = "tf-instance"
+	}
+
+```
+
+# Import
+
+Launch template can be imported using the `id`, e.g.
+
+```
+$ terraform import ksyun_launch_template.default xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+```
+*/
+
+package ksyun
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func resourceKsyunLaunchTemplate() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKsyunLaunchTemplateCreate,
+		Read:   resourceKsyunLaunchTemplateRead,
+		Update: resourceKsyunLaunchTemplateUpdate,
+		Delete: resourceKsyunLaunchTemplateDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Schema: map[string]*schema.Schema{
+			"launch_template_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the launch template.",
+			},
+			"image_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The ID of the image to launch the instances.",
+			},
+			"instance_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The type of the instances.",
+			},
+			"subnet_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The ID of the subnet of the instances.",
+			},
+			"security_group_id": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The IDs of the security groups of the instances.",
+			},
+			"key_id": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The certificate IDs of the instances.",
+			},
+			"user_data": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringLenBetween(0, kecUserDataMaxSize),
+				Description:  "The user data of the instances, which must be encoded in base64 and limited in 16 KB.",
+			},
+			"system_disk": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Description: "The system disk of the instances.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"disk_type": {
+							Type:     schema.TypeString,
+							Required: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"SSD3.0",
+								"EHDD",
+								"Local_SSD",
+								"ESSD_SYSTEM_PL0",
+								"ESSD_SYSTEM_PL1",
+								"ESSD_SYSTEM_PL2",
+							}, false),
+							Description: "The type of the system disk.",
+						},
+						"disk_size": {
+							Type:         schema.TypeInt,
+							Optional:     true,
+							ValidateFunc: validation.IntBetween(20, 500),
+							Description:  "The size of the system disk. value range: [20, 500].",
+						},
+					},
+				},
+			},
+			"data_disks": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    8,
+				Description: "The data disks of the instances.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"disk_type": {
+							Type:     schema.TypeString,
+							Required: true,
+							ValidateFunc: validation.StringInSlice([]string{
+								"SSD3.0",
+								"EHDD",
+								"Local_SSD",
+								"ESSD_PL0",
+								"ESSD_PL1",
+								"ESSD_PL2",
+								"ESSD_PL3",
+							}, false),
+							Description: "The type of the data disk.",
+						},
+						"disk_size": {
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntBetween(10, 16000),
+							Description:  "The size of the data disk. value range: [10, 16000].",
+						},
+						"delete_with_instance": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Delete the data disk when the instance is destroyed. It only works on EBS disk.",
+						},
+					},
+				},
+			},
+			"latest_version_number": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The latest version of the launch template, which is used by the instances without `launch_template_version`.",
+			},
+		},
+	}
+}
+
+func resourceKsyunLaunchTemplateCreate(d *schema.ResourceData, meta interface{}) (err error) {
+	launchTemplateService := LaunchTemplateService{meta.(*KsyunClient)}
+	err = launchTemplateService.CreateLaunchTemplate(d, resourceKsyunLaunchTemplate())
+	if err != nil {
+		return fmt.Errorf("error on creating launch template %q, %s", d.Id(), err)
+	}
+	return resourceKsyunLaunchTemplateRead(d, meta)
+}
+
+func resourceKsyunLaunchTemplateRead(d *schema.ResourceData, meta interface{}) (err error) {
+	launchTemplateService := LaunchTemplateService{meta.(*KsyunClient)}
+	err = launchTemplateService.ReadAndSetLaunchTemplate(d, resourceKsyunLaunchTemplate())
+	if err != nil {
+		if notFoundError(err) {
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("error on reading launch template %q, %s", d.Id(), err)
+	}
+	return err
+}
+
+func resourceKsyunLaunchTemplateUpdate(d *schema.ResourceData, meta interface{}) (err error) {
+	launchTemplateService := LaunchTemplateService{meta.(*KsyunClient)}
+	err = launchTemplateService.ModifyLaunchTemplate(d, resourceKsyunLaunchTemplate())
+	if err != nil {
+		return fmt.Errorf("error on updating launch template %q, %s", d.Id(), err)
+	}
+	return resourceKsyunLaunchTemplateRead(d, meta)
+}
+
+func resourceKsyunLaunchTemplateDelete(d *schema.ResourceData, meta interface{}) (err error) {
+	launchTemplateService := LaunchTemplateService{meta.(*KsyunClient)}
+	err = launchTemplateService.RemoveLaunchTemplate(d)
+	if err != nil {
+		return fmt.Errorf("error on deleting launch template %q, %s", d.Id(), err)
+	}
+	return err
+}
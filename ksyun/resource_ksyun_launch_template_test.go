@@ -0,0 +1,103 @@
+package ksyun
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/mockapi"
+)
+
+func TestMockKsyunLaunchTemplate_basic(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	var instanceId string
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testMockCheckDestroy(s, mockapi.KindLaunchTemplate, mockapi.KindInstance, mockapi.KindVolume),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockProviderConfig(s) + testMockLaunchTemplateConfig("N3.2B", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_launch_template.foo", "latest_version_number", "1"),
+					resource.TestCheckResourceAttr("ksyun_launch_template.foo", "data_disks.0.disk_size", "50"),
+					// the image set on the instance takes precedence over the template
+					resource.TestCheckResourceAttr("ksyun_instance.foo", "image_id", "IMG-foo"),
+					resource.TestCheckResourceAttr("ksyun_instance.foo", "instance_type", "N3.2B"),
+					resource.TestCheckResourceAttrPair("ksyun_instance.foo", "subnet_id", "ksyun_subnet.foo", "id"),
+					func(st *terraform.State) error {
+						instanceId = st.RootModule().Resources["ksyun_instance.foo"].Primary.ID
+						// the data disk of the template is created with the instance
+						volumes := s.Items(mockapi.KindVolume)
+						if len(volumes) != 1 || volumes[0]["Size"] != float64(50) {
+							return fmt.Errorf("the data disk of template is not created, %v", volumes)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// the change of template creates version 2, the instance pinned to version 1 is kept
+				Config: testMockProviderConfig(s) + testMockLaunchTemplateConfig("S6.2B", `
+resource "ksyun_instance" "bar" {
+  launch_template_id = ksyun_launch_template.foo.id
+  charge_type        = "Daily"
+  instance_name      = "tf-mock-bar"
+}
+`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_launch_template.foo", "latest_version_number", "2"),
+					resource.TestCheckResourceAttr("ksyun_launch_template.foo", "instance_type", "S6.2B"),
+					resource.TestCheckResourceAttr("ksyun_instance.foo", "instance_type", "N3.2B"),
+					resource.TestCheckResourceAttr("ksyun_instance.bar", "image_id", "IMG-mock"),
+					resource.TestCheckResourceAttr("ksyun_instance.bar", "instance_type", "S6.2B"),
+					func(st *terraform.State) error {
+						if id := st.RootModule().Resources["ksyun_instance.foo"].Primary.ID; id != instanceId {
+							return fmt.Errorf("the instance is recreated, %s != %s", id, instanceId)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testMockProviderConfig(s) + testMockInstanceNetworkConfig + `
+resource "ksyun_instance" "foo" {
+  instance_type     = "N3.2B"
+  subnet_id         = ksyun_subnet.foo.id
+  security_group_id = [ksyun_security_group.foo.id]
+  charge_type       = "Daily"
+}
+`,
+				ExpectError: regexp.MustCompile("image_id is required unless it's set in the launch template"),
+			},
+		},
+	})
+}
+
+func testMockLaunchTemplateConfig(instanceType string, extra string) string {
+	return testMockInstanceNetworkConfig + fmt.Sprintf(`
+resource "ksyun_launch_template" "foo" {
+  launch_template_name = "tf-mock"
+  image_id             = "IMG-mock"
+  instance_type        = "%s"
+  subnet_id            = ksyun_subnet.foo.id
+  security_group_id    = [ksyun_security_group.foo.id]
+  data_disks {
+    disk_type            = "SSD3.0"
+    disk_size            = 50
+    delete_with_instance = true
+  }
+}
+
+resource "ksyun_instance" "foo" {
+  launch_template_id      = ksyun_launch_template.foo.id
+  launch_template_version = 1
+  image_id                = "IMG-foo"
+  charge_type             = "Daily"
+  instance_name           = "tf-mock-foo"
+}
+%s`, instanceType, extra)
+}
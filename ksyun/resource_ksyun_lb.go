@@ -111,7 +111,8 @@ func resourceKsyunLb() *schema.Resource {
 				Description: "IP version, valid values: 'all', 'ipv4', 'ipv6'.",
 			},
 
-			"tags": tagsSchema(),
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 
 			"public_ip": {
 				Type:        schema.TypeString,
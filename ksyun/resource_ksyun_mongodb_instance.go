@@ -186,7 +186,8 @@ func resourceKsyunMongodbInstance() *schema.Resource {
 				Description: "the type of network.",
 			},
 
-			"tags": tagsSchema(),
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 
 			"user_id": {
 				Type:        schema.TypeString,
@@ -303,7 +304,7 @@ func resourceMongodbInstanceCreate(d *schema.ResourceData, meta interface{}) (er
 		return err
 	}
 	client := meta.(*KsyunClient)
-	if d.HasChange("tags") {
+	if hasTagsChange(d) {
 		tagService := TagService{client}
 		tagCall, err := tagService.ReplaceResourcesTagsWithResourceCall(d, resourceKsyunKrds(), "mongodb-instance", false, true)
 		if err != nil {
@@ -337,7 +338,7 @@ func resourceMongodbInstanceUpdate(d *schema.ResourceData, meta interface{}) (er
 		return err
 	}
 	client := meta.(*KsyunClient)
-	if d.HasChange("tags") {
+	if hasTagsChange(d) {
 		tagService := TagService{client}
 		tagCall, err := tagService.ReplaceResourcesTagsWithResourceCall(d, resourceKsyunKrds(), "mongodb-instance", false, true)
 		if err != nil {
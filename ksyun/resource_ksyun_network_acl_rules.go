@@ -0,0 +1,221 @@
+/*
+Provides a Network ACL Rules resource, which manages all the ingress and egress entries of a network ACL authoritatively.
+
+The rules of a direction are evaluated in the order of the list. A rule without `rule_number` is assigned a free number
+between its neighbouring rules, the number of an unchanged rule is kept, so that inserting a rule doesn't renumber the
+others.
+
+The rules fully shadowed by a former rule, which never take effect, are rejected on plan. A rule partially overlapped
+by a former rule with a different `rule_action` is accepted by default and not reported, as the usual patterns such as
+denying a host before allowing its network, or a final rule denying all the other traffic, are partial overlaps. The
+former rule wins for the overlapped traffic. Set `reject_overlaps` to reject such rules on plan too.
+
+~> **NOTE:** The entries of the network ACL which are not declared here, including the ones managed by
+`ksyun_network_acl_entry` or `network_acl_entries` of `ksyun_network_acl`, will be deleted.
+
+# Example Usage
+
+```hcl
+
+	resource "ksyun_network_acl" "default" {
+	  vpc_id           = "a8979fe2-cf1a-47b9-80f6-57445227c541"
+	  network_acl_name = "tf-acl"
+	}
+
+	resource "ksyun_network_acl_rules" "default" {
+	  network_acl_id = ksyun_network_acl.default.id
+	  ingress {
+	    rule_action     = "allow"
+	    protocol        = "tcp"
+	    cidr_block      = "10.0.0.0/8"
+	    port_range_from = 22
+	    port_range_to   = 22
+	  }
+	  ingress {
+	    rule_number = 100
+	    rule_action = "deny"
+	    protocol    = "ip"
+	    cidr_block  = "0.0.0.0/0"
+	  }
+	  egress {
+	    rule_action = "allow"
+	    protocol    = "ip"
+	    cidr_block  = "0.0.0.0/0"
+	  }
+	}
+
+```
+
+# Import
+
+Network ACL Rules can be imported using the `network_acl_id`, e.g.
+
+```
+$ terraform import ksyun_network_acl_rules.default fdeba8ca-8aa6-4cd0-8ffa-52ca9e9fef42
+```
+*/
+
+package ksyun
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func resourceKsyunNetworkAclRules() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKsyunNetworkAclRulesCreate,
+		Read:   resourceKsyunNetworkAclRulesRead,
+		Update: resourceKsyunNetworkAclRulesUpdate,
+		Delete: resourceKsyunNetworkAclRulesDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		CustomizeDiff: networkAclRulesCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"network_acl_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The id of the network acl.",
+			},
+			"reject_overlaps": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to reject the rules partially overlapped by a former rule with a different `rule_action` on plan. Such rules are accepted and not reported by default.",
+			},
+			"ingress": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        networkAclRuleElem(),
+				Description: "The ordered inbound rules of the network acl, a rule fully shadowed by a former one is rejected. The inbound entries not declared here are deleted.",
+			},
+			"egress": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        networkAclRuleElem(),
+				Description: "The ordered outbound rules of the network acl, a rule fully shadowed by a former one is rejected. The outbound entries not declared here are deleted.",
+			},
+		},
+	}
+}
+
+func networkAclRuleElem() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"rule_number": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, networkAclRuleNumberMax),
+				Description:  "The rule_number of the rule. value range:[1,32766]. It must be greater than the numbers of the former rules, if not set, a free number between the neighbouring rules is assigned.",
+			},
+			"rule_action": {
+				Type:     schema.TypeString,
+				Required: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"allow",
+					"deny",
+				}, false),
+				Description: "The rule_action of the rule. Valid Values: 'allow','deny'.",
+			},
+			"protocol": {
+				Type:     schema.TypeString,
+				Required: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"ip",
+					"tcp",
+					"udp",
+					"icmp",
+				}, false),
+				Description: "The protocol of the rule. Valid Values: 'ip','icmp','tcp','udp'.",
+			},
+			"cidr_block": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.IsCIDR,
+				Description:  "The cidr_block of the rule.",
+			},
+			"icmp_type": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The icmp_type of the rule. Only valid when protocol is icmp.",
+			},
+			"icmp_code": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "The icmp_code of the rule. Only valid when protocol is icmp.",
+			},
+			"port_range_from": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 65535),
+				Description:  "The port_range_from of the rule. Required when protocol is tcp or udp.",
+			},
+			"port_range_to": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(1, 65535),
+				Description:  "The port_range_to of the rule. Required when protocol is tcp or udp.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The description of the rule.",
+			},
+			"assigned_rule_number": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The rule number in effect, which is rule_number if set, otherwise the assigned one.",
+			},
+			"network_acl_entry_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "ID of the network acl entry.",
+			},
+		},
+	}
+}
+
+func resourceKsyunNetworkAclRulesCreate(d *schema.ResourceData, meta interface{}) (err error) {
+	vpcService := VpcService{meta.(*KsyunClient)}
+	err = vpcService.ApplyNetworkAclRules(d)
+	if err != nil {
+		return fmt.Errorf("error on creating network acl rules %q, %s", d.Get("network_acl_id"), err)
+	}
+	d.SetId(d.Get("network_acl_id").(string))
+	return resourceKsyunNetworkAclRulesRead(d, meta)
+}
+
+func resourceKsyunNetworkAclRulesRead(d *schema.ResourceData, meta interface{}) (err error) {
+	vpcService := VpcService{meta.(*KsyunClient)}
+	err = vpcService.ReadAndSetNetworkAclRules(d, resourceKsyunNetworkAclRules())
+	if err != nil {
+		if notFoundError(err) {
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("error on reading network acl rules %q, %s", d.Id(), err)
+	}
+	return err
+}
+
+func resourceKsyunNetworkAclRulesUpdate(d *schema.ResourceData, meta interface{}) (err error) {
+	vpcService := VpcService{meta.(*KsyunClient)}
+	err = vpcService.ApplyNetworkAclRules(d)
+	if err != nil {
+		return fmt.Errorf("error on updating network acl rules %q, %s", d.Id(), err)
+	}
+	return resourceKsyunNetworkAclRulesRead(d, meta)
+}
+
+func resourceKsyunNetworkAclRulesDelete(d *schema.ResourceData, meta interface{}) (err error) {
+	vpcService := VpcService{meta.(*KsyunClient)}
+	err = vpcService.RemoveNetworkAclRules(d)
+	if err != nil {
+		return fmt.Errorf("error on deleting network acl rules %q, %s", d.Id(), err)
+	}
+	return err
+}
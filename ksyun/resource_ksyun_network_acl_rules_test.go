@@ -0,0 +1,162 @@
+package ksyun
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/mockapi"
+)
+
+func TestMockKsyunNetworkAclRules_basic(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	var sshEntryId string
+	dns := `
+  ingress {
+    rule_action     = "allow"
+    protocol        = "udp"
+    cidr_block      = "0.0.0.0/0"
+    port_range_from = 53
+    port_range_to   = 53
+  }`
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testMockCheckDestroy(s, mockapi.KindNetworkAcl, mockapi.KindVpc),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockProviderConfig(s) + testMockNetworkAclRulesConfig("ssh", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_network_acl_rules.foo", "ingress.#", "2"),
+					resource.TestCheckResourceAttr("ksyun_network_acl_rules.foo", "ingress.0.rule_number", "0"),
+					resource.TestCheckResourceAttr("ksyun_network_acl_rules.foo", "ingress.0.assigned_rule_number", "10"),
+					resource.TestCheckResourceAttr("ksyun_network_acl_rules.foo", "ingress.1.assigned_rule_number", "100"),
+					resource.TestCheckResourceAttr("ksyun_network_acl_rules.foo", "egress.0.assigned_rule_number", "10"),
+					func(*terraform.State) error {
+						entries := testMockNetworkAclEntries(s)
+						if len(entries) != 3 {
+							return fmt.Errorf("expected 3 entries, got %v", entries)
+						}
+						for _, entry := range entries {
+							if entry["Protocol"] == "tcp" {
+								sshEntryId = entry["NetworkAclEntryId"].(string)
+							}
+						}
+						return nil
+					},
+				),
+			},
+			{
+				PreConfig: func() {
+					// an unmanaged entry added out of band
+					acl := s.Items(mockapi.KindNetworkAcl)[0]
+					_, _ = s.Action("CreateNetworkAclEntry")(s, mockapi.Params{
+						"NetworkAclId": acl["NetworkAclId"],
+						"Direction":    "in",
+						"RuleNumber":   "200",
+						"RuleAction":   "allow",
+						"Protocol":     "ip",
+						"CidrBlock":    "172.16.0.0/12",
+					})
+				},
+				Config: testMockProviderConfig(s) + testMockNetworkAclRulesConfig("ssh-updated", dns),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_network_acl_rules.foo", "ingress.#", "3"),
+					resource.TestCheckResourceAttr("ksyun_network_acl_rules.foo", "ingress.0.assigned_rule_number", "10"),
+					resource.TestCheckResourceAttr("ksyun_network_acl_rules.foo", "ingress.1.assigned_rule_number", "20"),
+					resource.TestCheckResourceAttr("ksyun_network_acl_rules.foo", "ingress.2.assigned_rule_number", "100"),
+					func(*terraform.State) error {
+						entries := testMockNetworkAclEntries(s)
+						if len(entries) != 4 {
+							return fmt.Errorf("expected 4 entries, got %v", entries)
+						}
+						for _, entry := range entries {
+							if entry["CidrBlock"] == "172.16.0.0/12" {
+								return fmt.Errorf("the unmanaged entry %v is not deleted", entry)
+							}
+							if entry["Protocol"] == "tcp" {
+								if entry["NetworkAclEntryId"] != sshEntryId || entry["Description"] != "ssh-updated" {
+									return fmt.Errorf("the description of %v is expected to be modified in place", entry)
+								}
+							}
+						}
+						return nil
+					},
+				),
+			},
+			{
+				// the dns rule is shadowed by the deny rule
+				Config: testMockProviderConfig(s) + strings.Replace(testMockNetworkAclRulesConfig("ssh-updated", ""),
+					"  egress {", dns[1:]+"\n  egress {", 1),
+				ExpectError: regexp.MustCompile(`ingress.2 is shadowed by ingress.1`),
+			},
+			{
+				// the deny rule partially overlaps the ssh rule
+				Config: testMockProviderConfig(s) + strings.Replace(testMockNetworkAclRulesConfig("ssh-updated", dns),
+					"  network_acl_id = ksyun_network_acl.foo.id\n",
+					"  network_acl_id  = ksyun_network_acl.foo.id\n  reject_overlaps = true\n", 1),
+				ExpectError: regexp.MustCompile(`ingress.2 is partially overlapped by ingress.0`),
+			},
+			{
+				Config:            testMockProviderConfig(s) + testMockNetworkAclRulesConfig("ssh-updated", dns),
+				ResourceName:      "ksyun_network_acl_rules.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// the imported rules keep the assigned numbers as rule_number
+				ImportStateVerifyIgnore: []string{"ingress.0.rule_number", "ingress.1.rule_number", "egress.0.rule_number", "reject_overlaps"},
+			},
+		},
+	})
+}
+
+func testMockNetworkAclEntries(s *mockapi.Server) []map[string]interface{} {
+	var entries []map[string]interface{}
+	for _, acl := range s.Items(mockapi.KindNetworkAcl) {
+		for _, entry := range acl["NetworkAclEntrySet"].([]interface{}) {
+			entries = append(entries, entry.(map[string]interface{}))
+		}
+	}
+	return entries
+}
+
+// testMockNetworkAclRulesConfig declares an ssh rule with description, the extra ingress rules and a deny rule
+func testMockNetworkAclRulesConfig(description, extra string) string {
+	return fmt.Sprintf(`
+resource "ksyun_vpc" "foo" {
+  vpc_name   = "tf-mock-acl"
+  cidr_block = "192.168.0.0/16"
+}
+
+resource "ksyun_network_acl" "foo" {
+  vpc_id           = ksyun_vpc.foo.id
+  network_acl_name = "tf-mock-acl"
+}
+
+resource "ksyun_network_acl_rules" "foo" {
+  network_acl_id = ksyun_network_acl.foo.id
+  ingress {
+    description     = "%s"
+    rule_action     = "allow"
+    protocol        = "tcp"
+    cidr_block      = "10.0.0.0/8"
+    port_range_from = 22
+    port_range_to   = 22
+  }%s
+  ingress {
+    rule_number = 100
+    rule_action = "deny"
+    protocol    = "ip"
+    cidr_block  = "0.0.0.0/0"
+  }
+  egress {
+    rule_action = "allow"
+    protocol    = "ip"
+    cidr_block  = "0.0.0.0/0"
+  }
+}
+`, description, extra)
+}
@@ -394,7 +394,8 @@ func resourceRedisInstance() *schema.Resource {
 				Description: "project name.",
 			},
 
-			"tags": tagsSchema(),
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 		},
 	}
 }
@@ -487,7 +488,7 @@ func resourceRedisInstanceCreate(d *schema.ResourceData, meta interface{}) error
 	}
 
 	client := meta.(*KsyunClient)
-	if d.HasChange("tags") {
+	if hasTagsChange(d) {
 		tagService := TagService{client}
 		tagCall, err := tagService.ReplaceResourcesTagsWithResourceCall(d, resourceKsyunKrds(), "redis-instance", false, true)
 		if err != nil {
@@ -594,7 +595,7 @@ func resourceRedisInstanceUpdate(d *schema.ResourceData, meta interface{}) (err
 	err = d.Set("reset_all_parameters", d.Get("reset_all_parameters"))
 
 	client := meta.(*KsyunClient)
-	if d.HasChange("tags") {
+	if hasTagsChange(d) {
 		tagService := TagService{client}
 		tagCall, err := tagService.ReplaceResourcesTagsWithResourceCall(d, resourceKsyunKrds(), "redis-instance", false, true)
 		if err != nil {
@@ -646,7 +647,7 @@ func resourceRedisInstanceRead(d *schema.ResourceData, meta interface{}) error {
 		Field: "capacity",
 	}
 
-	if _, ok := d.GetOk("tags"); ok {
+	if hasTags(d, meta.(*KsyunClient)) {
 		err = mergeTagsData(d, &item, meta.(*KsyunClient), "redis-instance")
 		if err != nil {
 			return fmt.Errorf("reading tags error: %s", err)
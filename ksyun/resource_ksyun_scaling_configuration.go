@@ -12,6 +12,15 @@ Provides a ScalingConfiguration resource.
 	  password = "Aa123456"
 	}
 
+	resource "ksyun_scaling_configuration" "spot" {
+	  scaling_configuration_name = "tf-spot"
+	  image_id = "IMG-5465174a-6d71-4770-b8e1-917a0dd92466"
+	  instance_type = "N3.1B"
+	  password = "Aa123456"
+	  spot_strategy = "SpotWithPriceLimit"
+	  spot_price_limit = 0.5
+	}
+
 ```
 
 # Import
@@ -42,6 +51,7 @@ func resourceKsyunScalingConfiguration() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: spotStrategyCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 
 			"scaling_configuration_name": {
@@ -234,6 +244,25 @@ func resourceKsyunScalingConfiguration() *schema.Resource {
 				Description: "Charge type.",
 			},
 
+			"spot_strategy": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"SpotAsPriceGo",
+					"SpotWithPriceLimit",
+				}, false),
+				Description: "The bidding strategy of the spot instances created by the desired ScalingConfiguration. Valid Values: 'SpotAsPriceGo', 'SpotWithPriceLimit'.",
+			},
+
+			"spot_price_limit": {
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.FloatAtLeast(0),
+				Description:  "The max hourly price of the spot instances, required when `spot_strategy` is `SpotWithPriceLimit`.",
+			},
+
 			"cpu": {
 				Type:        schema.TypeInt,
 				Computed:    true,
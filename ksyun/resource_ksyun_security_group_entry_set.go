@@ -1,32 +1,46 @@
 /*
-Provides a Security Group Entry resource.
+Provides a Security Group Entry Set resource, which manages all the entries of a security group authoritatively.
+
+~> **NOTE:** The entries of the security group which are not declared in `security_group_entries`, including the default
+outbound entry created with the security group and the entries added by `ksyun_security_group_entry`, will be revoked.
+An empty `security_group_entries` revokes all the entries of the security group. The missing entries are authorized
+before the stale ones are revoked, unless the quota of entries is exceeded, then the stale ones are revoked at first.
+The entries differing only in `description` are rejected on plan.
 
 # Example Usage
 
 ```hcl
 
 	resource "ksyun_security_group_entry_set" "default" {
-	  security_group_id="7385c8ea-79f7-4e9c-b99f-517fc3726256"
-	  cidr_block="10.0.0.1/32"
-	  direction="in"
-	  protocol="ip"
+	  security_group_id = "7385c8ea-79f7-4e9c-b99f-517fc3726256"
+	  security_group_entries {
+	    cidr_block = "10.0.0.1/32"
+	    direction  = "in"
+	    protocol   = "tcp"
+	    port_range_from = 22
+	    port_range_to   = 22
+	  }
+	  security_group_entries {
+	    cidr_block = "0.0.0.0/0"
+	    direction  = "out"
+	    protocol   = "ip"
+	  }
 	}
 
 ```
 
 # Import
 
-Security Group Entry can be imported using the `id`, e.g.
+Security Group Entry Set can be imported using the `security_group_id`, e.g.
 
 ```
-$ terraform import ksyun_security_group_entry.example xxxxxxxx-abc123456
+$ terraform import ksyun_security_group_entry_set.example xxxxxxxx-abc123456
 ```
 */
 
 package ksyun
 
 import (
-	"context"
 	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -49,40 +63,32 @@ func resourceKsyunSecurityGroupEntrySet() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		CustomizeDiff: securityGroupEntrySetCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"security_group_id": {
 				Type:        schema.TypeString,
 				Required:    true,
+				ForceNew:    true,
 				Description: "The ID of the security group.",
 			},
 			"security_group_entries": {
 				Type:     schema.TypeSet,
-				Required: true,
+				Optional: true,
 				Set:      securityGroupEntryHash,
 				Elem: &schema.Resource{
 					Schema: entry,
 				},
-				Description: "Network security group Entries.",
+				Description: "Network security group Entries. The entries not declared here are revoked.",
 			},
 		},
 	}
 }
 
-/*
-###
-
-this development of resource is suspended, because of the remote have a default entry that's direction is out, so that
-provider cannot manage that default entry when creating.
-
-###
-
-*/
-
 func resourceKsyunSecurityGroupEntrySetCreate(d *schema.ResourceData, meta interface{}) (err error) {
 	vpcService := VpcService{meta.(*KsyunClient)}
-	err = vpcService.CreateSecurityGroupEntrySet(d, resourceKsyunSecurityGroupEntrySet())
+	err = vpcService.ApplySecurityGroupEntrySet(d)
 	if err != nil {
-		return fmt.Errorf("error on creating security group set %q, %s", d.Id(), err)
+		return fmt.Errorf("error on creating security group set %q, %s", d.Get("security_group_id"), err)
 	}
 	d.SetId(d.Get("security_group_id").(string))
 	return resourceKsyunSecurityGroupEntrySetRead(d, meta)
@@ -92,6 +98,10 @@ func resourceKsyunSecurityGroupEntrySetRead(d *schema.ResourceData, meta interfa
 	vpcService := VpcService{meta.(*KsyunClient)}
 	err = vpcService.ReadAndSetSecurityGroup(d, resourceKsyunSecurityGroupEntrySet())
 	if err != nil {
+		if notFoundError(err) {
+			d.SetId("")
+			return nil
+		}
 		return fmt.Errorf("error on reading security group set %q, %s", d.Id(), err)
 	}
 	return err
@@ -99,29 +109,16 @@ func resourceKsyunSecurityGroupEntrySetRead(d *schema.ResourceData, meta interfa
 
 func resourceKsyunSecurityGroupEntrySetUpdate(d *schema.ResourceData, meta interface{}) (err error) {
 	vpcService := VpcService{meta.(*KsyunClient)}
-	err = vpcService.ModifySecurityGroupSet(d, resourceKsyunSecurityGroupEntrySet())
+	err = vpcService.ApplySecurityGroupEntrySet(d)
 	if err != nil {
 		return fmt.Errorf("error on updating security group set %q, %s", d.Id(), err)
 	}
-	return resourceKsyunSecurityGroupRead(d, meta)
+	return resourceKsyunSecurityGroupEntrySetRead(d, meta)
 }
 
 func resourceKsyunSecurityGroupEntrySetDelete(d *schema.ResourceData, meta interface{}) (err error) {
 	vpcService := VpcService{meta.(*KsyunClient)}
-
-	apiProcess := NewApiProcess(context.Background(), d, vpcService.client, true)
-
-	gId := d.Get("security_group_id").(string)
-	for _, entries := range d.Get("security_group_entries").(*schema.Set).List() {
-		entry := entries.(map[string]interface{})
-		entryId := entry["security_group_entry_id"].(string)
-		call, err := vpcService.RemoveSecurityGroupEntryCommonCall(gId, entryId)
-		if err != nil {
-			return fmt.Errorf("error on deleting security group entry set %q, %s", entryId, err)
-		}
-		apiProcess.PutCalls(call)
-	}
-	err = apiProcess.Run()
+	err = vpcService.RemoveSecurityGroupEntrySet(d)
 	if err != nil {
 		return fmt.Errorf("error on deleting security group set %q, %s", d.Id(), err)
 	}
@@ -1,9 +1,15 @@
 package ksyun
 
 import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/mockapi"
 )
 
 func TestAccKsyunSecurityGroupEntrySet_basic(t *testing.T) {
@@ -117,3 +123,219 @@ resource "ksyun_security_group_entry_set" "foo" {
   }
 }
 `
+
+func TestMockKsyunSecurityGroupEntrySet_basic(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	var sshEntryId string
+	outbound := `
+  security_group_entries {
+    direction  = "out"
+    protocol   = "ip"
+    cidr_block = "0.0.0.0/0"
+  }`
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testMockCheckDestroy(s, mockapi.KindSecurityGroup, mockapi.KindVpc),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockProviderConfig(s) + testMockSecurityGroupEntrySetConfig("ssh", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_security_group_entry_set.foo", "security_group_entries.#", "2"),
+					func(*terraform.State) error {
+						// the default outbound entry is revoked as it's not declared
+						entries := testMockSecurityGroupEntries(s)
+						if len(entries) != 2 {
+							return fmt.Errorf("expected 2 entries, got %v", entries)
+						}
+						for _, entry := range entries {
+							if entry["Direction"] != "in" {
+								return fmt.Errorf("unexpected entry %v", entry)
+							}
+							if entry["Protocol"] == "tcp" {
+								sshEntryId = entry["SecurityGroupEntryId"].(string)
+							}
+						}
+						return nil
+					},
+				),
+			},
+			{
+				PreConfig: func() {
+					// an unmanaged entry added out of band
+					sg := s.Items(mockapi.KindSecurityGroup)[0]
+					_, _ = s.Action("AuthorizeSecurityGroupEntry")(s, mockapi.Params{
+						"SecurityGroupId": sg["SecurityGroupId"],
+						"Direction":       "in",
+						"Protocol":        "ip",
+						"CidrBlock":       "172.16.0.0/12",
+					})
+				},
+				Config: testMockProviderConfig(s) + testMockSecurityGroupEntrySetConfig("ssh-updated", outbound),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_security_group_entry_set.foo", "security_group_entries.#", "3"),
+					func(*terraform.State) error {
+						entries := testMockSecurityGroupEntries(s)
+						if len(entries) != 3 {
+							return fmt.Errorf("expected 3 entries, got %v", entries)
+						}
+						for _, entry := range entries {
+							if entry["CidrBlock"] == "172.16.0.0/12" {
+								return fmt.Errorf("the unmanaged entry %v is not revoked", entry)
+							}
+							if entry["Protocol"] == "tcp" {
+								if entry["SecurityGroupEntryId"] != sshEntryId || entry["Description"] != "ssh-updated" {
+									return fmt.Errorf("the description of %v is expected to be modified in place", entry)
+								}
+							}
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config:            testMockProviderConfig(s) + testMockSecurityGroupEntrySetConfig("ssh-updated", outbound),
+				ResourceName:      "ksyun_security_group_entry_set.foo",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestMockKsyunSecurityGroupEntrySet_quota(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	quota := 3
+	var actions []string
+	authorize := s.Action("AuthorizeSecurityGroupEntry")
+	s.Handle("AuthorizeSecurityGroupEntry", func(s *mockapi.Server, p mockapi.Params) (map[string]interface{}, error) {
+		actions = append(actions, "authorize")
+		sg, err := s.Get(mockapi.KindSecurityGroup, p.String("SecurityGroupId"))
+		if err != nil {
+			return nil, err
+		}
+		if len(sg["SecurityGroupEntrySet"].([]interface{})) >= quota {
+			return nil, &mockapi.Error{StatusCode: http.StatusBadRequest, Code: "QuotaExceeded", Message: "too many entries"}
+		}
+		return authorize(s, p)
+	})
+	revoke := s.Action("RevokeSecurityGroupEntry")
+	s.Handle("RevokeSecurityGroupEntry", func(s *mockapi.Server, p mockapi.Params) (map[string]interface{}, error) {
+		actions = append(actions, "revoke")
+		return revoke(s, p)
+	})
+	checkActions := func(expected ...string) resource.TestCheckFunc {
+		return func(*terraform.State) error {
+			if !reflect.DeepEqual(actions, expected) {
+				return fmt.Errorf("expected the calls %v, got %v", expected, actions)
+			}
+			actions = nil
+			return nil
+		}
+	}
+	duplicated := `
+  security_group_entries {
+    description     = "duplicated"
+    direction       = "in"
+    protocol        = "tcp"
+    cidr_block      = "10.0.0.0/8"
+    port_range_from = 22
+    port_range_to   = 22
+  }`
+	outbound := `
+  security_group_entries {
+    direction  = "out"
+    protocol   = "ip"
+    cidr_block = "0.0.0.0/0"
+  }`
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testMockCheckDestroy(s, mockapi.KindSecurityGroup, mockapi.KindVpc),
+		Steps: []resource.TestStep{
+			{
+				Config:      testMockProviderConfig(s) + testMockSecurityGroupEntrySetConfig("ssh", duplicated),
+				ExpectError: regexp.MustCompile("differ only in description"),
+			},
+			{
+				// the default outbound entry is revoked after the new entries are authorized
+				Config: testMockProviderConfig(s) + testMockSecurityGroupEntrySetConfig("ssh", ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_security_group_entry_set.foo", "security_group_entries.#", "2"),
+					checkActions("authorize", "authorize", "revoke"),
+				),
+			},
+			{
+				PreConfig: func() {
+					// an unmanaged entry fills the quota
+					sg := s.Items(mockapi.KindSecurityGroup)[0]
+					_, _ = authorize(s, mockapi.Params{
+						"SecurityGroupId": sg["SecurityGroupId"],
+						"Direction":       "in",
+						"Protocol":        "ip",
+						"CidrBlock":       "172.16.0.0/12",
+					})
+				},
+				// the new entry exceeds the quota, the unmanaged one is revoked at first
+				Config: testMockProviderConfig(s) + testMockSecurityGroupEntrySetConfig("ssh", outbound),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_security_group_entry_set.foo", "security_group_entries.#", "3"),
+					checkActions("authorize", "revoke", "authorize"),
+					func(*terraform.State) error {
+						for _, entry := range testMockSecurityGroupEntries(s) {
+							if entry["CidrBlock"] == "172.16.0.0/12" {
+								return fmt.Errorf("the unmanaged entry %v is not revoked", entry)
+							}
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func testMockSecurityGroupEntries(s *mockapi.Server) []map[string]interface{} {
+	var entries []map[string]interface{}
+	for _, sg := range s.Items(mockapi.KindSecurityGroup) {
+		for _, entry := range sg["SecurityGroupEntrySet"].([]interface{}) {
+			entries = append(entries, entry.(map[string]interface{}))
+		}
+	}
+	return entries
+}
+
+func testMockSecurityGroupEntrySetConfig(description, extra string) string {
+	return fmt.Sprintf(`
+resource "ksyun_vpc" "foo" {
+  vpc_name   = "tf-mock-sg-set"
+  cidr_block = "192.168.0.0/16"
+}
+
+resource "ksyun_security_group" "foo" {
+  vpc_id              = ksyun_vpc.foo.id
+  security_group_name = "tf-mock-sg-set"
+}
+
+resource "ksyun_security_group_entry_set" "foo" {
+  security_group_id = ksyun_security_group.foo.id
+  security_group_entries {
+    description     = "%s"
+    direction       = "in"
+    protocol        = "tcp"
+    cidr_block      = "10.0.0.0/8"
+    port_range_from = 22
+    port_range_to   = 22
+  }
+  security_group_entries {
+    direction  = "in"
+    protocol   = "icmp"
+    cidr_block = "10.0.0.0/8"
+    icmp_type  = 8
+    icmp_code  = 0
+  }%s
+}
+`, description, extra)
+}
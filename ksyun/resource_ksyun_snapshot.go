@@ -69,6 +69,12 @@ func resourceKsyunSnapshot() *schema.Resource {
 				ForceNew:    true,
 				Description: "The type of the snapshot, valid values: 'LocalSnapShot', 'CommonSnapShot'. Default is 'CommonSnapShot'.",
 			},
+			"scheduled_delete_time": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "The time to delete the snapshot automatically, which is used to retain the snapshot for a period, the format is `yyyy-MM-dd HH:mm:ss`.",
+			},
 			"availability_zone": {
 				Type:        schema.TypeString,
 				Computed:    true,
@@ -0,0 +1,145 @@
+/*
+Provides a copy of EBS snapshot in another region, which is used to back up the volumes across regions.
+
+**Note** The copy is found by `snapshot_name` in the destination region as the api doesn't return its id, so the
+name must be unique in the destination region. The copy is managed in the destination region, it's deleted when the
+resource is destroyed.
+
+# Example Usage
+
+```hcl
+
+	resource "ksyun_snapshot" "default" {
+	  snapshot_name = "tf-data-backup"
+	  volume_id     = "xxxxxxxxx"
+	}
+
+	resource "ksyun_snapshot_copy" "default" {
+	  source_snapshot_id = ksyun_snapshot.default.id
+	  destination_region = "cn-shanghai-2"
+	  snapshot_name      = "tf-data-backup-shanghai"
+	  snapshot_desc      = "disaster recovery copy"
+	}
+
+	# the copy of a system disk snapshot can be imported as an image in the destination region
+	provider "ksyun" {
+	  alias  = "shanghai"
+	  region = "cn-shanghai-2"
+	}
+
+	resource "ksyun_image" "recovery" {
+	  provider    = ksyun.shanghai
+	  name        = "tf-recovery-image"
+	  snapshot_id = ksyun_snapshot_copy.default.id
+	}
+
+```
+*/
+package ksyun
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func resourceKsyunSnapshotCopy() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKsyunSnapshotCopyCreate,
+		Read:   resourceKsyunSnapshotCopyRead,
+		Delete: resourceKsyunSnapshotCopyDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"source_snapshot_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the snapshot to copy.",
+			},
+			"destination_region": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The region which the snapshot is copied to.",
+			},
+			"snapshot_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the copy, which must be unique in the destination region.",
+			},
+			"snapshot_desc": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The description of the copy.",
+			},
+			"availability_zone": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Availability zone.",
+			},
+			"volume_category": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The category of the volume, 'data' or 'system'.",
+			},
+			"size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The size of the snapshot, unit is 'GB'.",
+			},
+			"create_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The creation time.",
+			},
+			"snapshot_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the copy.",
+			},
+			"progress": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The progress of copying. Example value: 100%.",
+			},
+		},
+	}
+}
+
+func resourceKsyunSnapshotCopyCreate(d *schema.ResourceData, meta interface{}) (err error) {
+	snapshotService := SnapshotService{meta.(*KsyunClient)}
+	err = snapshotService.CopySnapshot(d)
+	if err != nil {
+		return fmt.Errorf("error on copying snapshot %q to %s, %s", d.Get("source_snapshot_id"), d.Get("destination_region"), err)
+	}
+	return resourceKsyunSnapshotCopyRead(d, meta)
+}
+
+func resourceKsyunSnapshotCopyRead(d *schema.ResourceData, meta interface{}) (err error) {
+	snapshotService := SnapshotService{meta.(*KsyunClient)}
+	err = snapshotService.ReadAndSetSnapshotCopy(d, resourceKsyunSnapshotCopy())
+	if err != nil {
+		if notFoundError(err) {
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("error on reading snapshot copy %q, %s", d.Id(), err)
+	}
+	return err
+}
+
+func resourceKsyunSnapshotCopyDelete(d *schema.ResourceData, meta interface{}) (err error) {
+	snapshotService := SnapshotService{meta.(*KsyunClient)}
+	err = snapshotService.RemoveSnapshotCopy(d)
+	if err != nil {
+		return fmt.Errorf("error on deleting snapshot copy %q, %s", d.Id(), err)
+	}
+	return err
+}
@@ -0,0 +1,87 @@
+package ksyun
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/mockapi"
+)
+
+func TestMockKsyunSnapshotCopy_basic(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testMockCheckDestroy(s, mockapi.KindSnapshot, mockapi.KindInstance),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockProviderConfig(s) + testMockSnapshotCopyConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_snapshot.foo", "scheduled_delete_time", "2099-01-01 00:00:00"),
+					resource.TestCheckResourceAttr("ksyun_snapshot_copy.foo", "snapshot_status", "available"),
+					resource.TestCheckResourceAttr("ksyun_snapshot_copy.foo", "snapshot_desc", "tf mock copy"),
+					resource.TestCheckResourceAttr("ksyun_snapshot_copy.foo", "size", "40"),
+					func(state *terraform.State) error {
+						copyId := state.RootModule().Resources["ksyun_snapshot_copy.foo"].Primary.ID
+						sourceId := state.RootModule().Resources["ksyun_snapshot.foo"].Primary.ID
+						for _, snapshot := range s.Items(mockapi.KindSnapshot) {
+							if snapshot["SnapshotId"] == copyId {
+								if snapshot["SourceSnapshotId"] != sourceId || snapshot["Region"] != "cn-shanghai-2" {
+									return fmt.Errorf("unexpected copy %v", snapshot)
+								}
+								return nil
+							}
+						}
+						return fmt.Errorf("the copy %s is absent", copyId)
+					},
+				),
+			},
+			{
+				// the name of copy must be unique in the destination region
+				Config:      testMockProviderConfig(s) + testMockSnapshotCopyConfig + testMockSnapshotCopyDuplicatedConfig,
+				ExpectError: regexp.MustCompile("the name of the copy must be unique"),
+			},
+		},
+	})
+}
+
+const testMockSnapshotCopyConfig = testMockInstanceNetworkConfig + `
+resource "ksyun_instance" "foo" {
+  image_id          = "IMG-mock"
+  instance_type     = "N3.2B"
+  subnet_id         = ksyun_subnet.foo.id
+  security_group_id = [ksyun_security_group.foo.id]
+  charge_type       = "Daily"
+  instance_name     = "tf-mock-snapshot"
+  data_disks {
+    disk_type            = "SSD3.0"
+    disk_size            = 40
+    delete_with_instance = true
+  }
+}
+
+resource "ksyun_snapshot" "foo" {
+  volume_id             = ksyun_instance.foo.data_disks.0.disk_id
+  snapshot_name         = "tf-mock-snapshot"
+  scheduled_delete_time = "2099-01-01 00:00:00"
+}
+
+resource "ksyun_snapshot_copy" "foo" {
+  source_snapshot_id = ksyun_snapshot.foo.id
+  destination_region = "cn-shanghai-2"
+  snapshot_name      = "tf-mock-snapshot-copy"
+  snapshot_desc      = "tf mock copy"
+}
+`
+
+const testMockSnapshotCopyDuplicatedConfig = `
+resource "ksyun_snapshot_copy" "bar" {
+  source_snapshot_id = ksyun_snapshot.foo.id
+  destination_region = "cn-shanghai-2"
+  snapshot_name      = ksyun_snapshot_copy.foo.snapshot_name
+}
+`
@@ -23,6 +23,15 @@ Provides a Subnet resource under VPC resource.
 	      availability_zone = "cn-shanghai-2a"
 	}
 
+	# the lowest free /24 block of the vpc is assigned
+	resource "ksyun_subnet" "auto" {
+	  subnet_name       = "tf-acc-subnet2"
+	  cidr_mask_length  = 24
+	  subnet_type       = "Normal"
+	  vpc_id            = ksyun_vpc.example.id
+	  availability_zone = "cn-shanghai-2a"
+	}
+
 ```
 
 # Import
@@ -68,10 +77,22 @@ func resourceKsyunSubnet() *schema.Resource {
 
 			"cidr_block": {
 				Type:         schema.TypeString,
-				Required:     true,
+				Optional:     true,
+				Computed:     true,
 				ForceNew:     true,
 				ValidateFunc: validateCIDRNetworkAddress,
-				Description:  "The CIDR block assigned to the subnet.",
+				ExactlyOneOf: []string{"cidr_block", "cidr_mask_length"},
+				Description:  "The CIDR block assigned to the subnet. Conflict with `cidr_mask_length`.",
+			},
+
+			"cidr_mask_length": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(8, 29),
+				ExactlyOneOf: []string{"cidr_block", "cidr_mask_length"},
+				Description:  "The mask length of the CIDR block, the lowest free block of the length in the vpc is assigned to the subnet. Conflict with `cidr_block`.",
 			},
 
 			"subnet_type": {
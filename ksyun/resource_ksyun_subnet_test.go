@@ -6,6 +6,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/mockapi"
 )
 
 func TestAccKsyunSubnet_basic(t *testing.T) {
@@ -186,3 +187,87 @@ resource "ksyun_subnet" "foo" {
   availability_zone = "${data.ksyun_availability_zones.default.availability_zones.0.availability_zone_name}"
 }
 `
+
+func TestMockKsyunSubnet_cidrMaskLength(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	config := testMockProviderConfig(s) + `
+resource "ksyun_vpc" "foo" {
+  vpc_name   = "tf-mock-cidr"
+  cidr_block = "10.0.0.0/16"
+}
+
+resource "ksyun_subnet" "foo" {
+  subnet_name       = "tf-mock-cidr-foo"
+  cidr_block        = "10.0.0.0/24"
+  subnet_type       = "Normal"
+  availability_zone = "cn-beijing-6a"
+  vpc_id            = ksyun_vpc.foo.id
+}
+
+resource "ksyun_subnet" "bar" {
+  subnet_name       = "tf-mock-cidr-bar"
+  cidr_mask_length  = 24
+  subnet_type       = "Normal"
+  availability_zone = "cn-beijing-6a"
+  vpc_id            = ksyun_vpc.foo.id
+  depends_on        = [ksyun_subnet.foo]
+}
+
+resource "ksyun_subnet" "baz" {
+  subnet_name       = "tf-mock-cidr-baz"
+  cidr_mask_length  = 20
+  subnet_type       = "Normal"
+  availability_zone = "cn-beijing-6b"
+  vpc_id            = ksyun_vpc.foo.id
+  depends_on        = [ksyun_subnet.foo]
+}
+`
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testMockCheckDestroy(s, mockapi.KindSubnet, mockapi.KindVpc),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_subnet.foo", "cidr_mask_length", "24"),
+					resource.TestCheckResourceAttr("ksyun_subnet.bar", "cidr_block", "10.0.1.0/24"),
+					resource.TestCheckResourceAttr("ksyun_subnet.bar", "gateway_ip", "10.0.1.1"),
+					resource.TestCheckResourceAttr("ksyun_subnet.baz", "cidr_block", "10.0.16.0/20"),
+				),
+			},
+			{
+				Config: config + `
+data "ksyun_vpc_cidr_plan" "foo" {
+  vpc_id               = ksyun_vpc.foo.id
+  reserved_cidr_blocks = ["10.0.2.0/24"]
+  requests {
+    availability_zone = "cn-beijing-6a"
+    cidr_mask_length  = 24
+    count             = 2
+  }
+  requests {
+    availability_zone = "cn-beijing-6b"
+    cidr_mask_length  = 26
+  }
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.ksyun_vpc_cidr_plan.foo", "vpc_cidr_block", "10.0.0.0/16"),
+					resource.TestCheckResourceAttr("data.ksyun_vpc_cidr_plan.foo", "used_cidr_blocks.#", "4"),
+					resource.TestCheckResourceAttr("data.ksyun_vpc_cidr_plan.foo", "plans.0.cidr_blocks.0", "10.0.3.0/24"),
+					resource.TestCheckResourceAttr("data.ksyun_vpc_cidr_plan.foo", "plans.0.cidr_blocks.1", "10.0.4.0/24"),
+					resource.TestCheckResourceAttr("data.ksyun_vpc_cidr_plan.foo", "plans.1.availability_zone", "cn-beijing-6b"),
+					resource.TestCheckResourceAttr("data.ksyun_vpc_cidr_plan.foo", "plans.1.cidr_blocks.0", "10.0.5.0/26"),
+				),
+			},
+			{
+				Config:            config,
+				ResourceName:      "ksyun_subnet.bar",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -153,7 +153,8 @@ func resourceKsyunVolume() *schema.Resource {
 				Description:      "When the cloud disk snapshot opens, the snapshot id is entered.",
 			},
 
-			"tags": tagsSchema(),
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
 		},
 	}
 }
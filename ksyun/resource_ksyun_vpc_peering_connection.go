@@ -0,0 +1,259 @@
+/*
+Provides a VPC peering connection resource, which connects two VPCs in the same account or across accounts and regions.
+
+**Note** The connection is pending acceptance after it's created. The connection between the VPCs of the same account can
+be accepted by `auto_accept`, otherwise it's accepted by the `ksyun_vpc_peering_connection_accepter` of the peer account.
+The `band_width` is required by the cross-region connection.
+
+# Example Usage
+
+## same region
+
+```hcl
+
+	resource "ksyun_vpc_peering_connection" "default" {
+	  peering_name = "tf-peering"
+	  vpc_id       = ksyun_vpc.foo.id
+	  peer_vpc_id  = ksyun_vpc.bar.id
+	  auto_accept  = true
+	}
+
+	resource "ksyun_route" "to_bar" {
+	  vpc_id                    = ksyun_vpc.foo.id
+	  destination_cidr_block    = ksyun_vpc.bar.cidr_block
+	  route_type                = "Peering"
+	  vpc_peering_connection_id = ksyun_vpc_peering_connection.default.id
+	}
+
+```
+
+## cross account and region
+
+```hcl
+
+	provider "ksyun" {
+	  alias  = "peer"
+	  region = "cn-shanghai-2"
+	  # the credentials of the peer account
+	}
+
+	resource "ksyun_vpc_peering_connection" "default" {
+	  peering_name    = "tf-peering"
+	  vpc_id          = ksyun_vpc.foo.id
+	  peer_vpc_id     = "the vpc id of the peer account"
+	  peer_region     = "cn-shanghai-2"
+	  peer_account_id = "the id of the peer account"
+	  band_width      = 10
+	  charge_type     = "Monthly"
+	  purchase_time   = 1
+	}
+
+	resource "ksyun_vpc_peering_connection_accepter" "default" {
+	  provider                  = ksyun.peer
+	  vpc_peering_connection_id = ksyun_vpc_peering_connection.default.id
+	}
+
+```
+
+# Import
+
+VPC peering connection can be imported using the `id`, e.g.
+
+```
+$ terraform import ksyun_vpc_peering_connection.default xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+```
+*/
+
+package ksyun
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func resourceKsyunVpcPeeringConnection() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKsyunVpcPeeringConnectionCreate,
+		Read:   resourceKsyunVpcPeeringConnectionRead,
+		Update: resourceKsyunVpcPeeringConnectionUpdate,
+		Delete: resourceKsyunVpcPeeringConnectionDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"vpc_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the requester VPC.",
+			},
+			"peer_vpc_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the peer VPC.",
+			},
+			"peering_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the peering connection.",
+			},
+			"peer_region": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The region of the peer VPC. Default is the region of the requester VPC.",
+			},
+			"peer_account_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The account ID of the peer VPC. Default is the account of the requester VPC.",
+			},
+			"band_width": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The bandwidth of the cross-region peering connection, unit is 'Mbps'.",
+			},
+			"charge_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					"Monthly",
+					"Daily",
+					"Peak",
+				}, false),
+				DiffSuppressFunc: chargeSchemaDiffSuppressFunc,
+				Description:      "The charge type of the cross-region peering connection, valid values: 'Monthly', 'Daily', 'Peak'.",
+			},
+			"purchase_time": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.IntBetween(0, 36),
+				DiffSuppressFunc: purchaseTimeDiffSuppressFunc,
+				Description:      "The purchase time of the peering connection, value range [1, 36]. If charge_type is Monthly this Field is Required.",
+			},
+			"project_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "ID of the project.",
+			},
+			"auto_accept": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to accept the peering connection when it's created. It only works on the VPCs of the same account.",
+			},
+
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The state of the peering connection.",
+			},
+			"vpc_peering_connection_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the peering connection.",
+			},
+			"create_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The time of creation.",
+			},
+			"requester_vpc_info": vpcPeeringConnectionVpcInfoSchema("The information of the requester VPC."),
+			"accepter_vpc_info":  vpcPeeringConnectionVpcInfoSchema("The information of the accepter VPC."),
+		},
+	}
+}
+
+func vpcPeeringConnectionVpcInfoSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: description,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"account_id": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The account ID of the VPC.",
+				},
+				"region": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The region of the VPC.",
+				},
+				"vpc_id": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The ID of the VPC.",
+				},
+				"vpc_name": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The name of the VPC.",
+				},
+				"cidr_block": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "The CIDR block of the VPC.",
+				},
+			},
+		},
+	}
+}
+
+func resourceKsyunVpcPeeringConnectionCreate(d *schema.ResourceData, meta interface{}) (err error) {
+	vpcService := VpcService{meta.(*KsyunClient)}
+	err = vpcService.CreateVpcPeeringConnection(d, resourceKsyunVpcPeeringConnection())
+	if err != nil {
+		return fmt.Errorf("error on creating vpc peering connection %q, %s", d.Id(), err)
+	}
+	return resourceKsyunVpcPeeringConnectionRead(d, meta)
+}
+
+func resourceKsyunVpcPeeringConnectionRead(d *schema.ResourceData, meta interface{}) (err error) {
+	vpcService := VpcService{meta.(*KsyunClient)}
+	err = vpcService.ReadAndSetVpcPeeringConnection(d, resourceKsyunVpcPeeringConnection())
+	if err != nil {
+		if notFoundError(err) {
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("error on reading vpc peering connection %q, %s", d.Id(), err)
+	}
+	return err
+}
+
+func resourceKsyunVpcPeeringConnectionUpdate(d *schema.ResourceData, meta interface{}) (err error) {
+	vpcService := VpcService{meta.(*KsyunClient)}
+	err = vpcService.ModifyVpcPeeringConnection(d, resourceKsyunVpcPeeringConnection())
+	if err != nil {
+		return fmt.Errorf("error on updating vpc peering connection %q, %s", d.Id(), err)
+	}
+	return resourceKsyunVpcPeeringConnectionRead(d, meta)
+}
+
+func resourceKsyunVpcPeeringConnectionDelete(d *schema.ResourceData, meta interface{}) (err error) {
+	vpcService := VpcService{meta.(*KsyunClient)}
+	err = vpcService.RemoveVpcPeeringConnection(d)
+	if err != nil {
+		return fmt.Errorf("error on deleting vpc peering connection %q, %s", d.Id(), err)
+	}
+	return err
+}
@@ -0,0 +1,145 @@
+/*
+Provides a resource to accept the VPC peering connection on the peer side, which is the account and region of the peer VPC.
+
+**Note** The provider of the accepter must be configured with the account and region of the peer VPC. The peering connection
+is deleted when the accepter is destroyed, either side of the connection can delete it.
+
+# Example Usage
+
+```hcl
+
+	provider "ksyun" {
+	  alias  = "peer"
+	  region = "cn-shanghai-2"
+	  # the credentials of the peer account
+	}
+
+	resource "ksyun_vpc_peering_connection" "default" {
+	  peering_name    = "tf-peering"
+	  vpc_id          = ksyun_vpc.foo.id
+	  peer_vpc_id     = "the vpc id of the peer account"
+	  peer_region     = "cn-shanghai-2"
+	  peer_account_id = "the id of the peer account"
+	  band_width      = 10
+	}
+
+	resource "ksyun_vpc_peering_connection_accepter" "default" {
+	  provider                  = ksyun.peer
+	  vpc_peering_connection_id = ksyun_vpc_peering_connection.default.id
+	}
+
+```
+
+# Import
+
+VPC peering connection accepter can be imported using the `id` of the peering connection, e.g.
+
+```
+$ terraform import ksyun_vpc_peering_connection_accepter.default xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+```
+*/
+
+package ksyun
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func resourceKsyunVpcPeeringConnectionAccepter() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceKsyunVpcPeeringConnectionAccepterCreate,
+		Read:   resourceKsyunVpcPeeringConnectionAccepterRead,
+		Delete: resourceKsyunVpcPeeringConnectionAccepterDelete,
+		Importer: &schema.ResourceImporter{
+			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				_ = d.Set("vpc_peering_connection_id", d.Id())
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"vpc_peering_connection_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The ID of the peering connection to accept.",
+			},
+
+			"vpc_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the requester VPC.",
+			},
+			"peer_vpc_id": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The ID of the peer VPC.",
+			},
+			"peering_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The name of the peering connection.",
+			},
+			"band_width": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The bandwidth of the cross-region peering connection, unit is 'Mbps'.",
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The state of the peering connection.",
+			},
+			"vpc_peering_connection_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of the peering connection.",
+			},
+			"create_time": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The time of creation.",
+			},
+			"requester_vpc_info": vpcPeeringConnectionVpcInfoSchema("The information of the requester VPC."),
+			"accepter_vpc_info":  vpcPeeringConnectionVpcInfoSchema("The information of the accepter VPC."),
+		},
+	}
+}
+
+func resourceKsyunVpcPeeringConnectionAccepterCreate(d *schema.ResourceData, meta interface{}) (err error) {
+	vpcService := VpcService{meta.(*KsyunClient)}
+	peeringId := d.Get("vpc_peering_connection_id").(string)
+	err = vpcService.AcceptVpcPeeringConnection(d, peeringId, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return fmt.Errorf("error on accepting vpc peering connection %q, %s", peeringId, err)
+	}
+	d.SetId(peeringId)
+	return resourceKsyunVpcPeeringConnectionAccepterRead(d, meta)
+}
+
+func resourceKsyunVpcPeeringConnectionAccepterRead(d *schema.ResourceData, meta interface{}) (err error) {
+	vpcService := VpcService{meta.(*KsyunClient)}
+	err = vpcService.ReadAndSetVpcPeeringConnection(d, resourceKsyunVpcPeeringConnectionAccepter())
+	if err != nil {
+		if notFoundError(err) {
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("error on reading vpc peering connection %q, %s", d.Id(), err)
+	}
+	return err
+}
+
+func resourceKsyunVpcPeeringConnectionAccepterDelete(d *schema.ResourceData, meta interface{}) (err error) {
+	vpcService := VpcService{meta.(*KsyunClient)}
+	err = vpcService.RemoveVpcPeeringConnection(d)
+	if err != nil {
+		return fmt.Errorf("error on deleting vpc peering connection %q, %s", d.Id(), err)
+	}
+	return err
+}
@@ -0,0 +1,76 @@
+package ksyun
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/mockapi"
+)
+
+func TestMockKsyunVpcPeeringConnection_accepter(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testMockCheckDestroy(s, mockapi.KindPeering, mockapi.KindVpc),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockProviderConfig(s) + testMockVpcPeeringConnectionAccepterConfig(s, 10),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_vpc_peering_connection_accepter.foo", "state", "active"),
+					resource.TestCheckResourceAttr("ksyun_vpc_peering_connection_accepter.foo", "vpc_peering_connection_type", "CrossRegion"),
+					resource.TestCheckResourceAttr("ksyun_vpc_peering_connection_accepter.foo", "accepter_vpc_info.0.region", "cn-shanghai-2"),
+					resource.TestCheckResourceAttrPair("ksyun_vpc_peering_connection_accepter.foo", "id", "ksyun_vpc_peering_connection.foo", "id"),
+				),
+			},
+			{
+				Config: testMockProviderConfig(s) + testMockVpcPeeringConnectionAccepterConfig(s, 20),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_vpc_peering_connection.foo", "band_width", "20"),
+					resource.TestCheckResourceAttr("ksyun_vpc_peering_connection.foo", "state", "active"),
+				),
+			},
+		},
+	})
+}
+
+func testMockVpcPeeringConnectionAccepterConfig(s *mockapi.Server, bandWidth int) string {
+	return fmt.Sprintf(`
+provider "ksyun" {
+  alias          = "peer"
+  access_key     = "mock-access-key"
+  secret_key     = "mock-secret-key"
+  region         = "cn-shanghai-2"
+  domain         = "%s"
+  ignore_service = true
+  max_retries    = 0
+}
+
+resource "ksyun_vpc" "foo" {
+  vpc_name   = "tf-mock-foo"
+  cidr_block = "192.168.0.0/16"
+}
+
+resource "ksyun_vpc" "bar" {
+  provider   = ksyun.peer
+  vpc_name   = "tf-mock-bar"
+  cidr_block = "10.1.0.0/16"
+}
+
+resource "ksyun_vpc_peering_connection" "foo" {
+  peering_name    = "tf-mock-peering"
+  vpc_id          = ksyun_vpc.foo.id
+  peer_vpc_id     = ksyun_vpc.bar.id
+  peer_region     = "cn-shanghai-2"
+  peer_account_id = "%s"
+  band_width      = %d
+}
+
+resource "ksyun_vpc_peering_connection_accepter" "foo" {
+  provider                  = ksyun.peer
+  vpc_peering_connection_id = ksyun_vpc_peering_connection.foo.id
+}
+`, s.Domain(), mockapi.DefaultAccountId, bandWidth)
+}
@@ -0,0 +1,143 @@
+package ksyun
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/mockapi"
+)
+
+func TestAccKsyunVpcPeeringConnection_basic(t *testing.T) {
+	resource.ParallelTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+
+		IDRefreshName: "ksyun_vpc_peering_connection.foo",
+		Providers:     testAccProviders,
+		CheckDestroy:  testAccCheckVpcPeeringConnectionDestroy,
+
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVpcPeeringConnectionConfig("tf-acc-peering"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_vpc_peering_connection.foo", "state", "active"),
+					resource.TestCheckResourceAttrPair("ksyun_vpc_peering_connection.foo", "peer_vpc_id", "ksyun_vpc.bar", "id"),
+				),
+			},
+			{
+				Config: testAccVpcPeeringConnectionConfig("tf-acc-peering-update"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_vpc_peering_connection.foo", "peering_name", "tf-acc-peering-update"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckVpcPeeringConnectionDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(*KsyunClient)
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "ksyun_vpc_peering_connection" {
+			continue
+		}
+		req := map[string]interface{}{
+			"VpcPeeringConnectionId.1": rs.Primary.ID,
+		}
+		resp, err := client.vpcconn.DescribeVpcPeeringConnections(&req)
+		if err != nil {
+			return err
+		}
+		if l, ok := (*resp)["VpcPeeringConnectionSet"].([]interface{}); ok && len(l) > 0 {
+			return fmt.Errorf("vpc peering connection %s still exists", rs.Primary.ID)
+		}
+	}
+	return nil
+}
+
+func testAccVpcPeeringConnectionConfig(name string) string {
+	return fmt.Sprintf(`
+resource "ksyun_vpc" "foo" {
+  vpc_name   = "tf-acc-peering-foo"
+  cidr_block = "192.168.0.0/16"
+}
+
+resource "ksyun_vpc" "bar" {
+  vpc_name   = "tf-acc-peering-bar"
+  cidr_block = "10.1.0.0/16"
+}
+
+resource "ksyun_vpc_peering_connection" "foo" {
+  peering_name = "%s"
+  vpc_id       = ksyun_vpc.foo.id
+  peer_vpc_id  = ksyun_vpc.bar.id
+  auto_accept  = true
+}
+`, name)
+}
+
+func TestMockKsyunVpcPeeringConnection_basic(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testMockCheckDestroy(s, mockapi.KindPeering, mockapi.KindRoute, mockapi.KindVpc),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockProviderConfig(s) + testMockVpcPeeringConnectionConfig("tf-mock-peering"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_vpc_peering_connection.foo", "state", "active"),
+					resource.TestCheckResourceAttr("ksyun_vpc_peering_connection.foo", "peer_region", mockapi.DefaultRegion),
+					resource.TestCheckResourceAttr("ksyun_vpc_peering_connection.foo", "accepter_vpc_info.0.cidr_block", "10.1.0.0/16"),
+					resource.TestCheckResourceAttrPair("ksyun_vpc_peering_connection.foo", "peer_vpc_id", "ksyun_vpc.bar", "id"),
+					resource.TestCheckResourceAttrPair("ksyun_route.foo", "vpc_peering_connection_id", "ksyun_vpc_peering_connection.foo", "id"),
+				),
+			},
+			{
+				Config: testMockProviderConfig(s) + testMockVpcPeeringConnectionConfig("tf-mock-peering-renamed") + `
+data "ksyun_vpc_peering_connections" "foo" {
+  vpc_ids    = [ksyun_vpc_peering_connection.foo.vpc_id]
+  name_regex = "renamed"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_vpc_peering_connection.foo", "peering_name", "tf-mock-peering-renamed"),
+					resource.TestCheckResourceAttr("data.ksyun_vpc_peering_connections.foo", "total_count", "1"),
+					resource.TestCheckResourceAttrPair("data.ksyun_vpc_peering_connections.foo", "vpc_peering_connections.0.peer_vpc_id", "ksyun_vpc.bar", "id"),
+					resource.TestCheckResourceAttr("data.ksyun_vpc_peering_connections.foo", "vpc_peering_connections.0.requester_vpc_info.0.cidr_block", "192.168.0.0/16"),
+				),
+			},
+		},
+	})
+}
+
+func testMockVpcPeeringConnectionConfig(name string) string {
+	return fmt.Sprintf(`
+resource "ksyun_vpc" "foo" {
+  vpc_name   = "tf-mock-foo"
+  cidr_block = "192.168.0.0/16"
+}
+
+resource "ksyun_vpc" "bar" {
+  vpc_name   = "tf-mock-bar"
+  cidr_block = "10.1.0.0/16"
+}
+
+resource "ksyun_vpc_peering_connection" "foo" {
+  peering_name = "%s"
+  vpc_id       = ksyun_vpc.foo.id
+  peer_vpc_id  = ksyun_vpc.bar.id
+  auto_accept  = true
+}
+
+resource "ksyun_route" "foo" {
+  vpc_id                    = ksyun_vpc.foo.id
+  destination_cidr_block    = ksyun_vpc.bar.cidr_block
+  route_type                = "Peering"
+  vpc_peering_connection_id = ksyun_vpc_peering_connection.foo.id
+}
+`, name)
+}
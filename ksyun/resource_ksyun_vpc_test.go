@@ -6,6 +6,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/mockapi"
 )
 
 func TestAccKsyunVPC_basic(t *testing.T) {
@@ -164,3 +165,36 @@ resource "ksyun_vpc" "foo" {
     cidr_block      = "192.168.0.0/16"
 }
 `
+
+func TestMockKsyunNetwork_basic(t *testing.T) {
+	s := mockapi.NewServer()
+	defer s.Close()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testMockCheckDestroy(s, mockapi.KindVpc, mockapi.KindSubnet, mockapi.KindSecurityGroup, mockapi.KindAddress, mockapi.KindLoadBalancer),
+		Steps: []resource.TestStep{
+			{
+				Config: testMockProviderConfig(s) + testMockNetworkConfig("tf-mock"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_vpc.foo", "vpc_name", "tf-mock"),
+					resource.TestCheckResourceAttr("ksyun_vpc.foo", "cidr_block", "192.168.0.0/16"),
+					resource.TestCheckResourceAttrPair("ksyun_subnet.foo", "vpc_id", "ksyun_vpc.foo", "id"),
+					resource.TestCheckResourceAttr("ksyun_subnet.foo", "gateway_ip", "192.168.1.1"),
+					resource.TestCheckResourceAttr("ksyun_security_group.foo", "security_group_name", "tf-mock"),
+					resource.TestCheckResourceAttr("ksyun_eip.foo", "band_width", "1"),
+					resource.TestCheckResourceAttr("ksyun_eip.foo", "tags.env", "test"),
+					resource.TestCheckResourceAttr("ksyun_lb.foo", "load_balancer_name", "tf-mock"),
+				),
+			},
+			{
+				Config: testMockProviderConfig(s) + testMockNetworkConfig("tf-mock-update"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("ksyun_vpc.foo", "vpc_name", "tf-mock-update"),
+					resource.TestCheckResourceAttr("ksyun_subnet.foo", "subnet_name", "tf-mock-update"),
+					resource.TestCheckResourceAttr("ksyun_lb.foo", "load_balancer_name", "tf-mock-update"),
+				),
+			},
+		},
+	})
+}
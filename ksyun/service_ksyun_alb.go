@@ -79,7 +79,7 @@ func (alb *AlbService) readAlb(d *schema.ResourceData, albId string, allProject
 		}
 	}
 
-	if _, ok := d.GetOk("tags"); ok {
+	if hasTags(d, alb.client) {
 		req["IsContainTag"] = true
 	}
 
@@ -312,7 +312,7 @@ func (alb *AlbService) ModifyAlb(d *schema.ResourceData, r *schema.Resource) (er
 		calls = append(calls, modifyAlbCall)
 	}
 
-	if d.HasChange("tags") {
+	if hasTagsChange(d) {
 		tagService := TagService{alb.client}
 		tagsCall, err := tagService.ReplaceResourcesTagsWithResourceCall(d, r, "loadbalancer", true, false)
 		if err != nil {
@@ -599,7 +599,7 @@ func (alb *AlbService) CreateAlb(d *schema.ResourceData, r *schema.Resource) (er
 		}
 	}
 
-	if d.HasChange("tags") {
+	if hasTagsChange(d) {
 		tagsService := TagService{client: alb.client}
 		tagsCall, err := tagsService.ReplaceResourcesTagsWithResourceCall(d, r, "loadbalancer", false, false)
 		if err != nil {
@@ -214,6 +214,24 @@ func (s *AutoSnapshotSrv) filterVolumesAutoSnapshotPolicyAssociations(
 	return data, err
 }
 
+// readAutoSnapshotPolicyVolumeIds returns the ids of the volumes which the policy is applied to
+func (s *AutoSnapshotSrv) readAutoSnapshotPolicyVolumeIds(policyId string) (ids []string, err error) {
+	volumes, err := s.readAutoSnapshotPolicyVolumeAssociationAll()
+	if err != nil || len(volumes) < 1 {
+		return ids, err
+	}
+	associations, err := s.filterVolumesAutoSnapshotPolicyAssociations(volumes, policyId)
+	if err != nil {
+		return ids, err
+	}
+	for _, v := range associations {
+		if id, ok := v.(map[string]interface{})["VolumeId"].(string); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, err
+}
+
 func (s *AutoSnapshotSrv) GetConn() *kec.Kec {
 	return s.client.kecconn
 }
@@ -55,7 +55,7 @@ func (s *BwsService) ReadBandWidthShare(d *schema.ResourceData, bwsId string) (d
 		return data, err
 	}
 
-	if _, ok := d.GetOk("tags"); ok {
+	if hasTags(d, s.client) {
 		req["IsContainTag"] = true
 	}
 
@@ -261,7 +261,7 @@ func (s *BwsService) ModifyBandWidthShare(d *schema.ResourceData, r *schema.Reso
 		return err
 	}
 	calls = append(calls, call)
-	if d.HasChange("tags") {
+	if hasTagsChange(d) {
 		tagsService := TagService{client: s.client}
 		tagsCall, err := tagsService.ReplaceResourcesTagsWithResourceCall(d, r, "bws", true, false)
 		if err != nil {
@@ -2,10 +2,13 @@ package ksyun
 
 import (
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/terraform-providers/terraform-provider-ksyun/logger"
-	"time"
 )
 
 type CenService struct {
@@ -212,3 +215,646 @@ func (s *CenService) RemoveCen(d *schema.ResourceData) (err error) {
 	}
 	return ksyunApiCallNew([]ApiCall{call}, d, s.client, true)
 }
+
+// cenRequest sends the actions absent in the cen sdk, such as the attachments and the bandwidths of cen
+func cenRequest(client *KsyunClient, action string, param *map[string]interface{}) (resp *map[string]interface{}, err error) {
+	op := &request.Operation{
+		Name:       action,
+		HTTPMethod: "GET",
+		HTTPPath:   "/",
+	}
+	resp = &map[string]interface{}{}
+	err = client.cenconn.NewRequest(op, param, resp).Send()
+	return resp, err
+}
+
+func (s *CenService) readCenSet(action string, setName string, condition map[string]interface{}) (data []interface{}, err error) {
+	return pageQueryWithNextToken(condition, "MaxResults", "NextToken", 100, func(condition map[string]interface{}) ([]interface{}, string, error) {
+		logger.Debug(logger.ReqFormat, action, condition)
+		resp, err := cenRequest(s.client, action, &condition)
+		if err != nil {
+			return nil, "", err
+		}
+		results, err := getSdkValue(setName, *resp)
+		if err != nil {
+			return nil, "", err
+		}
+		nextToken, _ := (*resp)["NextToken"].(string)
+		return results.([]interface{}), nextToken, nil
+	})
+}
+
+// removeCenCallError retries the deletion of the cen resources until they are not found by read
+func removeCenCallError(name string, read func() error) callErrorFunc {
+	return func(d *schema.ResourceData, client *KsyunClient, call ApiCall, baseErr error) error {
+		return resource.Retry(15*time.Minute, func() *resource.RetryError {
+			callErr := read()
+			if callErr != nil {
+				if notFoundError(callErr) {
+					return nil
+				} else {
+					return resource.NonRetryableError(fmt.Errorf("error on  reading %s when delete %q, %s", name, d.Id(), callErr))
+				}
+			}
+			_, callErr = call.executeCall(d, client, call)
+			if callErr == nil {
+				return nil
+			}
+			return resource.RetryableError(callErr)
+		})
+	}
+}
+
+// cen grant
+
+func (s *CenService) ReadCenGrants(condition map[string]interface{}) (data []interface{}, err error) {
+	return s.readCenSet("DescribeCenGrants", "CenGrantSet", condition)
+}
+
+func (s *CenService) ReadCenGrant(d *schema.ResourceData, grantId string) (data map[string]interface{}, err error) {
+	var results []interface{}
+	if grantId == "" {
+		grantId = d.Id()
+	}
+	req := map[string]interface{}{
+		"CenGrantId.1": grantId,
+	}
+	results, err = s.ReadCenGrants(req)
+	if err != nil {
+		return data, err
+	}
+	for _, v := range results {
+		data = v.(map[string]interface{})
+	}
+	if len(data) == 0 {
+		return data, fmt.Errorf("CenGrant %s not exist ", grantId)
+	}
+	return data, err
+}
+
+func (s *CenService) ReadAndSetCenGrant(d *schema.ResourceData, r *schema.Resource) (err error) {
+	data, err := s.ReadCenGrant(d, "")
+	if err != nil {
+		return err
+	}
+	SdkResponseAutoResourceData(d, r, data, nil)
+	return err
+}
+
+func (s *CenService) CreateCenGrantCall(d *schema.ResourceData, r *schema.Resource) (callback ApiCall, err error) {
+	req, err := SdkRequestAutoMapping(d, r, false, nil, nil, SdkReqParameter{
+		onlyTransform: false,
+	})
+	if err != nil {
+		return callback, err
+	}
+	callback = ApiCall{
+		param:  &req,
+		action: "CreateCenGrant",
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			return cenRequest(client, call.action, call.param)
+		},
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			id, err := getSdkValue("CenGrant.CenGrantId", *resp)
+			if err != nil {
+				return err
+			}
+			d.SetId(id.(string))
+			return err
+		},
+	}
+	return callback, err
+}
+
+func (s *CenService) CreateCenGrant(d *schema.ResourceData, r *schema.Resource) (err error) {
+	call, err := s.CreateCenGrantCall(d, r)
+	if err != nil {
+		return err
+	}
+	return ksyunApiCallNew([]ApiCall{call}, d, s.client, true)
+}
+
+func (s *CenService) RemoveCenGrantCall(d *schema.ResourceData) (callback ApiCall, err error) {
+	removeReq := map[string]interface{}{
+		"CenGrantId": d.Id(),
+	}
+	callback = ApiCall{
+		param:  &removeReq,
+		action: "DeleteCenGrant",
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			return cenRequest(client, call.action, call.param)
+		},
+		callError: removeCenCallError("cen grant", func() error {
+			_, err := s.ReadCenGrant(d, "")
+			return err
+		}),
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			return err
+		},
+	}
+	return callback, err
+}
+
+func (s *CenService) RemoveCenGrant(d *schema.ResourceData) (err error) {
+	call, err := s.RemoveCenGrantCall(d)
+	if err != nil {
+		return err
+	}
+	return ksyunApiCallNew([]ApiCall{call}, d, s.client, true)
+}
+
+// cen instance attachment, the id is formatted as cen_id:network_instance_id
+
+func parseCenInstanceAttachmentId(id string) (cenId string, instanceId string, err error) {
+	items := strings.Split(id, ":")
+	if len(items) != 2 || items[0] == "" || items[1] == "" {
+		return cenId, instanceId, fmt.Errorf("invalid cen instance attachment id %q, the format must be cen_id:network_instance_id", id)
+	}
+	return items[0], items[1], err
+}
+
+func (s *CenService) ReadCenInstanceAttachments(condition map[string]interface{}) (data []interface{}, err error) {
+	return s.readCenSet("DescribeNetworkInstances", "NetworkInstanceSet", condition)
+}
+
+func (s *CenService) ReadCenInstanceAttachment(d *schema.ResourceData, attachmentId string) (data map[string]interface{}, err error) {
+	var results []interface{}
+	if attachmentId == "" {
+		attachmentId = d.Id()
+	}
+	cenId, instanceId, err := parseCenInstanceAttachmentId(attachmentId)
+	if err != nil {
+		return data, err
+	}
+	req := map[string]interface{}{
+		"NetworkInstanceId.1": instanceId,
+		"Filter.1.Name":       "cen-id",
+		"Filter.1.Value.1":    cenId,
+	}
+	results, err = s.ReadCenInstanceAttachments(req)
+	if err != nil {
+		return data, err
+	}
+	for _, v := range results {
+		if item := v.(map[string]interface{}); item["CenId"] == cenId {
+			data = item
+		}
+	}
+	if len(data) == 0 {
+		return data, fmt.Errorf("CenInstanceAttachment %s not exist ", attachmentId)
+	}
+	return data, err
+}
+
+func (s *CenService) ReadAndSetCenInstanceAttachment(d *schema.ResourceData, r *schema.Resource) (err error) {
+	data, err := s.ReadCenInstanceAttachment(d, "")
+	if err != nil {
+		return err
+	}
+	SdkResponseAutoResourceData(d, r, data, nil)
+	return err
+}
+
+func (s *CenService) CreateCenInstanceAttachmentCall(d *schema.ResourceData, r *schema.Resource) (callback ApiCall, err error) {
+	req, err := SdkRequestAutoMapping(d, r, false, nil, nil, SdkReqParameter{
+		onlyTransform: false,
+	})
+	if err != nil {
+		return callback, err
+	}
+	if _, ok := req["InstanceRegion"]; !ok {
+		req["InstanceRegion"] = s.client.region
+	}
+	callback = ApiCall{
+		param:  &req,
+		action: "AttachNetworkInstance",
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			return cenRequest(client, call.action, call.param)
+		},
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			d.SetId(fmt.Sprintf("%s:%s", (*call.param)["CenId"], (*call.param)["NetworkInstanceId"]))
+			return err
+		},
+	}
+	return callback, err
+}
+
+func (s *CenService) CreateCenInstanceAttachment(d *schema.ResourceData, r *schema.Resource) (err error) {
+	call, err := s.CreateCenInstanceAttachmentCall(d, r)
+	if err != nil {
+		return err
+	}
+	return ksyunApiCallNew([]ApiCall{call}, d, s.client, true)
+}
+
+func (s *CenService) RemoveCenInstanceAttachmentCall(d *schema.ResourceData) (callback ApiCall, err error) {
+	cenId, instanceId, err := parseCenInstanceAttachmentId(d.Id())
+	if err != nil {
+		return callback, err
+	}
+	removeReq := map[string]interface{}{
+		"CenId":             cenId,
+		"NetworkInstanceId": instanceId,
+	}
+	callback = ApiCall{
+		param:  &removeReq,
+		action: "DetachNetworkInstance",
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			return cenRequest(client, call.action, call.param)
+		},
+		callError: removeCenCallError("cen instance attachment", func() error {
+			_, err := s.ReadCenInstanceAttachment(d, "")
+			return err
+		}),
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			return err
+		},
+	}
+	return callback, err
+}
+
+func (s *CenService) RemoveCenInstanceAttachment(d *schema.ResourceData) (err error) {
+	call, err := s.RemoveCenInstanceAttachmentCall(d)
+	if err != nil {
+		return err
+	}
+	return ksyunApiCallNew([]ApiCall{call}, d, s.client, true)
+}
+
+// cen bandwidth package
+
+func (s *CenService) ReadCenBandwidthPackages(condition map[string]interface{}) (data []interface{}, err error) {
+	return s.readCenSet("DescribeCenBandWidthPackages", "CenBandWidthPackageSet", condition)
+}
+
+func (s *CenService) ReadCenBandwidthPackage(d *schema.ResourceData, packageId string) (data map[string]interface{}, err error) {
+	var results []interface{}
+	if packageId == "" {
+		packageId = d.Id()
+	}
+	req := map[string]interface{}{
+		"CenBandWidthPackageId.1": packageId,
+	}
+	err = addProjectInfoAll(d, &req, s.client)
+	if err != nil {
+		return data, err
+	}
+	results, err = s.ReadCenBandwidthPackages(req)
+	if err != nil {
+		return data, err
+	}
+	for _, v := range results {
+		data = v.(map[string]interface{})
+	}
+	if len(data) == 0 {
+		return data, fmt.Errorf("CenBandwidthPackage %s not exist ", packageId)
+	}
+	return data, err
+}
+
+func (s *CenService) ReadAndSetCenBandwidthPackage(d *schema.ResourceData, r *schema.Resource) (err error) {
+	data, err := s.ReadCenBandwidthPackage(d, "")
+	if err != nil {
+		return err
+	}
+	extra := chargeExtraForVpc(data)
+	if v, ok := data["ServiceEndTime"].(string); !ok || v == "" {
+		delete(extra, "ServiceEndTime")
+	}
+	if _, ok := data["CenId"]; !ok {
+		data["CenId"] = ""
+	}
+	SdkResponseAutoResourceData(d, r, data, extra)
+	return err
+}
+
+func (s *CenService) CreateCenBandwidthPackageCall(d *schema.ResourceData, r *schema.Resource) (callback ApiCall, err error) {
+	req, err := SdkRequestAutoMapping(d, r, false, nil, nil, SdkReqParameter{
+		onlyTransform: false,
+	})
+	if err != nil {
+		return callback, err
+	}
+	callback = ApiCall{
+		param:  &req,
+		action: "CreateCenBandWidthPackage",
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			return cenRequest(client, call.action, call.param)
+		},
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			id, err := getSdkValue("CenBandWidthPackage.CenBandWidthPackageId", *resp)
+			if err != nil {
+				return err
+			}
+			d.SetId(id.(string))
+			return err
+		},
+	}
+	return callback, err
+}
+
+func (s *CenService) CreateCenBandwidthPackage(d *schema.ResourceData, r *schema.Resource) (err error) {
+	call, err := s.CreateCenBandwidthPackageCall(d, r)
+	if err != nil {
+		return err
+	}
+	return ksyunApiCallNew([]ApiCall{call}, d, s.client, true)
+}
+
+func (s *CenService) ModifyCenBandwidthPackageCall(d *schema.ResourceData, r *schema.Resource) (callback ApiCall, err error) {
+	transform := map[string]SdkReqTransform{
+		"cen_id": {Ignore: true},
+	}
+	req, err := SdkRequestAutoMapping(d, r, true, transform, nil, SdkReqParameter{
+		onlyTransform: false,
+	})
+	if err != nil {
+		return callback, err
+	}
+	if len(req) > 0 {
+		req["CenBandWidthPackageId"] = d.Id()
+		callback = ApiCall{
+			param:  &req,
+			action: "ModifyCenBandWidthPackage",
+			executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+				logger.Debug(logger.RespFormat, call.action, *(call.param))
+				return cenRequest(client, call.action, call.param)
+			},
+			afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+				logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+				return err
+			},
+		}
+	}
+	return callback, err
+}
+
+// CenBandwidthPackageAttachCall attaches the package to cen or detaches it from cen by action
+func (s *CenService) CenBandwidthPackageAttachCall(action string, packageId string, cenId string) (callback ApiCall) {
+	req := map[string]interface{}{
+		"CenBandWidthPackageId": packageId,
+		"CenId":                 cenId,
+	}
+	return ApiCall{
+		param:  &req,
+		action: action,
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			return cenRequest(client, call.action, call.param)
+		},
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			return err
+		},
+	}
+}
+
+func (s *CenService) ModifyCenBandwidthPackage(d *schema.ResourceData, r *schema.Resource) (err error) {
+	var calls []ApiCall
+	if d.HasChange("cen_id") {
+		oldCen, newCen := d.GetChange("cen_id")
+		if oldCen.(string) != "" {
+			calls = append(calls, s.CenBandwidthPackageAttachCall("DetachCenBandWidthPackage", d.Id(), oldCen.(string)))
+		}
+		if newCen.(string) != "" {
+			calls = append(calls, s.CenBandwidthPackageAttachCall("AttachCenBandWidthPackage", d.Id(), newCen.(string)))
+		}
+	}
+	call, err := s.ModifyCenBandwidthPackageCall(d, r)
+	if err != nil {
+		return err
+	}
+	calls = append(calls, call)
+	// the calls are checked by dry run one by one, as each of them depends on the former
+	for _, call := range calls {
+		if err = ksyunApiCallNew([]ApiCall{call}, d, s.client, true); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (s *CenService) RemoveCenBandwidthPackageCall(d *schema.ResourceData) (callback ApiCall, err error) {
+	removeReq := map[string]interface{}{
+		"CenBandWidthPackageId": d.Id(),
+	}
+	callback = ApiCall{
+		param:  &removeReq,
+		action: "DeleteCenBandWidthPackage",
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			return cenRequest(client, call.action, call.param)
+		},
+		callError: removeCenCallError("cen bandwidth package", func() error {
+			_, err := s.ReadCenBandwidthPackage(d, "")
+			return err
+		}),
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			return err
+		},
+	}
+	return callback, err
+}
+
+func (s *CenService) RemoveCenBandwidthPackage(d *schema.ResourceData) (err error) {
+	// the package must be detached before deletion
+	if cenId := d.Get("cen_id").(string); cenId != "" {
+		call := s.CenBandwidthPackageAttachCall("DetachCenBandWidthPackage", d.Id(), cenId)
+		if err = ksyunApiCallNew([]ApiCall{call}, d, s.client, true); err != nil {
+			return err
+		}
+	}
+	call, err := s.RemoveCenBandwidthPackageCall(d)
+	if err != nil {
+		return err
+	}
+	return ksyunApiCallNew([]ApiCall{call}, d, s.client, true)
+}
+
+// cen region bandwidth
+
+func (s *CenService) ReadCenRegionBandwidths(condition map[string]interface{}) (data []interface{}, err error) {
+	return s.readCenSet("DescribeCenRegionBandwidths", "CenRegionBandwidthSet", condition)
+}
+
+func (s *CenService) ReadCenRegionBandwidth(d *schema.ResourceData, bandwidthId string) (data map[string]interface{}, err error) {
+	var results []interface{}
+	if bandwidthId == "" {
+		bandwidthId = d.Id()
+	}
+	req := map[string]interface{}{
+		"CenRegionBandwidthId.1": bandwidthId,
+	}
+	results, err = s.ReadCenRegionBandwidths(req)
+	if err != nil {
+		return data, err
+	}
+	for _, v := range results {
+		data = v.(map[string]interface{})
+	}
+	if len(data) == 0 {
+		return data, fmt.Errorf("CenRegionBandwidth %s not exist ", bandwidthId)
+	}
+	return data, err
+}
+
+func (s *CenService) ReadAndSetCenRegionBandwidth(d *schema.ResourceData, r *schema.Resource) (err error) {
+	data, err := s.ReadCenRegionBandwidth(d, "")
+	if err != nil {
+		return err
+	}
+	SdkResponseAutoResourceData(d, r, data, nil)
+	return err
+}
+
+func (s *CenService) CreateCenRegionBandwidthCall(d *schema.ResourceData, r *schema.Resource) (callback ApiCall, err error) {
+	req, err := SdkRequestAutoMapping(d, r, false, nil, nil, SdkReqParameter{
+		onlyTransform: false,
+	})
+	if err != nil {
+		return callback, err
+	}
+	callback = ApiCall{
+		param:  &req,
+		action: "CreateCenRegionBandwidth",
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			return cenRequest(client, call.action, call.param)
+		},
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			id, err := getSdkValue("CenRegionBandwidth.CenRegionBandwidthId", *resp)
+			if err != nil {
+				return err
+			}
+			d.SetId(id.(string))
+			return err
+		},
+	}
+	return callback, err
+}
+
+func (s *CenService) CreateCenRegionBandwidth(d *schema.ResourceData, r *schema.Resource) (err error) {
+	call, err := s.CreateCenRegionBandwidthCall(d, r)
+	if err != nil {
+		return err
+	}
+	return ksyunApiCallNew([]ApiCall{call}, d, s.client, true)
+}
+
+func (s *CenService) ModifyCenRegionBandwidthCall(d *schema.ResourceData, r *schema.Resource) (callback ApiCall, err error) {
+	req, err := SdkRequestAutoMapping(d, r, true, nil, nil)
+	if err != nil {
+		return callback, err
+	}
+	if len(req) > 0 {
+		req["CenRegionBandwidthId"] = d.Id()
+		callback = ApiCall{
+			param:  &req,
+			action: "ModifyCenRegionBandwidth",
+			executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+				logger.Debug(logger.RespFormat, call.action, *(call.param))
+				return cenRequest(client, call.action, call.param)
+			},
+			afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+				logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+				return err
+			},
+		}
+	}
+	return callback, err
+}
+
+func (s *CenService) ModifyCenRegionBandwidth(d *schema.ResourceData, r *schema.Resource) (err error) {
+	call, err := s.ModifyCenRegionBandwidthCall(d, r)
+	if err != nil {
+		return err
+	}
+	return ksyunApiCallNew([]ApiCall{call}, d, s.client, true)
+}
+
+func (s *CenService) RemoveCenRegionBandwidthCall(d *schema.ResourceData) (callback ApiCall, err error) {
+	removeReq := map[string]interface{}{
+		"CenRegionBandwidthId": d.Id(),
+	}
+	callback = ApiCall{
+		param:  &removeReq,
+		action: "DeleteCenRegionBandwidth",
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			return cenRequest(client, call.action, call.param)
+		},
+		callError: removeCenCallError("cen region bandwidth", func() error {
+			_, err := s.ReadCenRegionBandwidth(d, "")
+			return err
+		}),
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			return err
+		},
+	}
+	return callback, err
+}
+
+func (s *CenService) RemoveCenRegionBandwidth(d *schema.ResourceData) (err error) {
+	call, err := s.RemoveCenRegionBandwidthCall(d)
+	if err != nil {
+		return err
+	}
+	return ksyunApiCallNew([]ApiCall{call}, d, s.client, true)
+}
+
+// cen route entries
+
+func (s *CenService) ReadCenRouteEntries(condition map[string]interface{}) (data []interface{}, err error) {
+	return s.readCenSet("DescribeCenRoutes", "CenRouteSet", condition)
+}
+
+func (s *CenService) ReadAndSetCenRouteEntries(d *schema.ResourceData, r *schema.Resource) (err error) {
+	transform := map[string]SdkReqTransform{
+		"ids": {
+			mapping: "CenRouteId",
+			Type:    TransformWithN,
+		},
+		"cen_id": {
+			mapping: "cen-id",
+			Type:    TransformWithFilter,
+		},
+		"network_instance_id": {
+			mapping: "network-instance-id",
+			Type:    TransformWithFilter,
+		},
+	}
+	req, err := mergeDataSourcesReq(d, r, transform)
+	if err != nil {
+		return err
+	}
+	data, err := s.ReadCenRouteEntries(req)
+	if err != nil {
+		return err
+	}
+
+	return mergeDataSourcesResp(d, r, ksyunDataSource{
+		collection:  data,
+		nameField:   "DestinationCidrBlock",
+		idFiled:     "CenRouteId",
+		targetField: "cen_route_entries",
+		extra: map[string]SdkResponseMapping{
+			"CenRouteId": {
+				Field:    "id",
+				KeepAuto: false,
+			},
+		},
+	})
+}
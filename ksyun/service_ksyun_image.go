@@ -1,6 +1,10 @@
 package ksyun
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/terraform-providers/terraform-provider-ksyun/logger"
 )
@@ -97,3 +101,497 @@ func (s *ImageService) ReadAndSetKecImages(d *schema.ResourceData, r *schema.Res
 		return result, flag, err
 	})
 }
+
+func (s *ImageService) ReadImage(d *schema.ResourceData, imageId string) (data map[string]interface{}, err error) {
+	if imageId == "" {
+		imageId = d.Id()
+	}
+	results, err := s.readKecImages(map[string]interface{}{
+		"ImageId": imageId,
+	})
+	if err != nil {
+		return data, err
+	}
+	for _, v := range results {
+		if image, ok := v.(map[string]interface{}); ok && image["ImageId"] == imageId {
+			data = image
+		}
+	}
+	if len(data) == 0 {
+		return data, fmt.Errorf("Image %s not exist ", imageId)
+	}
+	return data, err
+}
+
+func (s *ImageService) ReadImageShareAccounts(imageId string) (accountIds []string, err error) {
+	var (
+		resp    *map[string]interface{}
+		results interface{}
+	)
+	conn := s.client.kecconn
+	req := map[string]interface{}{
+		"ImageId": imageId,
+	}
+	action := "DescribeImageSharePermission"
+	logger.Debug(logger.ReqFormat, action, req)
+	resp, err = conn.DescribeImageSharePermission(&req)
+	if err != nil {
+		return accountIds, err
+	}
+	results, err = getSdkValue("SharePermissionSet", *resp)
+	if err != nil {
+		return accountIds, nil
+	}
+	for _, v := range results.([]interface{}) {
+		if accountId, ok := v.(map[string]interface{})["AccountId"].(string); ok {
+			accountIds = append(accountIds, accountId)
+		}
+	}
+	return accountIds, err
+}
+
+func (s *ImageService) ReadAndSetImage(d *schema.ResourceData, r *schema.Resource) (err error) {
+	return resource.Retry(5*time.Minute, func() *resource.RetryError {
+		data, callErr := s.ReadImage(d, "")
+		if callErr != nil {
+			if !d.IsNewResource() {
+				return resource.NonRetryableError(callErr)
+			}
+			if notFoundError(callErr) {
+				return resource.RetryableError(callErr)
+			} else {
+				return resource.NonRetryableError(fmt.Errorf("error on reading image %q, %s", d.Id(), callErr))
+			}
+		}
+		accountIds, callErr := s.ReadImageShareAccounts(d.Id())
+		if callErr != nil {
+			return resource.NonRetryableError(fmt.Errorf("error on reading share permission of image %q, %s", d.Id(), callErr))
+		}
+		SdkResponseAutoResourceData(d, r, data, nil)
+		if err := d.Set("share_account_ids", accountIds); err != nil {
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+}
+
+func (s *ImageService) checkImageState(d *schema.ResourceData, imageId string, target []string, timeout time.Duration) (err error) {
+	stateConf := &resource.StateChangeConf{
+		Pending:      []string{},
+		Target:       target,
+		Refresh:      s.imageStateRefreshFunc(d, imageId, []string{"error"}),
+		Timeout:      timeout,
+		PollInterval: 5 * time.Second,
+		Delay:        10 * time.Second,
+		MinTimeout:   1 * time.Second,
+	}
+	_, err = stateConf.WaitForState()
+	return err
+}
+
+func (s *ImageService) imageStateRefreshFunc(d *schema.ResourceData, imageId string, failStates []string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		data, err := s.ReadImage(d, imageId)
+		if err != nil {
+			return nil, "", err
+		}
+
+		status, err := getSdkValue("ImageState", data)
+		if err != nil {
+			return nil, "", err
+		}
+
+		for _, v := range failStates {
+			if v == status.(string) {
+				return nil, "", fmt.Errorf("image status error, status:%v", status)
+			}
+		}
+		return data, status.(string), nil
+	}
+}
+
+func (s *ImageService) CreateImageCall(d *schema.ResourceData, r *schema.Resource) (callback ApiCall, err error) {
+	transform := map[string]SdkReqTransform{
+		"data_disk_ids": {
+			mapping: "DataDiskIds",
+			Type:    TransformWithN,
+		},
+		"snapshot_id": {
+			mapping: "SnapshotIds",
+			Type:    TransformSingleN,
+		},
+		"share_account_ids": {Ignore: true},
+		"copy_to_regions":   {Ignore: true},
+	}
+	req, err := SdkRequestAutoMapping(d, r, false, transform, nil, SdkReqParameter{
+		onlyTransform: false,
+	})
+	if err != nil {
+		return callback, err
+	}
+	if req["InstanceId"] == nil && req["SnapshotIds.1"] == nil {
+		return callback, fmt.Errorf("one of instance_id and snapshot_id must be set")
+	}
+	callback = ApiCall{
+		param:  &req,
+		action: "CreateImage",
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			conn := client.kecconn
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			resp, err = conn.CreateImage(call.param)
+			return resp, err
+		},
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			id, err := getSdkValue("ImageId", *resp)
+			if err != nil {
+				return err
+			}
+			d.SetId(id.(string))
+			return s.checkImageState(d, "", []string{"active"}, d.Timeout(schema.TimeoutCreate))
+		},
+	}
+	return callback, err
+}
+
+func (s *ImageService) ModifyImageAttributeCall(d *schema.ResourceData, r *schema.Resource) (callback ApiCall, err error) {
+	if !d.HasChange("name") {
+		return callback, err
+	}
+	req := map[string]interface{}{
+		"ImageId": d.Id(),
+		"Name":    d.Get("name"),
+	}
+	callback = ApiCall{
+		param:  &req,
+		action: "ModifyImageAttribute",
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			conn := client.kecconn
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			resp, err = conn.ModifyImageAttribute(call.param)
+			return resp, err
+		},
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			return err
+		},
+	}
+	return callback, err
+}
+
+// ModifyImageSharePermissionCall shares the image with the accounts or cancels the sharing,
+// the permission is share or cancel.
+func (s *ImageService) ModifyImageSharePermissionCall(accountIds []string, permission string) (callback ApiCall, err error) {
+	if len(accountIds) == 0 {
+		return callback, err
+	}
+	req := map[string]interface{}{
+		"Permission": permission,
+	}
+	for i, accountId := range accountIds {
+		req[fmt.Sprintf("AccountId.%d", i+1)] = accountId
+	}
+	// the image id is unknown until the image is created, so the dry run is disabled
+	callback = ApiCall{
+		param:         &req,
+		action:        "ModifyImageSharePermission",
+		disableDryRun: true,
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			conn := client.kecconn
+			(*call.param)["ImageId"] = d.Id()
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			resp, err = conn.ModifyImageSharePermission(call.param)
+			return resp, err
+		},
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			return err
+		},
+	}
+	return callback, err
+}
+
+// CopyImageCall copies the image to the regions, the copies are tracked by copied_image_ids once they
+// are active in the destination regions.
+func (s *ImageService) CopyImageCall(regions []string) (callback ApiCall, err error) {
+	if len(regions) == 0 {
+		return callback, err
+	}
+	req := make(map[string]interface{})
+	for i, region := range regions {
+		req[fmt.Sprintf("DestinationRegion.%d", i+1)] = region
+	}
+	callback = ApiCall{
+		param:         &req,
+		action:        "CopyImage",
+		disableDryRun: true,
+		beforeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (bool, error) {
+			// the copies are found by the name, so the name must be unique in the destination regions
+			for _, region := range regions {
+				images, err := s.readImagesByName(region, d.Get("name").(string))
+				if err != nil {
+					return false, err
+				}
+				if len(images) > 0 {
+					return false, fmt.Errorf("the image named %q already exists in region %s, the name of the copy must be unique",
+						d.Get("name"), region)
+				}
+			}
+			return true, nil
+		},
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			conn := client.kecconn
+			(*call.param)["ImageId.1"] = d.Id()
+			(*call.param)["DestinationImageName"] = d.Get("name")
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			resp, err = conn.CopyImage(call.param)
+			return resp, err
+		},
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			return s.trackImageCopies(d, regions)
+		},
+	}
+	return callback, err
+}
+
+// readImagesByName reads the images named as name in the region
+func (s *ImageService) readImagesByName(region string, name string) (data []map[string]interface{}, err error) {
+	_, err = s.client.WithRegionClient(region, func(client *KsyunClient) (interface{}, error) {
+		destService := ImageService{client}
+		images, err := destService.readKecImages(nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range images {
+			if image, ok := v.(map[string]interface{}); ok && image["Name"] == name {
+				data = append(data, image)
+			}
+		}
+		return data, err
+	})
+	return data, err
+}
+
+// readImageCopy returns the copy of the image in the region, which is nil if the copy doesn't appear yet.
+// CopyImage doesn't return the ids of the copies, so the copy is found by the name and the original image.
+func (s *ImageService) readImageCopy(d *schema.ResourceData, region string) (copied map[string]interface{}, err error) {
+	images, err := s.readImagesByName(region, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	for _, image := range images {
+		if image["OriginalImageId"] != d.Id() {
+			continue
+		}
+		if copied != nil {
+			return nil, fmt.Errorf("more than one copy of image %q named %q in region %s", d.Id(), d.Get("name"), region)
+		}
+		copied = image
+	}
+	return copied, err
+}
+
+// trackImageCopies waits the copies to be active in the regions and sets their ids to copied_image_ids
+func (s *ImageService) trackImageCopies(d *schema.ResourceData, regions []string) (err error) {
+	timeout := d.Timeout(schema.TimeoutUpdate)
+	if d.IsNewResource() {
+		timeout = d.Timeout(schema.TimeoutCreate)
+	}
+	copies := d.Get("copied_image_ids").(map[string]interface{})
+	for _, region := range regions {
+		stateConf := &resource.StateChangeConf{
+			Pending: []string{"copying"},
+			Target:  []string{"active"},
+			Refresh: func() (interface{}, string, error) {
+				copied, err := s.readImageCopy(d, region)
+				if err != nil {
+					return nil, "", err
+				}
+				if copied == nil {
+					return region, "copying", nil
+				}
+				switch copied["ImageState"] {
+				case "active":
+					return copied["ImageId"], "active", nil
+				case "error":
+					return nil, "", fmt.Errorf("the copy %v is in error state", copied["ImageId"])
+				}
+				return region, "copying", nil
+			},
+			Timeout:    timeout,
+			Delay:      5 * time.Second,
+			MinTimeout: 5 * time.Second,
+		}
+		imageId, err := stateConf.WaitForState()
+		if err != nil {
+			return fmt.Errorf("error on waiting the copy of image %q in region %s, %s", d.Id(), region, err)
+		}
+		copies[region] = imageId
+		if err = d.Set("copied_image_ids", copies); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+// removeImageCopies removes the copies of the image in the regions, the copies already deleted are skipped.
+func (s *ImageService) removeImageCopies(d *schema.ResourceData, regions []string) (err error) {
+	copies := d.Get("copied_image_ids").(map[string]interface{})
+	for _, region := range regions {
+		imageId, ok := copies[region].(string)
+		if !ok {
+			continue
+		}
+		_, err = s.client.WithRegionClient(region, func(client *KsyunClient) (interface{}, error) {
+			destService := ImageService{client}
+			return nil, destService.removeImageById(imageId, d.Timeout(schema.TimeoutDelete))
+		})
+		if err != nil {
+			return fmt.Errorf("error on removing the copy %q in region %s, %s", imageId, region, err)
+		}
+		delete(copies, region)
+		if err = d.Set("copied_image_ids", copies); err != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (s *ImageService) removeImageById(imageId string, timeout time.Duration) (err error) {
+	req := map[string]interface{}{
+		"ImageId": imageId,
+	}
+	return resource.Retry(timeout, func() *resource.RetryError {
+		_, callErr := s.ReadImage(nil, imageId)
+		if callErr != nil {
+			if notFoundError(callErr) {
+				return nil
+			}
+			return resource.NonRetryableError(callErr)
+		}
+		conn := s.client.kecconn
+		action := "RemoveImages"
+		logger.Debug(logger.ReqFormat, action, req)
+		if _, callErr = conn.RemoveImages(&req); callErr != nil {
+			return retryError(callErr)
+		}
+		return resource.RetryableError(fmt.Errorf("image %q is still being deleted", imageId))
+	})
+}
+
+// imageSetChange returns the elements added to and removed from the set of key
+func imageSetChange(d *schema.ResourceData, key string) (added []string, removed []string) {
+	o, n := d.GetChange(key)
+	oldSet, newSet := o.(*schema.Set), n.(*schema.Set)
+	return SchemaSetToStringSlice(newSet.Difference(oldSet)), SchemaSetToStringSlice(oldSet.Difference(newSet))
+}
+
+func (s *ImageService) CreateImage(d *schema.ResourceData, r *schema.Resource) (err error) {
+	call, err := s.CreateImageCall(d, r)
+	if err != nil {
+		return err
+	}
+	shareCall, err := s.ModifyImageSharePermissionCall(SchemaSetToStringSlice(d.Get("share_account_ids")), "share")
+	if err != nil {
+		return err
+	}
+	copyCall, err := s.CopyImageCall(SchemaSetToStringSlice(d.Get("copy_to_regions")))
+	if err != nil {
+		return err
+	}
+	return ksyunApiCallNew([]ApiCall{call, shareCall, copyCall}, d, s.client, true)
+}
+
+func (s *ImageService) ModifyImage(d *schema.ResourceData, r *schema.Resource) (err error) {
+	call, err := s.ModifyImageAttributeCall(d, r)
+	if err != nil {
+		return err
+	}
+	shared, unshared := imageSetChange(d, "share_account_ids")
+	shareCall, err := s.ModifyImageSharePermissionCall(shared, "share")
+	if err != nil {
+		return err
+	}
+	cancelCall, err := s.ModifyImageSharePermissionCall(unshared, "cancel")
+	if err != nil {
+		return err
+	}
+	regions, removed := imageSetChange(d, "copy_to_regions")
+	copyCall, err := s.CopyImageCall(regions)
+	if err != nil {
+		return err
+	}
+	err = ksyunApiCallNew([]ApiCall{call, cancelCall, shareCall, copyCall}, d, s.client, true)
+	if err != nil {
+		return err
+	}
+	return s.removeImageCopies(d, removed)
+}
+
+func (s *ImageService) RemoveImageCall(d *schema.ResourceData) (callback ApiCall, err error) {
+	removeReq := map[string]interface{}{
+		"ImageId": d.Id(),
+	}
+	callback = ApiCall{
+		param:  &removeReq,
+		action: "RemoveImages",
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			conn := client.kecconn
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			resp, err = conn.RemoveImages(call.param)
+			return resp, err
+		},
+		callError: func(d *schema.ResourceData, client *KsyunClient, call ApiCall, baseErr error) error {
+			return resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+				_, callErr := s.ReadImage(d, "")
+				if callErr != nil {
+					if notFoundError(callErr) {
+						return nil
+					} else {
+						return resource.NonRetryableError(fmt.Errorf("error on reading image when delete %q, %s", d.Id(), callErr))
+					}
+				}
+				_, callErr = call.executeCall(d, client, call)
+				if callErr == nil {
+					return nil
+				}
+				return resource.RetryableError(callErr)
+			})
+		},
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			return resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+				_, callErr := s.ReadImage(d, "")
+				if callErr != nil {
+					if notFoundError(callErr) {
+						return nil
+					}
+					return resource.NonRetryableError(callErr)
+				}
+				return resource.RetryableError(fmt.Errorf("image %q is still being deleted", d.Id()))
+			})
+		},
+	}
+	return callback, err
+}
+
+func (s *ImageService) RemoveImage(d *schema.ResourceData) (err error) {
+	var regions []string
+	for region := range d.Get("copied_image_ids").(map[string]interface{}) {
+		regions = append(regions, region)
+	}
+	if err = s.removeImageCopies(d, regions); err != nil {
+		return err
+	}
+	// the image shared with other accounts can't be removed
+	cancelCall, err := s.ModifyImageSharePermissionCall(SchemaSetToStringSlice(d.Get("share_account_ids")), "cancel")
+	if err != nil {
+		return err
+	}
+	call, err := s.RemoveImageCall(d)
+	if err != nil {
+		return err
+	}
+	return ksyunApiCallNew([]ApiCall{cancelCall, call}, d, s.client, true)
+}
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"net"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/terraform-providers/terraform-provider-ksyun/ksyun/internal/pkg/helper"
@@ -226,6 +228,215 @@ func (s *KecService) ReadAndSetKecInstances(d *schema.ResourceData, r *schema.Re
 	})
 }
 
+func (s *KecService) ReadAndSetInstanceTypes(d *schema.ResourceData, r *schema.Resource) (err error) {
+	if _, ok := d.GetOk("max_price"); ok {
+		if _, ok = d.GetOk("charge_type"); !ok {
+			return fmt.Errorf("max_price must be used with charge_type")
+		}
+	}
+	data, err := s.readInstanceTypes()
+	if err != nil {
+		return err
+	}
+	// the smallest types come first, so the first one is the cheapest choice meeting the filters
+	sort.SliceStable(data, func(i, j int) bool {
+		a, b := data[i].(map[string]interface{}), data[j].(map[string]interface{})
+		for _, k := range []string{"Cpu", "Memory", "Gpu"} {
+			if a[k].(int) != b[k].(int) {
+				return a[k].(int) < b[k].(int)
+			}
+		}
+		return a["InstanceType"].(string) < b["InstanceType"].(string)
+	})
+
+	data, err = matchDataSourcesResp(d, data, func(data *schema.ResourceData, m map[string]interface{}) (result map[string]interface{}, flag bool, err error) {
+		if zone, ok := d.GetOk("availability_zone"); ok {
+			flag = true
+			for _, v := range m["AvailabilityZones"].([]interface{}) {
+				if v == zone {
+					result = m
+				}
+			}
+		}
+		return result, flag, err
+	}, func(data *schema.ResourceData, m map[string]interface{}) (result map[string]interface{}, flag bool, err error) {
+		if family, ok := d.GetOk("instance_family"); ok {
+			flag = true
+			if m["InstanceFamily"] == family {
+				result = m
+			}
+		}
+		return result, flag, err
+	}, func(data *schema.ResourceData, m map[string]interface{}) (result map[string]interface{}, flag bool, err error) {
+		flag = true
+		for field, k := range map[string]string{"cpu": "Cpu", "memory": "Memory", "gpu": "Gpu"} {
+			if v, ok := d.GetOk(field); ok && m[k].(int) != v.(int) {
+				return nil, flag, err
+			}
+			if v, ok := d.GetOk("min_" + field); ok && m[k].(int) < v.(int) {
+				return nil, flag, err
+			}
+		}
+		return m, flag, err
+	})
+	if err != nil {
+		return err
+	}
+
+	if chargeType, ok := d.GetOk("charge_type"); ok {
+		// the prices are queried one type by one type, only for the types meeting the other filters
+		data, err = matchDataSourcesResp(d, data, func(data *schema.ResourceData, m map[string]interface{}) (result map[string]interface{}, flag bool, err error) {
+			m["Price"], m["PriceUnit"], err = s.readInstanceTypePrice(m["InstanceType"].(string), chargeType.(string))
+			if err != nil {
+				return nil, true, err
+			}
+			if maxPrice, ok := d.GetOk("max_price"); ok && m["Price"].(float64) > maxPrice.(float64) {
+				return nil, true, err
+			}
+			return m, true, err
+		})
+		if err != nil {
+			return err
+		}
+		// the cheapest type comes first, the types of the same price keep the order of size
+		sort.SliceStable(data, func(i, j int) bool {
+			return data[i].(map[string]interface{})["Price"].(float64) < data[j].(map[string]interface{})["Price"].(float64)
+		})
+	}
+
+	return mergeDataSourcesResp(d, r, ksyunDataSource{
+		collection:  data,
+		idFiled:     "InstanceType",
+		nameField:   "InstanceType",
+		targetField: "instance_types",
+		extra:       map[string]SdkResponseMapping{},
+	})
+}
+
+// readInstanceTypePrice returns the trade price of an instance of the type by the charge type
+func (s *KecService) readInstanceTypePrice(instanceType, chargeType string) (price float64, unit string, err error) {
+	req := map[string]interface{}{
+		"InstanceType": instanceType,
+		"ChargeType":   chargeType,
+	}
+	conn := s.client.kecconn
+	action := "DescribePrice"
+	// DescribePrice is absent in the kec sdk
+	op := &request.Operation{
+		Name:       action,
+		HTTPMethod: "GET",
+		HTTPPath:   "/",
+	}
+	resp := &map[string]interface{}{}
+	logger.Debug(logger.ReqFormat, action, req)
+	if err = conn.NewRequest(op, &req, resp).Send(); err != nil {
+		return price, unit, fmt.Errorf("error on reading the price of instance type %s, %s", instanceType, err)
+	}
+	logger.Debug(logger.RespFormat, action, req, *resp)
+	v, err := getSdkValue("PriceInfo.InstancePrice.TradePrice", *resp)
+	if err != nil {
+		return price, unit, fmt.Errorf("the price of instance type %s is absent", instanceType)
+	}
+	switch n := v.(type) {
+	case float64:
+		price = n
+	case string:
+		price, _ = strconv.ParseFloat(n, 64)
+	}
+	if v, err := getSdkValue("PriceInfo.InstancePrice.PriceUnit", *resp); err == nil {
+		unit, _ = v.(string)
+	}
+	return price, unit, nil
+}
+
+// readInstanceTypes returns the instance type configs flattened to the fields of ksyun_instance_types.
+func (s *KecService) readInstanceTypes() (data []interface{}, err error) {
+	var (
+		resp    *map[string]interface{}
+		results interface{}
+	)
+	conn := s.client.kecconn
+	action := "DescribeInstanceTypeConfigs"
+	logger.Debug(logger.ReqFormat, action, nil)
+	resp, err = conn.DescribeInstanceTypeConfigs(nil)
+	if err != nil {
+		return data, err
+	}
+	logger.Debug(logger.RespFormat, action, nil, *resp)
+	results, err = getSdkValue("InstanceTypeConfigSet", *resp)
+	if err != nil {
+		return data, err
+	}
+	configs, _ := results.([]interface{})
+	for _, v := range configs {
+		config, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		item := map[string]interface{}{
+			"InstanceType":          config["InstanceType"],
+			"InstanceFamily":        config["InstanceFamily"],
+			"InstanceFamilyName":    config["InstanceFamilyName"],
+			"Cpu":                   instanceTypeInt(config["CPU"]),
+			"Memory":                instanceTypeInt(config["Memory"]),
+			"Gpu":                   instanceTypeInt(config["GPU"]),
+			"GpuSpec":               config["GPUspec"],
+			"NetworkInterfaceCount": 0,
+			"PrivateIpCount":        0,
+			"AvailabilityZones":     []interface{}{},
+			"SystemDiskTypes":       []interface{}{},
+			"DataDiskQuotas":        []interface{}{},
+		}
+		if quota, ok := config["NetworkInterfaceQuota"].(map[string]interface{}); ok {
+			item["NetworkInterfaceCount"] = instanceTypeInt(quota["NetworkInterfaceCount"])
+		}
+		if quota, ok := config["PrivateIpQuota"].(map[string]interface{}); ok {
+			item["PrivateIpCount"] = instanceTypeInt(quota["PrivateIpCount"])
+		}
+		if zones, ok := config["AvailabilityZoneSet"].([]interface{}); ok {
+			for _, zone := range zones {
+				if z, ok := zone.(map[string]interface{}); ok {
+					item["AvailabilityZones"] = append(item["AvailabilityZones"].([]interface{}), z["AzCode"])
+				}
+			}
+		}
+		if disks, ok := config["SystemDiskQuotaSet"].([]interface{}); ok {
+			for _, disk := range disks {
+				if q, ok := disk.(map[string]interface{}); ok {
+					item["SystemDiskTypes"] = append(item["SystemDiskTypes"].([]interface{}), q["SystemDiskType"])
+				}
+			}
+		}
+		if disks, ok := config["DataDiskQuotaSet"].([]interface{}); ok {
+			for _, disk := range disks {
+				if q, ok := disk.(map[string]interface{}); ok {
+					item["DataDiskQuotas"] = append(item["DataDiskQuotas"].([]interface{}), map[string]interface{}{
+						"DiskType": q["DataDiskType"],
+						"MinSize":  instanceTypeInt(q["DataDiskMinSize"]),
+						"MaxSize":  instanceTypeInt(q["DataDiskMaxsize"]),
+						"Count":    instanceTypeInt(q["DataDiskCount"]),
+					})
+				}
+			}
+		}
+		data = append(data, item)
+	}
+	return data, err
+}
+
+func instanceTypeInt(v interface{}) int {
+	switch n := v.(type) {
+	case float64:
+		return int(n)
+	case int:
+		return n
+	case string:
+		i, _ := strconv.Atoi(n)
+		return i
+	}
+	return 0
+}
+
 func (s *KecService) readKecNetworkInterface(networkInterfaceId string) (data map[string]interface{}, err error) {
 	var networkInterfaces []interface{}
 	vpcService := VpcService{s.client}
@@ -382,7 +593,7 @@ func (s *KecService) createKecInstance(d *schema.ResourceData, resource *schema.
 }
 
 func (s *KecService) kecRelatedAttachTags(d *schema.ResourceData, resource *schema.Resource) (calls []ApiCall, err error) {
-	if !d.HasChange("tags") {
+	if !hasTagsChange(d) {
 		return
 	}
 	dataDisksIf, ok := d.GetOk("data_disks")
@@ -411,7 +622,7 @@ func (s *KecService) kecRelatedAttachTags(d *schema.ResourceData, resource *sche
 		volumeIds = append(volumeIds, volumeId)
 	}
 
-	desiredTags := d.Get("tags").(map[string]interface{})
+	desiredTags := s.client.mergeDefaultTags(d.Get("tags").(map[string]interface{}))
 	for k, v := range desiredTags {
 		tags = append(tags, &Tag{
 			Key:   k,
@@ -470,6 +681,13 @@ func (s *KecService) modifyKecInstance(d *schema.ResourceData, resource *schema.
 	}
 	callbacks = append(callbacks, networkCall)
 
+	// data disks
+	dataDiskCalls, err := s.modifyKecInstanceDataDisks(d)
+	if err != nil {
+		return err
+	}
+	callbacks = append(callbacks, dataDiskCalls...)
+
 	// change an instance to another data guard group
 	modifyKecDGGCall, err := s.modifyKecInstanceDataGuardGroupCall(d, resource)
 	if err != nil {
@@ -500,7 +718,7 @@ func (s *KecService) modifyKecInstance(d *schema.ResourceData, resource *schema.
 		return err
 	}
 	if passCall.executeCall != nil || imageCall.executeCall != nil || addCall.executeCall != nil || removeCall.executeCall != nil {
-		stopCall, err := s.stopKecInstance(d)
+		stopCall, err := s.stopKecInstanceWithOptions(d, kecStopOptionsFromResource(d))
 		if err != nil {
 			return err
 		}
@@ -567,7 +785,35 @@ func (s *KecService) modifyKecInstance(d *schema.ResourceData, resource *schema.
 	return ksyunApiCallNew(callbacks, d, s.client, true)
 }
 
-func transKecInstanceParams(d *schema.ResourceData, resource *schema.Resource) (map[string]interface{}, error) {
+// modifyKecInstanceDataDisks changes the type and expands the size of EBS data disks in place,
+// the other changes of data disks are forced to create a new instance by kecDataDisksCustomizeDiff.
+func (s *KecService) modifyKecInstanceDataDisks(d *schema.ResourceData) (callbacks []ApiCall, err error) {
+	if !d.HasChange("data_disks") {
+		return callbacks, err
+	}
+	ebsService := EbsService{s.client}
+	o, n := d.GetChange("data_disks")
+	oldDisks, newDisks := o.([]interface{}), n.([]interface{})
+	for i, v := range newDisks {
+		if i >= len(oldDisks) {
+			break
+		}
+		oldDisk, newDisk := oldDisks[i].(map[string]interface{}), v.(map[string]interface{})
+		diskId, _ := oldDisk["disk_id"].(string)
+		if diskId == "" || oldDisk["disk_type"] == "Local_SSD" {
+			continue
+		}
+		if diskType := newDisk["disk_type"].(string); diskType != "" && diskType != oldDisk["disk_type"] {
+			callbacks = append(callbacks, ebsService.ModifyVolumeTypeCall(diskId, diskType))
+		}
+		if size := newDisk["disk_size"].(int); size > oldDisk["disk_size"].(int) {
+			callbacks = append(callbacks, ebsService.ResizeAttachedVolumeCall(diskId, size))
+		}
+	}
+	return callbacks, err
+}
+
+func transKecInstanceParams(d *schema.ResourceData, resource *schema.Resource, client *KsyunClient) (map[string]interface{}, error) {
 	transform := map[string]SdkReqTransform{
 		"key_id": {
 			Type: TransformWithN,
@@ -586,9 +832,14 @@ func transKecInstanceParams(d *schema.ResourceData, resource *schema.Resource) (
 			}, Type: TransformListN,
 		},
 		"instance_status":        {Ignore: true},
+		"stopped_mode":           {Ignore: true},
+		"stop_timeout":           {Ignore: true},
 		"force_delete":           {Ignore: true},
 		"force_reinstall_system": {Ignore: true},
 		"tags":                   {Ignore: true},
+		// the configs of the launch template are merged into the request below
+		"launch_template_id":      {Ignore: true},
+		"launch_template_version": {Ignore: true},
 	}
 
 	instanceParams, err := SdkRequestAutoMapping(d, resource, false, transform, nil, SdkReqParameter{
@@ -598,23 +849,45 @@ func transKecInstanceParams(d *schema.ResourceData, resource *schema.Resource) (
 		return instanceParams, err
 	}
 
+	if _, ok := resource.Schema["launch_template_id"]; ok {
+		if templateId, ok := d.GetOk("launch_template_id"); ok {
+			launchTemplateService := LaunchTemplateService{client}
+			err = launchTemplateService.addLaunchTemplateParams(templateId.(string), d.Get("launch_template_version").(int), instanceParams)
+			if err != nil {
+				return instanceParams, err
+			}
+		}
+		// they're optional as the launch template may provide them
+		for _, k := range []string{"image_id", "subnet_id", "security_group_id"} {
+			if !hasParamOf(instanceParams, Downline2Hump(k)) {
+				return instanceParams, fmt.Errorf("%s is required unless it's set in the launch template", k)
+			}
+		}
+	}
+
 	var syncTag interface{}
 	syncTag = d.Get("sync_tag")
 	instanceParams["SyncTag"] = syncTag
 
-	if tags, ok := d.GetOk("tags"); ok {
-		tagsMap := tags.(map[string]interface{})
-		idx := 1
-		for k, v := range tagsMap {
-			instanceParams["Tag."+strconv.Itoa(idx)+".Key"] = k
-			instanceParams["Tag."+strconv.Itoa(idx)+".Value"] = v
-			idx++
-		}
-	}
+	addKecInstanceTagParams(d, client, instanceParams)
 
 	return instanceParams, nil
 }
 
+// addKecInstanceTagParams adds the tags to the request of RunInstances, the tags are attached when instances are created
+func addKecInstanceTagParams(d *schema.ResourceData, client *KsyunClient, params map[string]interface{}) {
+	if !hasTags(d, client) {
+		return
+	}
+	tagsMap := client.mergeDefaultTags(d.Get("tags").(map[string]interface{}))
+	idx := 1
+	for k, v := range tagsMap {
+		params["Tag."+strconv.Itoa(idx)+".Key"] = k
+		params["Tag."+strconv.Itoa(idx)+".Value"] = v
+		idx++
+	}
+}
+
 func (s *KecService) createKecInstanceCommon(d *schema.ResourceData, r *schema.Resource) (callback ApiCall, err error) {
 	// transform := map[string]SdkReqTransform{
 	//	"key_id": {
@@ -641,7 +914,7 @@ func (s *KecService) createKecInstanceCommon(d *schema.ResourceData, r *schema.R
 	// createReq, err := SdkRequestAutoMapping(d, resource, false, transform, nil, SdkReqParameter{
 	//	onlyTransform: false,
 	// })
-	createReq, err := transKecInstanceParams(d, r)
+	createReq, err := transKecInstanceParams(d, r, s.client)
 	if err != nil {
 		return callback, err
 	}
@@ -1206,7 +1479,9 @@ func (s *KecService) stopOrStartKecInstance(d *schema.ResourceData) (callback Ap
 		if d.Get("instance_status") == "active" {
 			return s.startKecInstance(d)
 		} else {
-			return s.stopKecInstance(d)
+			options := kecStopOptionsFromResource(d)
+			options.stoppedMode = d.Get("stopped_mode").(string)
+			return s.stopKecInstanceWithOptions(d, options)
 		}
 	}
 	return callback, err
@@ -1300,10 +1575,32 @@ func (s *KecService) rebootKecInstance(d *schema.ResourceData) (callback ApiCall
 	return callback, err
 }
 
+// kecStopOptions are the options of stopping an instance
+type kecStopOptions struct {
+	// stoppedMode is KeepCharging or StopCharging, the default mode of api is used if it's empty
+	stoppedMode string
+	// forceTimeout is the time waiting for the instance to stop gracefully before stopping it forcibly,
+	// it's never stopped forcibly if forceTimeout is 0
+	forceTimeout time.Duration
+}
+
+func kecStopOptionsFromResource(d *schema.ResourceData) kecStopOptions {
+	return kecStopOptions{
+		forceTimeout: time.Duration(d.Get("stop_timeout").(int)) * time.Second,
+	}
+}
+
 func (s *KecService) stopKecInstance(d *schema.ResourceData) (callback ApiCall, err error) {
+	return s.stopKecInstanceWithOptions(d, kecStopOptions{})
+}
+
+func (s *KecService) stopKecInstanceWithOptions(d *schema.ResourceData, options kecStopOptions) (callback ApiCall, err error) {
 	updateReq := map[string]interface{}{
 		"InstanceId.1": d.Id(),
 	}
+	if options.stoppedMode != "" {
+		updateReq["StoppedMode"] = options.stoppedMode
+	}
 	callback = ApiCall{
 		param:  &updateReq,
 		action: "StopInstances",
@@ -1333,6 +1630,20 @@ func (s *KecService) stopKecInstance(d *schema.ResourceData) (callback ApiCall,
 		},
 		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
 			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			if options.forceTimeout > 0 {
+				err = s.checkKecInstanceState(d, "", []string{"stopped"}, options.forceTimeout)
+				if _, ok := err.(*resource.TimeoutError); !ok {
+					return err
+				}
+				forceReq := map[string]interface{}{"ForceStop": true}
+				for k, v := range *(call.param) {
+					forceReq[k] = v
+				}
+				logger.Debug(logger.ReqFormat, call.action, forceReq)
+				if _, err = client.kecconn.StopInstances(&forceReq); err != nil {
+					return err
+				}
+			}
 			err = s.checkKecInstanceState(d, "", []string{"stopped"}, d.Timeout(schema.TimeoutUpdate))
 			if err != nil {
 				return err
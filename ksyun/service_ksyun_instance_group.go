@@ -0,0 +1,331 @@
+package ksyun
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/terraform-providers/terraform-provider-ksyun/logger"
+)
+
+// kecBatchNamePattern matches the ordered suffix `[begin,digits]` of the names of instances launched in batch
+var kecBatchNamePattern = regexp.MustCompile(`\[(\d+),(\d+)\]`)
+
+// kecBatchName shifts the begin of the ordered suffix by offset, so the names of the instances launched in
+// the next batch continue the suffix of the group.
+func kecBatchName(name string, offset int) string {
+	loc := kecBatchNamePattern.FindStringSubmatchIndex(name)
+	if loc == nil || offset == 0 {
+		return name
+	}
+	begin, _ := strconv.Atoi(name[loc[2]:loc[3]])
+	return fmt.Sprintf("%s[%d,%s]%s", name[:loc[0]], begin+offset, name[loc[4]:loc[5]], name[loc[1]:])
+}
+
+// kecBatchOffset returns the offset of the ordered suffix of name, which makes the next batch start after the highest
+// suffix among the names of the existing instances, so the names are never reused even if a former instance is gone.
+func kecBatchOffset(name string, names []string) int {
+	loc := kecBatchNamePattern.FindStringSubmatchIndex(name)
+	if loc == nil {
+		return 0
+	}
+	begin, _ := strconv.Atoi(name[loc[2]:loc[3]])
+	pattern := regexp.MustCompile("^" + regexp.QuoteMeta(name[:loc[0]]) + `(\d+)` + regexp.QuoteMeta(name[loc[1]:]) + "$")
+	next := begin
+	for _, n := range names {
+		if m := pattern.FindStringSubmatch(n); m != nil {
+			if index, _ := strconv.Atoi(m[1]); index >= next {
+				next = index + 1
+			}
+		}
+	}
+	return next - begin
+}
+
+func (s *KecService) createKecInstanceGroup(d *schema.ResourceData, r *schema.Resource) (err error) {
+	count := d.Get("instance_count").(int)
+	ids, err := s.runKecInstanceGroupBatch(d, r, nil, count)
+	if err != nil {
+		return err
+	}
+	// the id of the group is only a handle, the instances are tracked by instance_ids
+	d.SetId(resource.PrefixedUniqueId("kec-group-"))
+	_ = d.Set("instance_ids", ids)
+	return s.checkKecInstanceGroupState(d, ids, "active", d.Timeout(schema.TimeoutCreate))
+}
+
+func (s *KecService) modifyKecInstanceGroup(d *schema.ResourceData, r *schema.Resource) (err error) {
+	// instance_ids is planned as unknown when the group scales, the instances are in the prior state
+	o, _ := d.GetChange("instance_ids")
+	members, err := s.readKecInstanceGroupMembers(d, kecInstanceGroupIds(o))
+	if err != nil {
+		return err
+	}
+	var ids []string
+	for _, id := range kecInstanceGroupIds(o) {
+		if _, ok := members[id]; ok {
+			ids = append(ids, id)
+		}
+	}
+	count := d.Get("instance_count").(int)
+	switch {
+	case count > len(ids):
+		var launched []string
+		launched, err = s.runKecInstanceGroupBatch(d, r, members, count-len(ids))
+		if err != nil {
+			return err
+		}
+		ids = append(ids, launched...)
+		_ = d.Set("instance_ids", ids)
+		return s.checkKecInstanceGroupState(d, launched, "active", d.Timeout(schema.TimeoutUpdate))
+	case count < len(ids):
+		// the newest instances are terminated first
+		err = s.terminateKecInstances(d, ids[count:], d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			return err
+		}
+		return d.Set("instance_ids", ids[:count])
+	}
+	return err
+}
+
+func (s *KecService) removeKecInstanceGroup(d *schema.ResourceData) (err error) {
+	return s.terminateKecInstances(d, kecInstanceGroupIds(d.Get("instance_ids")), d.Timeout(schema.TimeoutDelete))
+}
+
+func (s *KecService) readAndSetKecInstanceGroup(d *schema.ResourceData) (err error) {
+	ids := kecInstanceGroupIds(d.Get("instance_ids"))
+	members, err := s.readKecInstanceGroupMembers(d, ids)
+	if err != nil {
+		return err
+	}
+	var (
+		existIds  []string
+		instances []interface{}
+	)
+	for _, id := range ids {
+		data, ok := members[id]
+		if !ok {
+			continue
+		}
+		instance := map[string]interface{}{
+			"instance_id":        id,
+			"instance_name":      data["InstanceName"],
+			"host_name":          data["HostName"],
+			"private_ip_address": data["PrivateIpAddress"],
+		}
+		if state, err := getSdkValue("InstanceState.Name", data); err == nil {
+			instance["instance_state"] = state
+		}
+		if niId, err := getSdkValue("NetworkInterfaceSet.0.NetworkInterfaceId", data); err == nil {
+			instance["network_interface_id"] = niId
+		}
+		existIds = append(existIds, id)
+		instances = append(instances, instance)
+	}
+	if len(existIds) == 0 {
+		d.SetId("")
+		return err
+	}
+	// the instances terminated outside are launched again on the next apply, see kecInstanceGroupCustomizeDiff
+	_ = d.Set("instance_ids", existIds)
+	return d.Set("instances", instances)
+}
+
+// runKecInstanceGroupBatch launches count instances by one RunInstances call, members are the instances already
+// in the group.
+func (s *KecService) runKecInstanceGroupBatch(d *schema.ResourceData, r *schema.Resource, members map[string]map[string]interface{}, count int) (ids []string, err error) {
+	transform := map[string]SdkReqTransform{
+		"key_id": {
+			Type: TransformWithN,
+		},
+		"system_disk": {
+			Type: TransformListUnique,
+		},
+		"security_group_id": {
+			Type: TransformWithN,
+		},
+		"data_disks": {
+			mappings: map[string]string{
+				"data_disks": "DataDisk",
+				"disk_size":  "Size",
+				"disk_type":  "Type",
+			}, Type: TransformListN,
+		},
+		"instance_count":     {Ignore: true},
+		"min_instance_count": {Ignore: true},
+		"instance_name":      {Ignore: true},
+		"host_name":          {Ignore: true},
+		"instance_ids":       {Ignore: true},
+		"instances":          {Ignore: true},
+		"tags":               {Ignore: true},
+	}
+	createReq, err := SdkRequestAutoMapping(d, r, false, transform, nil, SdkReqParameter{
+		onlyTransform: false,
+	})
+	if err != nil {
+		return ids, err
+	}
+	createReq["SyncTag"] = d.Get("sync_tag")
+	createReq["AutoCreateEbs"] = d.Get("auto_create_ebs")
+	addKecInstanceTagParams(d, s.client, createReq)
+	var instanceNames, hostNames []string
+	for _, data := range members {
+		if name, ok := data["InstanceName"].(string); ok {
+			instanceNames = append(instanceNames, name)
+		}
+		if name, ok := data["HostName"].(string); ok {
+			hostNames = append(hostNames, name)
+		}
+	}
+	if v, ok := d.GetOk("instance_name"); ok {
+		createReq["InstanceName"] = kecBatchName(v.(string), kecBatchOffset(v.(string), instanceNames))
+	}
+	if v, ok := d.GetOk("host_name"); ok {
+		createReq["HostName"] = kecBatchName(v.(string), kecBatchOffset(v.(string), hostNames))
+	}
+
+	// min_instance_count is the minimum of the whole group
+	minCount := count
+	if v, ok := d.GetOk("min_instance_count"); ok {
+		minCount = v.(int) - len(members)
+		if minCount < 1 {
+			minCount = 1
+		}
+		if minCount > count {
+			minCount = count
+		}
+	}
+	createReq["MaxCount"] = strconv.Itoa(count)
+	createReq["MinCount"] = strconv.Itoa(minCount)
+
+	callback := ApiCall{
+		param:  &createReq,
+		action: "RunInstances",
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			conn := client.kecconn
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			resp, err = conn.RunInstances(call.param)
+			return resp, err
+		},
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			results, err := getSdkValue("InstancesSet", *resp)
+			if err != nil {
+				return err
+			}
+			for _, v := range results.([]interface{}) {
+				if id, ok := v.(map[string]interface{})["InstanceId"].(string); ok {
+					ids = append(ids, id)
+				}
+			}
+			return err
+		},
+	}
+	err = ksyunApiCallNew([]ApiCall{callback}, d, s.client, true)
+	return ids, err
+}
+
+// readKecInstanceGroupMembers reads the instances of the ids in all projects, the terminated instances are absent.
+func (s *KecService) readKecInstanceGroupMembers(d *schema.ResourceData, ids []string) (members map[string]map[string]interface{}, err error) {
+	members = make(map[string]map[string]interface{})
+	if len(ids) == 0 {
+		return members, err
+	}
+	req := make(map[string]interface{})
+	for i, id := range ids {
+		req["InstanceId."+strconv.Itoa(i+1)] = id
+	}
+	if err = addProjectInfoAll(d, &req, s.client); err != nil {
+		return members, err
+	}
+	results, err := s.readKecInstances(req)
+	if err != nil {
+		return members, err
+	}
+	for _, v := range results {
+		data := v.(map[string]interface{})
+		if id, ok := data["InstanceId"].(string); ok {
+			members[id] = data
+		}
+	}
+	return members, err
+}
+
+func (s *KecService) checkKecInstanceGroupState(d *schema.ResourceData, ids []string, target string, timeout time.Duration) (err error) {
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"pending"},
+		Target:  []string{target},
+		Refresh: func() (interface{}, string, error) {
+			members, err := s.readKecInstanceGroupMembers(d, ids)
+			if err != nil {
+				return nil, "", err
+			}
+			if len(members) < len(ids) {
+				return members, "pending", nil
+			}
+			for id, data := range members {
+				status, err := getSdkValue("InstanceState.Name", data)
+				if err != nil {
+					return nil, "", err
+				}
+				if status == "error" {
+					return nil, "", fmt.Errorf("instance %s status error", id)
+				}
+				if status != target {
+					return members, "pending", nil
+				}
+			}
+			return members, target, nil
+		},
+		Timeout:      timeout,
+		PollInterval: 5 * time.Second,
+		Delay:        10 * time.Second,
+		MinTimeout:   1 * time.Second,
+	}
+	_, err = stateConf.WaitForState()
+	return err
+}
+
+// terminateKecInstances terminates the existing instances of the ids and waits them to be absent
+func (s *KecService) terminateKecInstances(d *schema.ResourceData, ids []string, timeout time.Duration) (err error) {
+	members, err := s.readKecInstanceGroupMembers(d, ids)
+	if err != nil || len(members) == 0 {
+		return err
+	}
+	req := map[string]interface{}{
+		"ForceDelete": true,
+	}
+	idx := 1
+	for _, id := range ids {
+		if _, ok := members[id]; ok {
+			req["InstanceId."+strconv.Itoa(idx)] = id
+			idx++
+		}
+	}
+	action := "TerminateInstances"
+	logger.Debug(logger.ReqFormat, action, req)
+	if _, err = s.client.kecconn.TerminateInstances(&req); err != nil {
+		return err
+	}
+	return resource.Retry(timeout, func() *resource.RetryError {
+		members, err := s.readKecInstanceGroupMembers(d, ids)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		if len(members) > 0 {
+			return resource.RetryableError(fmt.Errorf("waiting for %d instances to be terminated", len(members)))
+		}
+		return nil
+	})
+}
+
+func kecInstanceGroupIds(v interface{}) (ids []string) {
+	for _, v := range v.([]interface{}) {
+		ids = append(ids, v.(string))
+	}
+	return ids
+}
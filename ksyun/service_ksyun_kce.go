@@ -107,7 +107,7 @@ func formatKceInstancePara(nodeConfig map[string]interface{}) (para string) {
 		// tag这个忽略的设置有点问题，kec的terraform是单独调了tag接口，但实际上主机的接口是支持tag的
 		"instance_status", "force_delete", "force_reinstall_system",
 		"extension_network_interface",
-		"tags",
+		"tags", "tags_all",
 		"role",
 		"advanced_setting",
 	}
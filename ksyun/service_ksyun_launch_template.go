@@ -0,0 +1,305 @@
+package ksyun
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/terraform-providers/terraform-provider-ksyun/logger"
+)
+
+type LaunchTemplateService struct {
+	client *KsyunClient
+}
+
+// launchTemplateDataFields are the request fields of the instance configs kept by the launch template versions
+var launchTemplateDataFields = []string{
+	"ImageId",
+	"InstanceType",
+	"SubnetId",
+	"SecurityGroupId",
+	"KeyId",
+	"UserData",
+	"SystemDisk",
+	"DataDisk",
+}
+
+// launchTemplateRequest sends the launch template actions which are absent in the kec sdk
+func launchTemplateRequest(client *KsyunClient, action string, param *map[string]interface{}) (resp *map[string]interface{}, err error) {
+	op := &request.Operation{
+		Name:       action,
+		HTTPMethod: "GET",
+		HTTPPath:   "/",
+	}
+	resp = &map[string]interface{}{}
+	err = client.kecconn.NewRequest(op, param, resp).Send()
+	return resp, err
+}
+
+func (s *LaunchTemplateService) readLaunchTemplateSet(action string, setName string, condition map[string]interface{}) (data []interface{}, err error) {
+	logger.Debug(logger.ReqFormat, action, condition)
+	resp, err := launchTemplateRequest(s.client, action, &condition)
+	if err != nil {
+		return data, err
+	}
+	results, err := getSdkValue(setName, *resp)
+	if err != nil {
+		return data, nil
+	}
+	data, _ = results.([]interface{})
+	return data, nil
+}
+
+func (s *LaunchTemplateService) ReadLaunchTemplates(condition map[string]interface{}) (data []interface{}, err error) {
+	return s.readLaunchTemplateSet("DescribeLaunchTemplates", "LaunchTemplateSet", condition)
+}
+
+func (s *LaunchTemplateService) ReadLaunchTemplate(d *schema.ResourceData, templateId string) (data map[string]interface{}, err error) {
+	var results []interface{}
+	if templateId == "" {
+		templateId = d.Id()
+	}
+	req := map[string]interface{}{
+		"LaunchTemplateId.1": templateId,
+	}
+	results, err = s.ReadLaunchTemplates(req)
+	if err != nil {
+		return data, err
+	}
+	for _, v := range results {
+		data = v.(map[string]interface{})
+	}
+	if len(data) == 0 {
+		return data, fmt.Errorf("LaunchTemplate %s not exist ", templateId)
+	}
+	return data, err
+}
+
+// ReadLaunchTemplateVersion reads the version of the launch template, the latest version is read when version is 0
+func (s *LaunchTemplateService) ReadLaunchTemplateVersion(templateId string, version int) (data map[string]interface{}, err error) {
+	req := map[string]interface{}{
+		"LaunchTemplateId": templateId,
+	}
+	if version > 0 {
+		req["VersionNumber.1"] = version
+	}
+	results, err := s.readLaunchTemplateSet("DescribeLaunchTemplateVersions", "LaunchTemplateVersionSet", req)
+	if err != nil {
+		return data, err
+	}
+	latest := 0
+	for _, v := range results {
+		item := v.(map[string]interface{})
+		number, _ := strconv.Atoi(fmt.Sprintf("%v", item["VersionNumber"]))
+		if (version == 0 && number > latest) || (version > 0 && number == version) {
+			data, latest = item, number
+		}
+	}
+	if len(data) == 0 {
+		return data, fmt.Errorf("the version %d of LaunchTemplate %s not exist ", version, templateId)
+	}
+	return data, err
+}
+
+func (s *LaunchTemplateService) ReadAndSetLaunchTemplate(d *schema.ResourceData, r *schema.Resource) (err error) {
+	template, err := s.ReadLaunchTemplate(d, "")
+	if err != nil {
+		return err
+	}
+	version, err := s.ReadLaunchTemplateVersion(d.Id(), 0)
+	if err != nil {
+		return err
+	}
+	data := map[string]interface{}{}
+	for _, k := range launchTemplateDataFields {
+		if v, ok := version[k]; ok {
+			data[k] = v
+		}
+	}
+	data["LaunchTemplateName"] = template["LaunchTemplateName"]
+	data["LatestVersionNumber"] = version["VersionNumber"]
+	extra := map[string]SdkResponseMapping{
+		"DataDisk": {
+			Field: "data_disks",
+		},
+		"Type": {
+			Field: "disk_type",
+		},
+		"Size": {
+			Field: "disk_size",
+		},
+	}
+	SdkResponseAutoResourceData(d, r, data, extra)
+	return err
+}
+
+// launchTemplateDataParams returns the request parameters of the instance configs in the launch template
+func launchTemplateDataParams(d *schema.ResourceData, r *schema.Resource) (map[string]interface{}, error) {
+	transform := map[string]SdkReqTransform{
+		"key_id": {
+			Type: TransformWithN,
+		},
+		"system_disk": {
+			Type: TransformListUnique,
+		},
+		"security_group_id": {
+			Type: TransformWithN,
+		},
+		"data_disks": {
+			mappings: map[string]string{
+				"data_disks": "DataDisk",
+				"disk_size":  "Size",
+				"disk_type":  "Type",
+			}, Type: TransformListN,
+		},
+		"launch_template_name":  {Ignore: true},
+		"latest_version_number": {Ignore: true},
+	}
+	return SdkRequestAutoMapping(d, r, false, transform, nil, SdkReqParameter{
+		onlyTransform: false,
+	})
+}
+
+func (s *LaunchTemplateService) CreateLaunchTemplateCall(d *schema.ResourceData, r *schema.Resource) (callback ApiCall, err error) {
+	req, err := launchTemplateDataParams(d, r)
+	if err != nil {
+		return callback, err
+	}
+	req["LaunchTemplateName"] = d.Get("launch_template_name")
+	callback = ApiCall{
+		param:  &req,
+		action: "CreateLaunchTemplate",
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			return launchTemplateRequest(client, call.action, call.param)
+		},
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			id, err := getSdkValue("LaunchTemplateId", *resp)
+			if err != nil {
+				return err
+			}
+			d.SetId(id.(string))
+			return err
+		},
+	}
+	return callback, err
+}
+
+func (s *LaunchTemplateService) CreateLaunchTemplate(d *schema.ResourceData, r *schema.Resource) (err error) {
+	call, err := s.CreateLaunchTemplateCall(d, r)
+	if err != nil {
+		return err
+	}
+	return ksyunApiCallNew([]ApiCall{call}, d, s.client, true)
+}
+
+// CreateLaunchTemplateVersionCall creates a new version of the template with the whole instance configs,
+// the former versions are kept for the instances launched by them.
+func (s *LaunchTemplateService) CreateLaunchTemplateVersionCall(d *schema.ResourceData, r *schema.Resource) (callback ApiCall, err error) {
+	req, err := launchTemplateDataParams(d, r)
+	if err != nil {
+		return callback, err
+	}
+	req["LaunchTemplateId"] = d.Id()
+	callback = ApiCall{
+		param:  &req,
+		action: "CreateLaunchTemplateVersion",
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			return launchTemplateRequest(client, call.action, call.param)
+		},
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			return err
+		},
+	}
+	return callback, err
+}
+
+func (s *LaunchTemplateService) ModifyLaunchTemplate(d *schema.ResourceData, r *schema.Resource) (err error) {
+	call, err := s.CreateLaunchTemplateVersionCall(d, r)
+	if err != nil {
+		return err
+	}
+	return ksyunApiCallNew([]ApiCall{call}, d, s.client, true)
+}
+
+func (s *LaunchTemplateService) RemoveLaunchTemplateCall(d *schema.ResourceData) (callback ApiCall, err error) {
+	removeReq := map[string]interface{}{
+		"LaunchTemplateId": d.Id(),
+	}
+	callback = ApiCall{
+		param:  &removeReq,
+		action: "DeleteLaunchTemplate",
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			return launchTemplateRequest(client, call.action, call.param)
+		},
+		callError: func(d *schema.ResourceData, client *KsyunClient, call ApiCall, baseErr error) error {
+			if notFoundError(baseErr) {
+				return nil
+			}
+			return baseErr
+		},
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			return err
+		},
+	}
+	return callback, err
+}
+
+func (s *LaunchTemplateService) RemoveLaunchTemplate(d *schema.ResourceData) (err error) {
+	call, err := s.RemoveLaunchTemplateCall(d)
+	if err != nil {
+		return err
+	}
+	return ksyunApiCallNew([]ApiCall{call}, d, s.client, true)
+}
+
+// addLaunchTemplateParams merges the instance configs of the launch template version into the request of
+// RunInstances, the configs set on the instance take precedence over the ones of the template.
+func (s *LaunchTemplateService) addLaunchTemplateParams(templateId string, version int, params map[string]interface{}) (err error) {
+	data, err := s.ReadLaunchTemplateVersion(templateId, version)
+	if err != nil {
+		return fmt.Errorf("error on reading the launch template %s, %s", templateId, err)
+	}
+	for _, field := range launchTemplateDataFields {
+		v, ok := data[field]
+		if !ok || hasParamOf(params, field) {
+			continue
+		}
+		flattenLaunchTemplateParam(field, v, params)
+	}
+	return err
+}
+
+// hasParamOf checks whether the request has the parameter field or any of its nested parameters, such as DataDisk.1.Size
+func hasParamOf(params map[string]interface{}, field string) bool {
+	for k := range params {
+		if k == field || strings.HasPrefix(k, field+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// flattenLaunchTemplateParam flattens the nested value into the request parameters, {"DataDisk": [{"Size": 10}]}
+// is flattened as DataDisk.1.Size=10
+func flattenLaunchTemplateParam(key string, value interface{}, params map[string]interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			flattenLaunchTemplateParam(key+"."+k, e, params)
+		}
+	case []interface{}:
+		for i, e := range v {
+			flattenLaunchTemplateParam(key+"."+strconv.Itoa(i+1), e, params)
+		}
+	default:
+		params[key] = v
+	}
+}
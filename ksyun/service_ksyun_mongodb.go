@@ -612,7 +612,7 @@ func readMongodbInstanceCommon(d *schema.ResourceData, meta interface{}, r *sche
 	if _, ok := data["InstanceAccount"]; !ok {
 		err = d.Set("instance_account", "root")
 	}
-	if _, ok := d.GetOk("tags"); ok {
+	if hasTags(d, meta.(*KsyunClient)) {
 		err = mergeTagsData(d, &data, meta.(*KsyunClient), "mongodb-instance")
 		if err != nil {
 			return fmt.Errorf("reading tags error: %s", err)
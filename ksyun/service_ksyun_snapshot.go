@@ -3,9 +3,12 @@ package ksyun
 import (
 	"errors"
 	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-	"time"
+	"github.com/terraform-providers/terraform-provider-ksyun/logger"
 )
 
 type SnapshotService struct {
@@ -210,3 +213,129 @@ func (s *SnapshotService) ReadAndSetSnapshots(d *schema.ResourceData, r *schema.
 		targetField: "snapshots",
 	})
 }
+
+// CopySnapshotCall copies the source snapshot to the destination region. The api doesn't return the id of the copy,
+// so the copy is found by its name in the destination region, which must be unique.
+func (s *SnapshotService) CopySnapshotCall(d *schema.ResourceData) (callback ApiCall, err error) {
+	req := map[string]interface{}{
+		"SnapshotId.1":            d.Get("source_snapshot_id"),
+		"DestinationRegion.1":     d.Get("destination_region"),
+		"DestinationSnapshotName": d.Get("snapshot_name"),
+	}
+	if v, ok := d.GetOk("snapshot_desc"); ok {
+		req["DestinationSnapshotDesc"] = v
+	}
+	callback = ApiCall{
+		param:  &req,
+		action: "CopySnapshot",
+		beforeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (bool, error) {
+			copies, err := s.readSnapshotCopies(d)
+			if err != nil {
+				return false, err
+			}
+			if len(copies) > 0 {
+				return false, fmt.Errorf("the snapshot named %q already exists in region %s, the name of the copy must be unique",
+					d.Get("snapshot_name"), d.Get("destination_region"))
+			}
+			return true, nil
+		},
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			conn := client.kecconn
+			// CopySnapshot is absent in the kec sdk
+			op := &request.Operation{
+				Name:       call.action,
+				HTTPMethod: "GET",
+				HTTPPath:   "/",
+			}
+			resp = &map[string]interface{}{}
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			err = conn.NewRequest(op, call.param, resp).Send()
+			return resp, err
+		},
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			return s.checkSnapshotCopyState(d, d.Timeout(schema.TimeoutCreate))
+		},
+		disableDryRun: true,
+	}
+	return callback, err
+}
+
+func (s *SnapshotService) CopySnapshot(d *schema.ResourceData) (err error) {
+	call, err := s.CopySnapshotCall(d)
+	if err != nil {
+		return err
+	}
+	return ksyunApiCallNew([]ApiCall{call}, d, s.client, true)
+}
+
+// readSnapshotCopies reads the snapshots named as the copy in the destination region
+func (s *SnapshotService) readSnapshotCopies(d *schema.ResourceData) (data []interface{}, err error) {
+	_, err = s.client.WithRegionClient(d.Get("destination_region").(string), func(client *KsyunClient) (interface{}, error) {
+		destService := SnapshotService{client}
+		data, err = destService.readSnapshots(map[string]interface{}{
+			"SnapshotName": d.Get("snapshot_name"),
+		})
+		return data, err
+	})
+	return data, err
+}
+
+// checkSnapshotCopyState waits the copy to appear in the destination region and to be available, the id of
+// resource is set once the copy is found.
+func (s *SnapshotService) checkSnapshotCopyState(d *schema.ResourceData, timeout time.Duration) (err error) {
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"copying"},
+		Target:  []string{"available"},
+		Refresh: func() (interface{}, string, error) {
+			copies, err := s.readSnapshotCopies(d)
+			if err != nil {
+				return nil, "", err
+			}
+			if len(copies) == 0 {
+				return copies, "copying", nil
+			}
+			if len(copies) > 1 {
+				return nil, "", fmt.Errorf("more than one snapshot named %q in region %s", d.Get("snapshot_name"), d.Get("destination_region"))
+			}
+			data := copies[0].(map[string]interface{})
+			if id, ok := data["SnapshotId"].(string); ok && d.Id() == "" {
+				d.SetId(id)
+			}
+			switch status := data["SnapshotStatus"]; status {
+			case "available":
+				return data, "available", nil
+			case "error":
+				return nil, "", fmt.Errorf("snapshot copy status error, progress: %v", data["Progress"])
+			default:
+				return data, "copying", nil
+			}
+		},
+		Timeout:    timeout,
+		Delay:      10 * time.Second,
+		MinTimeout: 10 * time.Second,
+	}
+	_, err = stateConf.WaitForState()
+	return err
+}
+
+func (s *SnapshotService) ReadAndSetSnapshotCopy(d *schema.ResourceData, r *schema.Resource) (err error) {
+	_, err = s.client.WithRegionClient(d.Get("destination_region").(string), func(client *KsyunClient) (interface{}, error) {
+		destService := SnapshotService{client}
+		data, err := destService.ReadSnapshot(d, "")
+		if err != nil {
+			return nil, err
+		}
+		SdkResponseAutoResourceData(d, r, data, nil)
+		return data, err
+	})
+	return err
+}
+
+func (s *SnapshotService) RemoveSnapshotCopy(d *schema.ResourceData) (err error) {
+	_, err = s.client.WithRegionClient(d.Get("destination_region").(string), func(client *KsyunClient) (interface{}, error) {
+		destService := SnapshotService{client}
+		return nil, destService.Remove(d)
+	})
+	return err
+}
@@ -213,6 +213,29 @@ func (s *TagService) ReadTagByResourceId(d *schema.ResourceData, resourceId stri
 	return data, err
 }
 
+// ReadIgnoredTags returns the tags of resource which are ignored by provider
+func (s *TagService) ReadIgnoredTags(resourceId string, resourceType string) (tags map[string]interface{}, err error) {
+	tags = make(map[string]interface{})
+	if !s.client.hasIgnoreTags() || resourceId == "" {
+		return tags, err
+	}
+	results, err := s.ReadTagByResourceId(nil, resourceId, resourceType)
+	if err != nil {
+		return tags, err
+	}
+	for _, result := range results {
+		r, ok := result.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		k, _ := r["TagKey"].(string)
+		if s.client.isIgnoredTag(k) {
+			tags[k] = r["TagValue"]
+		}
+	}
+	return tags, err
+}
+
 func (s *TagService) CreateTagCommonCall(req map[string]interface{}, isSetId bool) (callback ApiCall, err error) {
 	callback = ApiCall{
 		param:  &req,
@@ -562,25 +585,32 @@ func (s *TagService) ReplaceResourcesTagsCall(d *schema.ResourceData, r *schema.
 }
 
 func (s *TagService) ReplaceResourcesTagsWithResourceCall(d *schema.ResourceData, r *schema.Resource, resourceType string, isUpdate bool, disableDryRun bool) (callback ApiCall, err error) {
-	transform := map[string]SdkReqTransform{
-		"tags": {
-			FieldReqFunc: func(i interface{}, s string, m map[string]string, i2 int, s2 string, m2 *map[string]interface{}) (int, error) {
-				if tagMap, ok := i.(map[string]interface{}); ok {
-					for k, v := range tagMap {
-						(*m2)["Tag_"+strconv.Itoa(i2)+"_Key"] = k
-						(*m2)["Tag_"+strconv.Itoa(i2)+"_Value"] = v
-						i2++
-					}
-				}
-				return 0, nil
-			},
-		},
-	}
-	req, err := SdkRequestAutoMapping(d, r, isUpdate, transform, nil)
-	if err != nil {
+	if isUpdate && !hasTagsChange(d) {
 		return callback, err
 	}
-	if len(req) > 0 || d.HasChange("tags") {
+	// the default tags of provider are replaced together with the tags of resource
+	req := make(map[string]interface{})
+	tags, _ := d.Get("tags").(map[string]interface{})
+	tags = s.client.mergeDefaultTags(tags)
+	if d.Id() != "" {
+		// keep the ignored tags of the existing resource, which are managed outside
+		ignored, err := s.ReadIgnoredTags(d.Id(), resourceType)
+		if err != nil {
+			return callback, fmt.Errorf("error on reading ignored tags of %s %q, %s", resourceType, d.Id(), err)
+		}
+		for k, v := range ignored {
+			if _, ok := tags[k]; !ok {
+				tags[k] = v
+			}
+		}
+	}
+	idx := 1
+	for k, v := range tags {
+		req["Tag_"+strconv.Itoa(idx)+"_Key"] = k
+		req["Tag_"+strconv.Itoa(idx)+"_Value"] = v
+		idx++
+	}
+	if len(req) > 0 || hasTagsChange(d) {
 		req["ResourceType"] = resourceType
 		return s.ReplaceResourcesTagsCommonCall(req, disableDryRun)
 	}
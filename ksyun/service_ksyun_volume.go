@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go/aws/request"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/terraform-providers/terraform-provider-ksyun/logger"
@@ -326,6 +327,60 @@ func (s *EbsService) ModifyVolumeResizeCall(d *schema.ResourceData, r *schema.Re
 	return callback, err
 }
 
+// ResizeAttachedVolumeCall expands the volume attached to an instance online, the calls of ksyun_instance use it
+// to resize the inline data disks.
+func (s *EbsService) ResizeAttachedVolumeCall(volumeId string, size int) (callback ApiCall) {
+	return ApiCall{
+		param: &map[string]interface{}{
+			"VolumeId":     volumeId,
+			"Size":         size,
+			"OnlineResize": true,
+		},
+		action: "ResizeVolume",
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			conn := client.ebsconn
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			resp, err = conn.ResizeVolume(call.param)
+			return resp, err
+		},
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			_, err = s.checkVolumeState(d, volumeId, []string{"available", "in-use"}, d.Timeout(schema.TimeoutUpdate))
+			return err
+		},
+	}
+}
+
+// ModifyVolumeTypeCall changes the type of volume, such as upgrading ESSD_PL0 to ESSD_PL1.
+func (s *EbsService) ModifyVolumeTypeCall(volumeId string, volumeType string) (callback ApiCall) {
+	return ApiCall{
+		param: &map[string]interface{}{
+			"VolumeId":                       volumeId,
+			"PerformanceLevelVolumeCategory": volumeType,
+		},
+		action: "ModifyVolumeType",
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			conn := client.ebsconn
+			// ModifyVolumeType is absent in the ebs sdk
+			op := &request.Operation{
+				Name:       call.action,
+				HTTPMethod: "GET",
+				HTTPPath:   "/",
+			}
+			resp = &map[string]interface{}{}
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			err = conn.NewRequest(op, call.param, resp).Send()
+			return resp, err
+		},
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			_, err = s.checkVolumeState(d, volumeId, []string{"available", "in-use"}, d.Timeout(schema.TimeoutUpdate))
+			return err
+		},
+		disableDryRun: true,
+	}
+}
+
 func (s *EbsService) ModifyVolume(d *schema.ResourceData, r *schema.Resource) (err error) {
 
 	// a := d.HasChange("project_id")
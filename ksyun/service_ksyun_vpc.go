@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"log"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -559,6 +561,9 @@ func (s *VpcService) ReadAndSetSubnet(d *schema.ResourceData, r *schema.Resource
 		"AvailableIpNumber": {Field: "available_ip_number"},
 	}
 	SdkResponseAutoResourceData(d, r, data, extra)
+	if _, _, maskLen, err := getCidrSpan(d.Get("cidr_block").(string)); err == nil {
+		return d.Set("cidr_mask_length", maskLen)
+	}
 	return err
 }
 
@@ -633,11 +638,87 @@ func (s *VpcService) SubnetAutoMatch(req *map[string]interface{}) {
 	}
 }
 
+// ReadVpcCidrUsage returns the cidr block of the vpc and the cidr blocks of its subnets
+func (s *VpcService) ReadVpcCidrUsage(vpcId string) (vpcCidr string, used []string, err error) {
+	vpc, err := s.ReadVpc(nil, vpcId)
+	if err != nil {
+		return vpcCidr, used, err
+	}
+	vpcCidr = vpc["CidrBlock"].(string)
+	subnets, err := s.ReadSubnets(map[string]interface{}{
+		"Filter.1.Name":    "vpc-id",
+		"Filter.1.Value.1": vpcId,
+	})
+	if err != nil {
+		return vpcCidr, used, err
+	}
+	for _, subnet := range subnets {
+		used = append(used, subnet.(map[string]interface{})["CidrBlock"].(string))
+	}
+	return vpcCidr, used, err
+}
+
+func (s *VpcService) ReadAndSetVpcCidrPlan(d *schema.ResourceData, r *schema.Resource) (err error) {
+	vpcCidr, used, err := s.ReadVpcCidrUsage(d.Get("vpc_id").(string))
+	if err != nil {
+		return err
+	}
+	for _, cidr := range d.Get("reserved_cidr_blocks").([]interface{}) {
+		used = append(used, cidr.(string))
+	}
+	var (
+		plans []interface{}
+		all   []string
+	)
+	allocated := append([]string{}, used...)
+	for i, v := range d.Get("requests").([]interface{}) {
+		request := v.(map[string]interface{})
+		blocks, err := allocateCidrBlocks(vpcCidr, allocated, request["cidr_mask_length"].(int), request["count"].(int))
+		if err != nil {
+			return fmt.Errorf("error on planning requests.%d, %s", i, err)
+		}
+		allocated = append(allocated, blocks...)
+		all = append(all, blocks...)
+		plans = append(plans, map[string]interface{}{
+			"availability_zone": request["availability_zone"],
+			"cidr_mask_length":  request["cidr_mask_length"],
+			"cidr_blocks":       blocks,
+		})
+	}
+	d.SetId(dataResourceIdHash(append([]string{d.Get("vpc_id").(string)}, all...)))
+	_ = d.Set("vpc_cidr_block", vpcCidr)
+	_ = d.Set("used_cidr_blocks", used)
+	if err = d.Set("plans", plans); err != nil {
+		return err
+	}
+	if outputFile, ok := d.GetOk("output_file"); ok && outputFile.(string) != "" {
+		return writeToFile(outputFile.(string), plans)
+	}
+	return err
+}
+
 func (s *VpcService) CreateSubnetCall(d *schema.ResourceData, r *schema.Resource) (callback ApiCall, err error) {
-	req, err := SdkRequestAutoMapping(d, r, false, nil, nil)
+	transform := map[string]SdkReqTransform{
+		"cidr_mask_length": {Ignore: true},
+	}
+	req, err := SdkRequestAutoMapping(d, r, false, transform, nil, SdkReqParameter{
+		onlyTransform: false,
+	})
 	if err != nil {
 		return callback, err
 	}
+	// allocate the lowest free block of cidr_mask_length in the vpc
+	if maskLen, ok := d.GetOk("cidr_mask_length"); ok && req["CidrBlock"] == nil {
+		vpcCidr, used, err := s.ReadVpcCidrUsage(d.Get("vpc_id").(string))
+		if err != nil {
+			return callback, err
+		}
+		blocks, err := allocateCidrBlocks(vpcCidr, used, maskLen.(int), 1)
+		if err != nil {
+			return callback, err
+		}
+		req["CidrBlock"] = blocks[0]
+	}
 	s.SubnetAutoMatch(&req)
 	if req["SubnetType"] != "Reserve" {
 		if _, ok := req["GatewayIp"]; !ok {
@@ -676,6 +757,11 @@ func (s *VpcService) CreateSubnetCall(d *schema.ResourceData, r *schema.Resource
 }
 
 func (s *VpcService) CreateSubnet(d *schema.ResourceData, r *schema.Resource) (err error) {
+	// the subnets allocated by cidr_mask_length are created one by one, so that they don't take the same block
+	if _, ok := d.GetOk("cidr_block"); !ok {
+		subnetCidrMutex.Lock()
+		defer subnetCidrMutex.Unlock()
+	}
 	call, err := s.CreateSubnetCall(d, r)
 	if err != nil {
 		return err
@@ -1007,7 +1093,7 @@ func (s *VpcService) ReadNat(d *schema.ResourceData, natId string) (data map[str
 	if err != nil {
 		return data, err
 	}
-	if _, ok := d.GetOk("tags"); ok {
+	if hasTags(d, s.client) {
 		req["IsContainTag"] = true
 	}
 	results, err = s.ReadNats(req)
@@ -2396,6 +2482,219 @@ func (s *VpcService) RemoveNetworkAclAssociate(d *schema.ResourceData) (err erro
 	return ksyunApiCallNew([]ApiCall{call}, d, s.client, true)
 }
 
+// networkAclRuleFromEntry converts a remote network acl entry to a rule of ksyun_network_acl_rules
+func networkAclRuleFromEntry(entry map[string]interface{}) map[string]interface{} {
+	number := int(entry["RuleNumber"].(float64))
+	rule := map[string]interface{}{
+		"rule_number":          number,
+		"assigned_rule_number": number,
+		"network_acl_entry_id": entry["NetworkAclEntryId"],
+		"rule_action":          entry["RuleAction"],
+		"protocol":             entry["Protocol"],
+		"cidr_block":           entry["CidrBlock"],
+		"description":          "",
+		"icmp_type":            0,
+		"icmp_code":            0,
+		"port_range_from":      0,
+		"port_range_to":        0,
+	}
+	if description, ok := entry["Description"].(string); ok {
+		rule["description"] = description
+	}
+	for _, k := range generateEntryField(entry["Protocol"].(string)) {
+		if v, ok := entry[Downline2Hump(k)].(float64); ok {
+			rule[k] = int(v)
+		}
+	}
+	return rule
+}
+
+// readNetworkAclRuleEntries returns the remote entries managed by ksyun_network_acl_rules in the order of rule number,
+// the entries beyond the valid rule number are built in and left alone.
+func (s *VpcService) readNetworkAclRuleEntries(d *schema.ResourceData, networkAclId string) (entries []map[string]interface{}, err error) {
+	data, err := s.ReadNetworkAcl(d, networkAclId)
+	if err != nil {
+		return entries, err
+	}
+	for _, v := range data["NetworkAclEntrySet"].([]interface{}) {
+		entry := v.(map[string]interface{})
+		if int(entry["RuleNumber"].(float64)) <= networkAclRuleNumberMax {
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i]["RuleNumber"].(float64) < entries[j]["RuleNumber"].(float64)
+	})
+	return entries, err
+}
+
+func (s *VpcService) ReadAndSetNetworkAclRules(d *schema.ResourceData, r *schema.Resource) (err error) {
+	entries, err := s.readNetworkAclRuleEntries(d, d.Id())
+	if err != nil {
+		return err
+	}
+	rules := map[string][]interface{}{
+		"ingress": {},
+		"egress":  {},
+	}
+	for field := range rules {
+		// keep the rule_number of the auto assigned rules unset
+		auto := make(map[int]bool)
+		for _, v := range d.Get(field).([]interface{}) {
+			if rule := v.(map[string]interface{}); rule["rule_number"].(int) == 0 {
+				auto[rule["assigned_rule_number"].(int)] = true
+			}
+		}
+		direction := networkAclRuleDirection(field)
+		for _, entry := range entries {
+			if entry["Direction"] != direction {
+				continue
+			}
+			rule := networkAclRuleFromEntry(entry)
+			if auto[rule["assigned_rule_number"].(int)] {
+				rule["rule_number"] = 0
+			}
+			rules[field] = append(rules[field], rule)
+		}
+		err = d.Set(field, rules[field])
+		if err != nil {
+			return err
+		}
+	}
+	return d.Set("network_acl_id", d.Id())
+}
+
+// NetworkAclRulesCalls returns the calls which make the entries of the network acl exactly the ingress and egress
+// rules. The entries matching a rule by number and traffic are kept and only their descriptions are modified, the
+// others are deleted before the missing rules are created, since the rule numbers are unique per direction.
+func (s *VpcService) NetworkAclRulesCalls(d *schema.ResourceData) (callbacks []ApiCall, err error) {
+	var removes, creates, modifies []ApiCall
+	aclId := d.Get("network_acl_id").(string)
+	entries, err := s.readNetworkAclRuleEntries(d, aclId)
+	if err != nil {
+		return callbacks, err
+	}
+	existing := make(map[string][]int)
+	for _, entry := range entries {
+		rule := networkAclRuleFromEntry(entry)
+		key := networkAclRuleKey(entry["Direction"].(string), rule)
+		existing[key] = append(existing[key], rule["assigned_rule_number"].(int))
+	}
+
+	desired := make(map[string]map[string]interface{})
+	var keys []string
+	for _, field := range []string{"ingress", "egress"} {
+		var numbers []int
+		rules := d.Get(field).([]interface{})
+		numbers, err = assignNetworkAclRuleNumbers(field, rules, existing)
+		if err != nil {
+			return callbacks, err
+		}
+		direction := networkAclRuleDirection(field)
+		for i, v := range rules {
+			key := direction + ":" + strconv.Itoa(numbers[i])
+			rule := v.(map[string]interface{})
+			rule["assigned_rule_number"] = numbers[i]
+			desired[key] = rule
+			keys = append(keys, key)
+		}
+		// the assigned numbers tell the auto assigned rules apart on reading
+		err = d.Set(field, rules)
+		if err != nil {
+			return callbacks, err
+		}
+	}
+
+	matched := make(map[string]bool)
+	for _, entry := range entries {
+		var callback ApiCall
+		rule := networkAclRuleFromEntry(entry)
+		direction := entry["Direction"].(string)
+		key := direction + ":" + strconv.Itoa(rule["assigned_rule_number"].(int))
+		if want, ok := desired[key]; ok && networkAclRuleKey(direction, want) == networkAclRuleKey(direction, rule) {
+			matched[key] = true
+			if want["description"] != rule["description"] {
+				callback, err = s.ModifyNetworkAclEntryCommonCall(map[string]interface{}{
+					"NetworkAclEntryId": entry["NetworkAclEntryId"],
+					"Description":       want["description"],
+				})
+				if err != nil {
+					return callbacks, err
+				}
+				modifies = append(modifies, callback)
+			}
+			continue
+		}
+		callback, err = s.RemoveNetworkAclEntryCommonCall(aclId, entry["NetworkAclEntryId"].(string))
+		if err != nil {
+			return callbacks, err
+		}
+		removes = append(removes, callback)
+	}
+
+	for _, key := range keys {
+		var callback ApiCall
+		if matched[key] {
+			continue
+		}
+		rule := desired[key]
+		req := map[string]interface{}{
+			"Direction":  strings.Split(key, ":")[0],
+			"RuleNumber": rule["assigned_rule_number"],
+			"RuleAction": rule["rule_action"],
+			"Protocol":   rule["protocol"],
+			"CidrBlock":  rule["cidr_block"],
+		}
+		if description := rule["description"].(string); description != "" {
+			req["Description"] = description
+		}
+		for _, k := range generateEntryField(rule["protocol"].(string)) {
+			req[Downline2Hump(k)] = rule[k]
+		}
+		callback, err = s.CreateNetworkAclEntryCommonCall(req, false)
+		if err != nil {
+			return callbacks, err
+		}
+		creates = append(creates, callback)
+	}
+
+	callbacks = append(callbacks, removes...)
+	callbacks = append(callbacks, creates...)
+	callbacks = append(callbacks, modifies...)
+	return callbacks, err
+}
+
+func (s *VpcService) ApplyNetworkAclRules(d *schema.ResourceData) (err error) {
+	apiProcess := NewApiProcess(context.Background(), d, s.client, true)
+	calls, err := s.NetworkAclRulesCalls(d)
+	if err != nil {
+		return err
+	}
+	apiProcess.PutCalls(calls...)
+	return apiProcess.Run()
+}
+
+// RemoveNetworkAclRules deletes all the entries managed by ksyun_network_acl_rules
+func (s *VpcService) RemoveNetworkAclRules(d *schema.ResourceData) (err error) {
+	aclId := d.Get("network_acl_id").(string)
+	entries, err := s.readNetworkAclRuleEntries(d, aclId)
+	if err != nil {
+		if notFoundError(err) {
+			return nil
+		}
+		return err
+	}
+	apiProcess := NewApiProcess(context.Background(), d, s.client, true)
+	for _, entry := range entries {
+		call, err := s.RemoveNetworkAclEntryCommonCall(aclId, entry["NetworkAclEntryId"].(string))
+		if err != nil {
+			return err
+		}
+		apiProcess.PutCalls(call)
+	}
+	return apiProcess.Run()
+}
+
 func (s *VpcService) ReadSecurityGroups(condition map[string]interface{}) (data []interface{}, err error) {
 	var (
 		resp    *map[string]interface{}
@@ -2735,13 +3034,113 @@ func (s *VpcService) CreateSecurityGroup(d *schema.ResourceData, r *schema.Resou
 	return ksyunApiCallNew(callbacks, d, s.client, false)
 }
 
-func (s *VpcService) CreateSecurityGroupEntrySet(d *schema.ResourceData, r *schema.Resource) (err error) {
+// securityGroupEntryQuotaErrors are the error codes of authorizing an entry beyond the quota of the security group
+var securityGroupEntryQuotaErrors = []string{
+	"QuotaExceeded",
+	"SecurityGroupEntryLimitExceeded",
+	"SecurityGroupEntryQuotaExceeded",
+}
+
+// SecurityGroupEntrySetCalls returns the calls which make the entries of the security group exactly the
+// security_group_entries. The entries are matched by protocol, direction, cidr block and ports, the unmanaged
+// and duplicated entries are revoked, the missing ones are authorized and the descriptions are modified in place.
+// The missing entries are authorized before revoking the stale ones, so that the traffic allowed by both is never
+// interrupted, unless revokeFirst is set to release the quota of entries at first.
+func (s *VpcService) SecurityGroupEntrySetCalls(d *schema.ResourceData, revokeFirst bool) (callbacks []ApiCall, err error) {
+	var revokes, authorizes, modifies []ApiCall
+	sgId := d.Get("security_group_id").(string)
+	sg, err := s.ReadSecurityGroup(d, sgId)
+	if err != nil {
+		return callbacks, err
+	}
+	desired := make(map[int]map[string]interface{})
+	entries := d.Get("security_group_entries").(*schema.Set).List()
+	for _, entry := range entries {
+		desired[securityGroupEntrySimpleHash(entry)] = entry.(map[string]interface{})
+	}
+	existed := make(map[int]bool)
+	for _, v := range sg["SecurityGroupEntrySet"].([]interface{}) {
+		var callback ApiCall
+		entry := v.(map[string]interface{})
+		entryId := entry["SecurityGroupEntryId"].(string)
+		index := securityGroupEntrySimpleHashWithHump(entry)
+		want, ok := desired[index]
+		if !ok || existed[index] {
+			callback, err = s.RemoveSecurityGroupEntryCommonCall(sgId, entryId)
+			if err != nil {
+				return callbacks, err
+			}
+			revokes = append(revokes, callback)
+			continue
+		}
+		existed[index] = true
+		if description, _ := entry["Description"].(string); description != want["description"].(string) {
+			callback, err = s.ModifySecurityGroupEntryCommonCall(map[string]interface{}{
+				"SecurityGroupId":      sgId,
+				"SecurityGroupEntryId": entryId,
+				"Description":          want["description"],
+			})
+			if err != nil {
+				return callbacks, err
+			}
+			modifies = append(modifies, callback)
+		}
+	}
+	for _, entry := range entries {
+		var callback ApiCall
+		if existed[securityGroupEntrySimpleHash(entry)] {
+			continue
+		}
+		callback, err = s.CreateSecurityGroupEntryCommonCall(securityGroupEntrySetReq(entry.(map[string]interface{})), false)
+		if err != nil {
+			return callbacks, err
+		}
+		authorizes = append(authorizes, callback)
+	}
+	if revokeFirst {
+		callbacks = append(callbacks, revokes...)
+		callbacks = append(callbacks, authorizes...)
+		callbacks = append(callbacks, modifies...)
+		return callbacks, err
+	}
+	callbacks = append(callbacks, authorizes...)
+	callbacks = append(callbacks, modifies...)
+	callbacks = append(callbacks, revokes...)
+	return callbacks, err
+}
+
+func securityGroupEntrySetReq(entry map[string]interface{}) map[string]interface{} {
+	req := make(map[string]interface{})
+	for _, k := range []string{"cidr_block", "direction", "protocol"} {
+		req[Downline2Hump(k)] = entry[k]
+	}
+	if description, ok := entry["description"].(string); ok && description != "" {
+		req["Description"] = description
+	}
+	for _, k := range generateEntryField(entry["protocol"].(string)) {
+		req[Downline2Hump(k)] = entry[k]
+	}
+	return req
+}
+
+func (s *VpcService) ApplySecurityGroupEntrySet(d *schema.ResourceData) (err error) {
+	err = s.applySecurityGroupEntrySet(d, false)
+	if err != nil && isExpectError(err, securityGroupEntryQuotaErrors) {
+		// the entries are compared with the remote again, the ones authorized before the error are kept
+		log.Printf("[WARN] the entries of security group %s exceed the quota, revoke the stale entries at first: %s",
+			d.Get("security_group_id"), err)
+		err = s.applySecurityGroupEntrySet(d, true)
+	}
+	return err
+}
+
+func (s *VpcService) applySecurityGroupEntrySet(d *schema.ResourceData, revokeFirst bool) (err error) {
 	apiProcess := NewApiProcess(context.Background(), d, s.client, true)
-	entries, err := s.CreateSecurityGroupEntryWithSgCall(d, r)
+	calls, err := s.SecurityGroupEntrySetCalls(d, revokeFirst)
 	if err != nil {
 		return err
 	}
-	apiProcess.PutCalls(entries...)
+	apiProcess.PutCalls(calls...)
 	return apiProcess.Run()
 }
 
@@ -2991,16 +3390,6 @@ func (s *VpcService) ModifySecurityGroup(d *schema.ResourceData, r *schema.Resou
 	return ksyunApiCallNew(callbacks, d, s.client, true)
 }
 
-func (s *VpcService) ModifySecurityGroupSet(d *schema.ResourceData, r *schema.Resource) (err error) {
-	apiProcess := NewApiProcess(context.Background(), d, s.client, true)
-	entries, err := s.ModifySecurityGroupEntryWithSgCall(d, r)
-	if err != nil {
-		return err
-	}
-	apiProcess.PutCalls(entries...)
-	return apiProcess.Run()
-}
-
 func (s *VpcService) ModifySecurityGroupEntry(d *schema.ResourceData, r *schema.Resource) (err error) {
 	var callbacks []ApiCall
 	call, err := s.ModifySecurityGroupEntryCall(d, r)
@@ -3121,6 +3510,27 @@ func (s *VpcService) RemoveSecurityGroup(d *schema.ResourceData) (err error) {
 	return ksyunApiCallNew([]ApiCall{call}, d, s.client, true)
 }
 
+// RemoveSecurityGroupEntrySet revokes all the entries of the security group
+func (s *VpcService) RemoveSecurityGroupEntrySet(d *schema.ResourceData) (err error) {
+	sgId := d.Get("security_group_id").(string)
+	sg, err := s.ReadSecurityGroup(d, sgId)
+	if err != nil {
+		if notFoundError(err) {
+			return nil
+		}
+		return err
+	}
+	apiProcess := NewApiProcess(context.Background(), d, s.client, true)
+	for _, entry := range sg["SecurityGroupEntrySet"].([]interface{}) {
+		call, err := s.RemoveSecurityGroupEntryCommonCall(sgId, entry.(map[string]interface{})["SecurityGroupEntryId"].(string))
+		if err != nil {
+			return err
+		}
+		apiProcess.PutCalls(call)
+	}
+	return apiProcess.Run()
+}
+
 func (s *VpcService) RemoveSecurityGroupEntry(d *schema.ResourceData) (err error) {
 	call, err := s.RemoveSecurityGroupEntryCommonCall(d.Get("security_group_id").(string), d.Get("security_group_entry_id").(string))
 	if err != nil {
@@ -3849,6 +4259,328 @@ func (s *VpcService) RemoveVpnTunnel(d *schema.ResourceData) (err error) {
 	return ksyunApiCallNew([]ApiCall{call}, d, s.client, true)
 }
 
+func (s *VpcService) ReadVpcPeeringConnections(condition map[string]interface{}) (data []interface{}, err error) {
+	var (
+		resp    *map[string]interface{}
+		results interface{}
+	)
+	conn := s.client.vpcconn
+	action := "DescribeVpcPeeringConnections"
+	logger.Debug(logger.ReqFormat, action, condition)
+	if condition == nil {
+		resp, err = conn.DescribeVpcPeeringConnections(nil)
+		if err != nil {
+			return data, err
+		}
+	} else {
+		resp, err = conn.DescribeVpcPeeringConnections(&condition)
+		if err != nil {
+			return data, err
+		}
+	}
+
+	results, err = getSdkValue("VpcPeeringConnectionSet", *resp)
+	if err != nil {
+		return data, err
+	}
+	data = results.([]interface{})
+	return data, err
+}
+
+func (s *VpcService) ReadVpcPeeringConnection(d *schema.ResourceData, peeringId string) (data map[string]interface{}, err error) {
+	var results []interface{}
+	if peeringId == "" {
+		peeringId = d.Id()
+	}
+	req := map[string]interface{}{
+		"VpcPeeringConnectionId.1": peeringId,
+	}
+	err = addProjectInfoAll(d, &req, s.client)
+	if err != nil {
+		return data, err
+	}
+	results, err = s.ReadVpcPeeringConnections(req)
+	if err != nil {
+		return data, err
+	}
+	for _, v := range results {
+		data = v.(map[string]interface{})
+	}
+	if len(data) == 0 {
+		return data, fmt.Errorf("VpcPeeringConnection %s not exist ", peeringId)
+	}
+	return data, err
+}
+
+// vpcPeeringConnectionExtra maps the accepter side of the connection to the peer arguments, the connection is
+// described in the same way by both sides.
+func vpcPeeringConnectionExtra(data map[string]interface{}) map[string]SdkResponseMapping {
+	extra := chargeExtraForVpc(data)
+	for _, k := range []string{"RequesterVpcInfo", "AccepterVpcInfo"} {
+		if info, ok := data[k].(map[string]interface{}); ok {
+			delete(info, "VpcPeeringConnectionId")
+		}
+	}
+	if accepter, ok := data["AccepterVpcInfo"].(map[string]interface{}); ok {
+		data["PeerVpcId"] = accepter["VpcId"]
+		data["PeerRegion"] = accepter["Region"]
+		data["PeerAccountId"] = accepter["AccountId"]
+	}
+	if v, ok := data["ServiceEndTime"].(string); !ok || v == "" {
+		delete(extra, "ServiceEndTime")
+	}
+	return extra
+}
+
+func (s *VpcService) ReadAndSetVpcPeeringConnection(d *schema.ResourceData, r *schema.Resource) (err error) {
+	data, err := s.ReadVpcPeeringConnection(d, "")
+	if err != nil {
+		return err
+	}
+	SdkResponseAutoResourceData(d, r, data, vpcPeeringConnectionExtra(data))
+	return err
+}
+
+func (s *VpcService) ReadAndSetVpcPeeringConnections(d *schema.ResourceData, r *schema.Resource) (err error) {
+	transform := map[string]SdkReqTransform{
+		"ids": {
+			mapping: "VpcPeeringConnectionId",
+			Type:    TransformWithN,
+		},
+		"project_ids": {
+			mapping: "ProjectId",
+			Type:    TransformWithN,
+		},
+		"vpc_ids": {
+			mapping: "vpc-id",
+			Type:    TransformWithFilter,
+		},
+	}
+	req, err := mergeDataSourcesReq(d, r, transform)
+	if err != nil {
+		return err
+	}
+	data, err := s.ReadVpcPeeringConnections(req)
+	if err != nil {
+		return err
+	}
+	for _, v := range data {
+		vpcPeeringConnectionExtra(v.(map[string]interface{}))
+	}
+
+	return mergeDataSourcesResp(d, r, ksyunDataSource{
+		collection:  data,
+		nameField:   "PeeringName",
+		idFiled:     "VpcPeeringConnectionId",
+		targetField: "vpc_peering_connections",
+		extra: map[string]SdkResponseMapping{
+			"VpcPeeringConnectionId": {
+				Field:    "id",
+				KeepAuto: false,
+			},
+		},
+	})
+}
+
+func (s *VpcService) CreateVpcPeeringConnectionCall(d *schema.ResourceData, r *schema.Resource) (callback ApiCall, err error) {
+	transform := map[string]SdkReqTransform{
+		"auto_accept": {Ignore: true},
+	}
+	req, err := SdkRequestAutoMapping(d, r, false, transform, nil, SdkReqParameter{
+		onlyTransform: false,
+	})
+	if err != nil {
+		return callback, err
+	}
+	callback = ApiCall{
+		param:  &req,
+		action: "CreateVpcPeeringConnection",
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			conn := client.vpcconn
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			resp, err = conn.CreateVpcPeeringConnection(call.param)
+			return resp, err
+		},
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			id, err := getSdkValue("VpcPeeringConnection.VpcPeeringConnectionId", *resp)
+			if err != nil {
+				return err
+			}
+			d.SetId(id.(string))
+			return err
+		},
+	}
+	return callback, err
+}
+
+func (s *VpcService) CreateVpcPeeringConnection(d *schema.ResourceData, r *schema.Resource) (err error) {
+	autoAccept := d.Get("auto_accept").(bool)
+	if autoAccept && d.Get("peer_account_id").(string) != "" {
+		return fmt.Errorf("auto_accept only works on the peering connection in the same account, " +
+			"use ksyun_vpc_peering_connection_accepter to accept it with the peer account")
+	}
+	call, err := s.CreateVpcPeeringConnectionCall(d, r)
+	if err != nil {
+		return err
+	}
+	err = ksyunApiCallNew([]ApiCall{call}, d, s.client, true)
+	if err != nil || !autoAccept {
+		return err
+	}
+	// the connection is accepted in the region of the peer vpc
+	_, err = s.client.WithRegionClient(d.Get("peer_region").(string), func(client *KsyunClient) (interface{}, error) {
+		peerService := VpcService{client}
+		return nil, peerService.AcceptVpcPeeringConnection(d, d.Id(), d.Timeout(schema.TimeoutCreate))
+	})
+	return err
+}
+
+// AcceptVpcPeeringConnection accepts the connection which is pending acceptance and waits it to be active
+func (s *VpcService) AcceptVpcPeeringConnection(d *schema.ResourceData, peeringId string, timeout time.Duration) (err error) {
+	data, err := s.ReadVpcPeeringConnection(d, peeringId)
+	if err != nil {
+		return err
+	}
+	if data["State"] == "pending-acceptance" {
+		call, err := s.AcceptVpcPeeringConnectionCall(peeringId)
+		if err != nil {
+			return err
+		}
+		if err = ksyunApiCallNew([]ApiCall{call}, d, s.client, true); err != nil {
+			return err
+		}
+	}
+	return s.checkVpcPeeringConnectionState(d, peeringId, "active", timeout)
+}
+
+func (s *VpcService) AcceptVpcPeeringConnectionCall(peeringId string) (callback ApiCall, err error) {
+	req := map[string]interface{}{
+		"VpcPeeringConnectionId": peeringId,
+	}
+	callback = ApiCall{
+		param:  &req,
+		action: "AcceptVpcPeeringConnection",
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			conn := client.vpcconn
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			resp, err = conn.AcceptVpcPeeringConnection(call.param)
+			return resp, err
+		},
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			return err
+		},
+	}
+	return callback, err
+}
+
+func (s *VpcService) checkVpcPeeringConnectionState(d *schema.ResourceData, peeringId string, target string, timeout time.Duration) (err error) {
+	stateConf := &resource.StateChangeConf{
+		Pending: []string{"pending-acceptance", "provisioning"},
+		Target:  []string{target},
+		Refresh: func() (interface{}, string, error) {
+			data, err := s.ReadVpcPeeringConnection(d, peeringId)
+			if err != nil {
+				return nil, "", err
+			}
+			state := fmt.Sprintf("%v", data["State"])
+			if state == "rejected" || state == "expired" {
+				return nil, "", fmt.Errorf("the vpc peering connection %s is %s", peeringId, state)
+			}
+			return data, state, nil
+		},
+		Timeout:    timeout,
+		Delay:      2 * time.Second,
+		MinTimeout: 1 * time.Second,
+	}
+	_, err = stateConf.WaitForState()
+	return err
+}
+
+func (s *VpcService) ModifyVpcPeeringConnectionCall(d *schema.ResourceData, r *schema.Resource) (callback ApiCall, err error) {
+	transform := map[string]SdkReqTransform{
+		"peering_name": {},
+		"band_width":   {},
+	}
+	req, err := SdkRequestAutoMapping(d, r, true, transform, nil)
+	if err != nil {
+		return callback, err
+	}
+	if len(req) > 0 {
+		req["VpcPeeringConnectionId"] = d.Id()
+		callback = ApiCall{
+			param:  &req,
+			action: "ModifyVpcPeeringConnection",
+			executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+				conn := client.vpcconn
+				logger.Debug(logger.RespFormat, call.action, *(call.param))
+				resp, err = conn.ModifyVpcPeeringConnection(call.param)
+				return resp, err
+			},
+			afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+				logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+				return err
+			},
+		}
+	}
+	return callback, err
+}
+
+func (s *VpcService) ModifyVpcPeeringConnection(d *schema.ResourceData, r *schema.Resource) (err error) {
+	call, err := s.ModifyVpcPeeringConnectionCall(d, r)
+	if err != nil {
+		return err
+	}
+	return ksyunApiCallNew([]ApiCall{call}, d, s.client, true)
+}
+
+func (s *VpcService) RemoveVpcPeeringConnectionCall(d *schema.ResourceData) (callback ApiCall, err error) {
+	removeReq := map[string]interface{}{
+		"VpcPeeringConnectionId": d.Id(),
+	}
+	callback = ApiCall{
+		param:  &removeReq,
+		action: "DeleteVpcPeeringConnection",
+		executeCall: func(d *schema.ResourceData, client *KsyunClient, call ApiCall) (resp *map[string]interface{}, err error) {
+			conn := client.vpcconn
+			logger.Debug(logger.RespFormat, call.action, *(call.param))
+			resp, err = conn.DeleteVpcPeeringConnection(call.param)
+			return resp, err
+		},
+		callError: func(d *schema.ResourceData, client *KsyunClient, call ApiCall, baseErr error) error {
+			return resource.Retry(15*time.Minute, func() *resource.RetryError {
+				_, callErr := s.ReadVpcPeeringConnection(d, "")
+				if callErr != nil {
+					if notFoundError(callErr) {
+						return nil
+					} else {
+						return resource.NonRetryableError(fmt.Errorf("error on  reading vpc peering connection when delete %q, %s", d.Id(), callErr))
+					}
+				}
+				_, callErr = call.executeCall(d, client, call)
+				if callErr == nil {
+					return nil
+				}
+				return resource.RetryableError(callErr)
+			})
+		},
+		afterCall: func(d *schema.ResourceData, client *KsyunClient, resp *map[string]interface{}, call ApiCall) (err error) {
+			logger.Debug(logger.RespFormat, call.action, *(call.param), *resp)
+			return err
+		},
+	}
+	return callback, err
+}
+
+func (s *VpcService) RemoveVpcPeeringConnection(d *schema.ResourceData) (err error) {
+	call, err := s.RemoveVpcPeeringConnectionCall(d)
+	if err != nil {
+		return err
+	}
+	return ksyunApiCallNew([]ApiCall{call}, d, s.client, true)
+}
+
 func (s *VpcService) ReadAvailabilityZones(condition map[string]interface{}) (data []interface{}, err error) {
 	var (
 		resp    *map[string]interface{}
@@ -469,6 +469,10 @@ func SdkRequestAutoMapping(d *schema.ResourceData, resource *schema.Resource, is
 		}
 	} else {
 		for k := range resource.Schema {
+			// tags_all is computed from tags and the default tags of provider, never a request parameter
+			if k == "tags_all" {
+				continue
+			}
 			if v, ok := transform[k]; ok {
 				if isUpdate {
 					count, err = requestUpdateMapping(d, k, v, count, nil, &req)
@@ -61,6 +61,50 @@ func networkAclEntryCustomizeDiff(d *schema.ResourceDiff, meta interface{}) (err
 	return err
 }
 
+// networkAclRulesCustomizeDiff checks the ordered rules of ksyun_network_acl_rules on plan: the ports of tcp and udp
+// rules, the shadowed rules, the overlapped ones if reject_overlaps is set and whether the rules without rule_number
+// can be assigned one.
+func networkAclRulesCustomizeDiff(d *schema.ResourceDiff, meta interface{}) (err error) {
+	for _, field := range []string{"ingress", "egress"} {
+		if (!d.HasChange(field) && !d.HasChange("reject_overlaps")) || !d.NewValueKnown(field) {
+			continue
+		}
+		rules := d.Get(field).([]interface{})
+		for i, v := range rules {
+			rule := v.(map[string]interface{})
+			if rule["protocol"] != "tcp" && rule["protocol"] != "udp" {
+				continue
+			}
+			for _, k := range []string{"port_range_from", "port_range_to"} {
+				key := fmt.Sprintf("%s.%d.%s", field, i, k)
+				if d.NewValueKnown(key) && rule[k].(int) == 0 {
+					return fmt.Errorf("%s is required when protocol is %s", key, rule["protocol"])
+				}
+			}
+			if rule["port_range_from"].(int) > rule["port_range_to"].(int) {
+				return fmt.Errorf("%s.%d.port_range_from must not be greater than port_range_to", field, i)
+			}
+		}
+		err = checkNetworkAclRulesShadowed(field, rules, d.Get("reject_overlaps").(bool))
+		if err != nil {
+			return err
+		}
+		// the numbers assigned before are reused on apply
+		existing := make(map[string][]int)
+		o, _ := d.GetChange(field)
+		for _, v := range o.([]interface{}) {
+			rule := v.(map[string]interface{})
+			key := networkAclRuleKey(networkAclRuleDirection(field), rule)
+			existing[key] = append(existing[key], rule["assigned_rule_number"].(int))
+		}
+		_, err = assignNetworkAclRuleNumbers(field, rules, existing)
+		if err != nil {
+			return err
+		}
+	}
+	return err
+}
+
 func bareMetalCustomizeDiff(d *schema.ResourceDiff, meta interface{}) (err error) {
 
 	if d.Get("network_interface_mode") != "dual" {
@@ -108,3 +152,101 @@ func bareMetalCustomizeDiff(d *schema.ResourceDiff, meta interface{}) (err error
 	}
 	return err
 }
+
+func kecInstanceCustomizeDiff(d *schema.ResourceDiff, meta interface{}) (err error) {
+	if d.Get("spot_strategy") != "" && d.NewValueKnown("charge_type") && d.Get("charge_type") != "HourlyInstantSettlement" {
+		return fmt.Errorf("spot_strategy is only supported when charge_type is HourlyInstantSettlement")
+	}
+	if err = kecDataDisksCustomizeDiff(d); err != nil {
+		return err
+	}
+	return spotStrategyCustomizeDiff(d, meta)
+}
+
+// kecDataDisksCustomizeDiff forces a new instance for the data disk changes which can't be applied in place,
+// the EBS data disks are only expanded and changed between EBS types by modifyKecInstanceDataDisks.
+func kecDataDisksCustomizeDiff(d *schema.ResourceDiff) (err error) {
+	if d.Id() == "" || !d.HasChange("data_disks") {
+		return err
+	}
+	o, n := d.GetChange("data_disks")
+	oldDisks, newDisks := o.([]interface{}), n.([]interface{})
+	if len(oldDisks) != len(newDisks) {
+		return d.ForceNew("data_disks")
+	}
+	for i := range newDisks {
+		prefix := "data_disks." + strconv.Itoa(i) + "."
+		oldDisk, newDisk := oldDisks[i].(map[string]interface{}), newDisks[i].(map[string]interface{})
+		if d.HasChange(prefix+"disk_type") && d.NewValueKnown(prefix+"disk_type") &&
+			(oldDisk["disk_type"] == "Local_SSD" || newDisk["disk_type"] == "Local_SSD") {
+			if err = d.ForceNew(prefix + "disk_type"); err != nil {
+				return err
+			}
+		}
+		if d.HasChange(prefix+"disk_size") && d.NewValueKnown(prefix+"disk_size") &&
+			(oldDisk["disk_type"] == "Local_SSD" || newDisk["disk_size"].(int) < oldDisk["disk_size"].(int)) {
+			if err = d.ForceNew(prefix + "disk_size"); err != nil {
+				return err
+			}
+		}
+	}
+	return err
+}
+
+func spotStrategyCustomizeDiff(d *schema.ResourceDiff, meta interface{}) (err error) {
+	if !d.NewValueKnown("spot_strategy") || !d.NewValueKnown("spot_price_limit") {
+		return err
+	}
+	_, hasLimit := d.GetOk("spot_price_limit")
+	switch d.Get("spot_strategy") {
+	case "SpotWithPriceLimit":
+		if !hasLimit {
+			return fmt.Errorf("spot_price_limit is required when spot_strategy is SpotWithPriceLimit")
+		}
+	default:
+		if hasLimit && d.HasChange("spot_price_limit") {
+			return fmt.Errorf("spot_price_limit must be used with spot_strategy SpotWithPriceLimit")
+		}
+	}
+	return err
+}
+
+// kecInstanceGroupCustomizeDiff checks the minimum count of the group, and marks the instances unknown when the group scales
+func kecInstanceGroupCustomizeDiff(d *schema.ResourceDiff, meta interface{}) (err error) {
+	if d.NewValueKnown("instance_count") && d.NewValueKnown("min_instance_count") {
+		if min, ok := d.GetOk("min_instance_count"); ok && min.(int) > d.Get("instance_count").(int) {
+			return fmt.Errorf("min_instance_count %d is greater than instance_count %d", min, d.Get("instance_count"))
+		}
+	}
+	if d.Id() == "" || !d.NewValueKnown("instance_count") {
+		return err
+	}
+	// the group also scales when its instances are fewer than instance_count, e.g. terminated outside
+	ids, _ := d.GetChange("instance_ids")
+	if d.HasChange("instance_count") || len(ids.([]interface{})) != d.Get("instance_count").(int) {
+		if err = d.SetNewComputed("instance_ids"); err != nil {
+			return err
+		}
+		return d.SetNewComputed("instances")
+	}
+	return err
+}
+
+// securityGroupEntrySetCustomizeDiff rejects the entries of ksyun_security_group_entry_set which differ only in
+// description, the remote keeps one entry for them, so that the others would be dropped silently.
+func securityGroupEntrySetCustomizeDiff(d *schema.ResourceDiff, meta interface{}) (err error) {
+	if !d.HasChange("security_group_entries") || !d.NewValueKnown("security_group_entries") {
+		return err
+	}
+	seen := make(map[int]map[string]interface{})
+	for _, v := range d.Get("security_group_entries").(*schema.Set).List() {
+		entry := v.(map[string]interface{})
+		index := securityGroupEntrySimpleHash(entry)
+		if former, ok := seen[index]; ok {
+			return fmt.Errorf("security_group_entries has duplicated entries of %s %s %s, which differ only in description %q and %q",
+				entry["direction"], entry["protocol"], entry["cidr_block"], former["description"], entry["description"])
+		}
+		seen[index] = entry
+	}
+	return err
+}
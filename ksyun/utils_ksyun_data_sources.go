@@ -42,28 +42,9 @@ func mergeDataSourcesResp(d *schema.ResourceData, r *schema.Resource, dataSource
 		result []map[string]interface{}
 	)
 
-	if plugIns != nil && len(plugIns) > 0 {
-		for _, plugIn := range plugIns {
-			var filter []interface{}
-			for _, item := range dataSource.collection {
-				var (
-					temp map[string]interface{}
-					flag bool
-				)
-				temp, flag, err = plugIn(d, item.(map[string]interface{}))
-				if err != nil {
-					return err
-				}
-				if flag {
-					if temp != nil {
-						filter = append(filter, temp)
-					}
-				} else {
-					filter = append(filter, item.(map[string]interface{}))
-				}
-			}
-			dataSource.collection = filter
-		}
+	dataSource.collection, err = matchDataSourcesResp(d, dataSource.collection, plugIns...)
+	if err != nil {
+		return err
 	}
 
 	for _, item := range dataSource.collection {
@@ -96,6 +77,33 @@ func mergeDataSourcesResp(d *schema.ResourceData, r *schema.Resource, dataSource
 	return err
 }
 
+// matchDataSourcesResp returns the items of collection matched by all the plugIns, in the order of collection.
+func matchDataSourcesResp(d *schema.ResourceData, collection []interface{}, plugIns ...matchPlugin) (result []interface{}, err error) {
+	result = collection
+	for _, plugIn := range plugIns {
+		var filter []interface{}
+		for _, item := range result {
+			var (
+				temp map[string]interface{}
+				flag bool
+			)
+			temp, flag, err = plugIn(d, item.(map[string]interface{}))
+			if err != nil {
+				return result, err
+			}
+			if flag {
+				if temp != nil {
+					filter = append(filter, temp)
+				}
+			} else {
+				filter = append(filter, item.(map[string]interface{}))
+			}
+		}
+		result = filter
+	}
+	return result, err
+}
+
 func mergeNameRegex(d *schema.ResourceData, data map[string]interface{}, nameField string) (result map[string]interface{}, flag bool, err error) {
 	if nameRegex, ok := d.GetOk("name_regex"); ok {
 		match := regexp.MustCompile(nameRegex.(string))
@@ -0,0 +1,151 @@
+package ksyun
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+type planDryRunCallFunc func(client *KsyunClient, d *schema.ResourceData, r *schema.Resource) (ApiCall, error)
+
+// planDryRunCalls are the creation calls supporting DryRun, they are validated during plan
+// when dry_run_on_plan of provider is enabled.
+var planDryRunCalls = map[string]planDryRunCallFunc{
+	"ksyun_instance": func(client *KsyunClient, d *schema.ResourceData, r *schema.Resource) (ApiCall, error) {
+		// the image and network are known after apply unless they're set or taken from the launch template
+		if _, ok := d.GetOk("launch_template_id"); !ok {
+			for _, k := range []string{"image_id", "subnet_id", "security_group_id"} {
+				if _, ok = d.GetOk(k); !ok {
+					log.Printf("[DEBUG] skip the dry run of instance during plan since %s is known after apply", k)
+					return ApiCall{}, nil
+				}
+			}
+		}
+		srv := KecService{client}
+		return srv.createKecInstanceCommon(d, r)
+	},
+	"ksyun_eip": func(client *KsyunClient, d *schema.ResourceData, r *schema.Resource) (ApiCall, error) {
+		srv := EipService{client}
+		return srv.CreateAddressCall(d, r)
+	},
+	"ksyun_vpc": func(client *KsyunClient, d *schema.ResourceData, r *schema.Resource) (ApiCall, error) {
+		srv := VpcService{client}
+		return srv.CreateVpcCall(d, r)
+	},
+	"ksyun_subnet": func(client *KsyunClient, d *schema.ResourceData, r *schema.Resource) (ApiCall, error) {
+		// the cidr block can't be decided if neither cidr_block nor cidr_mask_length is known
+		if _, ok := d.GetOk("cidr_block"); !ok {
+			if _, ok = d.GetOk("cidr_mask_length"); !ok {
+				log.Printf("[DEBUG] skip the dry run of subnet during plan since the cidr block is known after apply")
+				return ApiCall{}, nil
+			}
+		}
+		srv := VpcService{client}
+		return srv.CreateSubnetCall(d, r)
+	},
+	"ksyun_security_group": func(client *KsyunClient, d *schema.ResourceData, r *schema.Resource) (ApiCall, error) {
+		srv := VpcService{client}
+		return srv.CreateSecurityGroupCall(d, r)
+	},
+	"ksyun_route": func(client *KsyunClient, d *schema.ResourceData, r *schema.Resource) (ApiCall, error) {
+		srv := VpcService{client}
+		return srv.CreateRouteCall(d, r)
+	},
+	"ksyun_nat": func(client *KsyunClient, d *schema.ResourceData, r *schema.Resource) (ApiCall, error) {
+		srv := VpcService{client}
+		return srv.CreateNatCall(d, r)
+	},
+	"ksyun_network_acl": func(client *KsyunClient, d *schema.ResourceData, r *schema.Resource) (ApiCall, error) {
+		srv := VpcService{client}
+		return srv.CreateNetworkAclCall(d, r)
+	},
+}
+
+// withPlanDryRun makes the resources of planDryRunCalls send their creation calls with DryRun
+// during plan, so the permission and quota errors are reported before anything is created.
+func withPlanDryRun(resources map[string]*schema.Resource) {
+	for name, callFunc := range planDryRunCalls {
+		callFunc := callFunc
+		r, ok := resources[name]
+		if !ok {
+			continue
+		}
+		customizeDiff := r.CustomizeDiff
+		r.CustomizeDiff = func(d *schema.ResourceDiff, meta interface{}) error {
+			if customizeDiff != nil {
+				if err := customizeDiff(d, meta); err != nil {
+					return err
+				}
+			}
+			return planDryRunCustomizeDiff(d, meta, r, callFunc)
+		}
+	}
+}
+
+func planDryRunCustomizeDiff(diff *schema.ResourceDiff, meta interface{}, r *schema.Resource, callFunc planDryRunCallFunc) error {
+	client := meta.(*KsyunClient)
+	// only the creation is validated, the resources in state have passed it
+	if !client.dryRunOnPlan || diff.Id() != "" {
+		return nil
+	}
+	d, known := resourceDataFromDiff(diff, r)
+	if !known {
+		log.Printf("[DEBUG] skip the dry run during plan since some arguments are known after apply")
+		return nil
+	}
+	call, err := callFunc(client, d, r)
+	if err != nil {
+		return err
+	}
+	if err = ksyunApiCallProcess([]ApiCall{call}, d, client, true); err != nil {
+		return fmt.Errorf("the dry run of %s failed: %s", call.action, err)
+	}
+	return nil
+}
+
+// resourceDataFromDiff returns the ResourceData holding the planned arguments of diff, known is false
+// if any argument can't be known until apply, such as the id of a resource being created.
+func resourceDataFromDiff(diff *schema.ResourceDiff, r *schema.Resource) (d *schema.ResourceData, known bool) {
+	d = r.Data(nil)
+	for k, s := range r.Schema {
+		if !s.Optional && !s.Required {
+			continue
+		}
+		if !planValueKnown(diff, k, s) {
+			// the optional computed arguments not set are unknown too, they're left to the api
+			if s.Computed {
+				continue
+			}
+			return d, false
+		}
+		if v, ok := diff.GetOk(k); ok {
+			if err := d.Set(k, v); err != nil {
+				log.Printf("[WARN] can't set %s for the dry run, %s", k, err)
+				return d, false
+			}
+		}
+	}
+	return d, true
+}
+
+func planValueKnown(diff *schema.ResourceDiff, k string, s *schema.Schema) bool {
+	if !diff.NewValueKnown(k) {
+		return false
+	}
+	elem, ok := s.Elem.(*schema.Resource)
+	if !ok || s.Type != schema.TypeList {
+		return true
+	}
+	count, _ := diff.Get(k + ".#").(int)
+	for i := 0; i < count; i++ {
+		for field, fieldSchema := range elem.Schema {
+			key := k + "." + strconv.Itoa(i) + "." + field
+			if !planValueKnown(diff, key, fieldSchema) && !fieldSchema.Computed {
+				return false
+			}
+		}
+	}
+	return true
+}
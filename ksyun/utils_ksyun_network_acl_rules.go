@@ -0,0 +1,178 @@
+package ksyun
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+const (
+	// networkAclRuleNumberStep is the distance between the auto assigned rule numbers, the gaps leave room for the
+	// rules inserted later without renumbering the existing ones.
+	networkAclRuleNumberStep = 10
+	networkAclRuleNumberMax  = 32766
+)
+
+// networkAclRuleKey identifies the traffic matched by a rule, the rule number and description are excluded.
+func networkAclRuleKey(direction string, rule map[string]interface{}) string {
+	protocol := strings.ToLower(rule["protocol"].(string))
+	key := fmt.Sprintf("%s-%s-%s-%s", direction, strings.ToLower(rule["rule_action"].(string)), protocol, rule["cidr_block"])
+	switch protocol {
+	case "icmp":
+		key += fmt.Sprintf("-%d-%d", rule["icmp_type"], rule["icmp_code"])
+	case "tcp", "udp":
+		key += fmt.Sprintf("-%d-%d", rule["port_range_from"], rule["port_range_to"])
+	}
+	return key
+}
+
+// assignNetworkAclRuleNumbers returns the rule numbers of the ordered rules of a direction. The rules with rule_number
+// keep it, the others reuse the number of the existing identical rule if it still fits the order, otherwise they
+// take a free number between the neighbouring rules.
+func assignNetworkAclRuleNumbers(field string, rules []interface{}, existing map[string][]int) (numbers []int, err error) {
+	numbers = make([]int, len(rules))
+	direction := networkAclRuleDirection(field)
+	prev := 0
+	for i, v := range rules {
+		if n := v.(map[string]interface{})["rule_number"].(int); n > 0 {
+			if n <= prev {
+				return numbers, fmt.Errorf("%s.%d.rule_number %d must be greater than %d, the rules are evaluated in the order of the list",
+					field, i, n, prev)
+			}
+			numbers[i] = n
+			prev = n
+		}
+	}
+
+	// reuse the numbers of the existing rules
+	used := make(map[int]bool)
+	prev = 0
+	for i, v := range rules {
+		if numbers[i] > 0 {
+			prev = numbers[i]
+			continue
+		}
+		next, remain := networkAclNextRuleNumber(numbers, i)
+		for _, n := range existing[networkAclRuleKey(direction, v.(map[string]interface{}))] {
+			if !used[n] && n > prev && n+remain < next {
+				numbers[i] = n
+				used[n] = true
+				prev = n
+				break
+			}
+		}
+	}
+
+	// fill the others between the assigned ones
+	for i := 0; i < len(numbers); i++ {
+		if numbers[i] > 0 {
+			continue
+		}
+		low := 0
+		if i > 0 {
+			low = numbers[i-1]
+		}
+		high, count := networkAclNextRuleNumber(numbers, i)
+		count++
+		step := networkAclRuleNumberStep
+		if low+step*count >= high {
+			step = (high - low) / (count + 1)
+		}
+		if step < 1 {
+			return numbers, fmt.Errorf("there is no free rule_number between %d and %d for %d rules of %s", low, high, count, field)
+		}
+		for j := 0; j < count; j++ {
+			numbers[i+j] = low + step*(j+1)
+		}
+	}
+	return numbers, err
+}
+
+// networkAclNextRuleNumber returns the next assigned rule number after i and the count of the unassigned rules
+// between them.
+func networkAclNextRuleNumber(numbers []int, i int) (next int, remain int) {
+	for j := i + 1; j < len(numbers); j++ {
+		if numbers[j] > 0 {
+			return numbers[j], remain
+		}
+		remain++
+	}
+	return networkAclRuleNumberMax + 1, remain
+}
+
+func networkAclRuleDirection(field string) string {
+	if field == "egress" {
+		return "out"
+	}
+	return "in"
+}
+
+// checkNetworkAclRulesShadowed returns an error if a rule never takes effect because all of its traffic is matched
+// by a former rule. The rules partially overlapped by a former rule with a different action are only rejected when
+// rejectOverlaps is set, as they're the usual way to make exceptions, e.g. a rule denying all the traffic at last.
+func checkNetworkAclRulesShadowed(field string, rules []interface{}, rejectOverlaps bool) error {
+	for j := range rules {
+		later := rules[j].(map[string]interface{})
+		for i := 0; i < j; i++ {
+			former := rules[i].(map[string]interface{})
+			if networkAclRuleCovers(former, later) {
+				return fmt.Errorf("%s.%d is shadowed by %s.%d and never takes effect", field, j, field, i)
+			}
+			if rejectOverlaps && former["rule_action"] != later["rule_action"] && networkAclRuleOverlaps(former, later) {
+				return fmt.Errorf("%s.%d is partially overlapped by %s.%d with a different rule_action, the former rule wins for the overlapped traffic",
+					field, j, field, i)
+			}
+		}
+	}
+	return nil
+}
+
+// networkAclRuleCovers returns true if all the traffic matched by b is matched by a
+func networkAclRuleCovers(a, b map[string]interface{}) bool {
+	_, netA, errA := net.ParseCIDR(a["cidr_block"].(string))
+	_, netB, errB := net.ParseCIDR(b["cidr_block"].(string))
+	if errA != nil || errB != nil {
+		return false
+	}
+	onesA, _ := netA.Mask.Size()
+	onesB, _ := netB.Mask.Size()
+	if onesA > onesB || !netA.Contains(netB.IP) {
+		return false
+	}
+	protocol := a["protocol"].(string)
+	if protocol == "ip" {
+		return true
+	}
+	if protocol != b["protocol"] {
+		return false
+	}
+	switch protocol {
+	case "icmp":
+		return a["icmp_type"] == b["icmp_type"] && a["icmp_code"] == b["icmp_code"]
+	case "tcp", "udp":
+		return a["port_range_from"].(int) <= b["port_range_from"].(int) && b["port_range_to"].(int) <= a["port_range_to"].(int)
+	}
+	return true
+}
+
+// networkAclRuleOverlaps returns true if some traffic is matched by both a and b
+func networkAclRuleOverlaps(a, b map[string]interface{}) bool {
+	_, netA, errA := net.ParseCIDR(a["cidr_block"].(string))
+	_, netB, errB := net.ParseCIDR(b["cidr_block"].(string))
+	if errA != nil || errB != nil || !(netA.Contains(netB.IP) || netB.Contains(netA.IP)) {
+		return false
+	}
+	if a["protocol"] == "ip" || b["protocol"] == "ip" {
+		return true
+	}
+	if a["protocol"] != b["protocol"] {
+		return false
+	}
+	switch a["protocol"] {
+	case "icmp":
+		return a["icmp_type"] == b["icmp_type"] && a["icmp_code"] == b["icmp_code"]
+	case "tcp", "udp":
+		return a["port_range_from"].(int) <= b["port_range_to"].(int) && b["port_range_from"].(int) <= a["port_range_to"].(int)
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+package ksyun
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testNetworkAclRule(ruleNumber int, action, protocol, cidr string, ports ...int) interface{} {
+	rule := map[string]interface{}{
+		"rule_number":     ruleNumber,
+		"rule_action":     action,
+		"protocol":        protocol,
+		"cidr_block":      cidr,
+		"icmp_type":       0,
+		"icmp_code":       0,
+		"port_range_from": 0,
+		"port_range_to":   0,
+		"description":     "",
+	}
+	if len(ports) == 2 {
+		rule["port_range_from"] = ports[0]
+		rule["port_range_to"] = ports[1]
+	}
+	return rule
+}
+
+func TestAssignNetworkAclRuleNumbers(t *testing.T) {
+	ssh := testNetworkAclRule(0, "allow", "tcp", "10.0.0.0/8", 22, 22)
+	web := testNetworkAclRule(0, "allow", "tcp", "0.0.0.0/0", 443, 443)
+	deny := testNetworkAclRule(100, "deny", "ip", "0.0.0.0/0")
+
+	numbers, err := assignNetworkAclRuleNumbers("ingress", []interface{}{ssh, web, deny}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if expected := []int{10, 20, 100}; !reflect.DeepEqual(numbers, expected) {
+		t.Errorf("Expected %v, got %v", expected, numbers)
+	}
+
+	// the inserted rule takes the gap, the existing ones keep their numbers
+	dns := testNetworkAclRule(0, "allow", "udp", "0.0.0.0/0", 53, 53)
+	existing := map[string][]int{
+		networkAclRuleKey("in", ssh.(map[string]interface{})): {10},
+		networkAclRuleKey("in", web.(map[string]interface{})): {20},
+	}
+	numbers, err = assignNetworkAclRuleNumbers("ingress", []interface{}{ssh, dns, web, deny}, existing)
+	if err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if expected := []int{10, 15, 20, 100}; !reflect.DeepEqual(numbers, expected) {
+		t.Errorf("Expected %v, got %v", expected, numbers)
+	}
+
+	// the explicit numbers must follow the order of the list
+	_, err = assignNetworkAclRuleNumbers("ingress", []interface{}{deny, testNetworkAclRule(50, "allow", "ip", "10.0.0.0/8")}, nil)
+	if err == nil || !strings.Contains(err.Error(), "ingress.1.rule_number 50 must be greater than 100") {
+		t.Errorf("Expected an order error, got %v", err)
+	}
+
+	// no gap between 1 and 2
+	_, err = assignNetworkAclRuleNumbers("egress", []interface{}{
+		testNetworkAclRule(1, "allow", "ip", "10.0.0.0/8"),
+		ssh,
+		testNetworkAclRule(2, "deny", "ip", "0.0.0.0/0"),
+	}, nil)
+	if err == nil || !strings.Contains(err.Error(), "no free rule_number between 1 and 2") {
+		t.Errorf("Expected a gap error, got %v", err)
+	}
+}
+
+func TestCheckNetworkAclRulesShadowed(t *testing.T) {
+	rules := []interface{}{
+		testNetworkAclRule(0, "deny", "tcp", "10.0.1.0/24", 22, 22),
+		testNetworkAclRule(0, "allow", "tcp", "10.0.0.0/16", 1, 1024),
+		testNetworkAclRule(0, "deny", "ip", "0.0.0.0/0"),
+	}
+	// the partial overlaps with a different action are accepted unless they're rejected
+	if err := checkNetworkAclRulesShadowed("ingress", rules, false); err != nil {
+		t.Errorf("Expected no error, got %s", err)
+	}
+	err := checkNetworkAclRulesShadowed("ingress", rules, true)
+	if err == nil || !strings.Contains(err.Error(), "ingress.1 is partially overlapped by ingress.0") {
+		t.Errorf("Expected an overlapped error, got %v", err)
+	}
+	// the overlaps with the same action are always accepted
+	sameAction := []interface{}{
+		testNetworkAclRule(0, "allow", "tcp", "10.0.1.0/24", 1, 100),
+		testNetworkAclRule(0, "allow", "tcp", "10.0.0.0/16", 50, 200),
+	}
+	if err = checkNetworkAclRulesShadowed("ingress", sameAction, true); err != nil {
+		t.Errorf("Expected no error, got %s", err)
+	}
+
+	rules = append(rules, testNetworkAclRule(0, "allow", "udp", "192.168.0.0/24", 53, 53))
+	err = checkNetworkAclRulesShadowed("ingress", rules, false)
+	if err == nil || !strings.Contains(err.Error(), "ingress.3 is shadowed by ingress.2") {
+		t.Errorf("Expected a shadowed error, got %v", err)
+	}
+
+	rules = []interface{}{
+		testNetworkAclRule(0, "allow", "tcp", "10.0.0.0/16", 1, 1024),
+		testNetworkAclRule(0, "deny", "tcp", "10.0.1.0/24", 80, 80),
+	}
+	err = checkNetworkAclRulesShadowed("egress", rules, false)
+	if err == nil || !strings.Contains(err.Error(), "egress.1 is shadowed by egress.0") {
+		t.Errorf("Expected a shadowed error, got %v", err)
+	}
+}
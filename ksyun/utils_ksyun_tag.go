@@ -1,8 +1,10 @@
 package ksyun
 
 import (
-	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"reflect"
 	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
 
 func tagsSchema() *schema.Schema {
@@ -13,13 +15,21 @@ func tagsSchema() *schema.Schema {
 	}
 }
 
+func tagsAllSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeMap,
+		Computed:    true,
+		Description: "all tags of the resource, including the default tags of provider.",
+	}
+}
+
 func mergeTagsData(d *schema.ResourceData, data *map[string]interface{}, client *KsyunClient, resourceType string) (err error) {
 	var tags []interface{}
 	tagService := TagService{client}
 	tags, err = tagService.ReadTagByResourceId(d, d.Id(), resourceType)
 	if err != nil {
 		//此处暂时兼容如果没有更改tags可以忽略listTags的权限检查。做到最大兼容性
-		if !d.HasChange("tags") {
+		if !hasTagsChange(d) {
 			errMessage := strings.ToLower(err.Error())
 			if strings.Contains(errMessage, "lack of policy") {
 				return nil
@@ -30,6 +40,9 @@ func mergeTagsData(d *schema.ResourceData, data *map[string]interface{}, client
 	tagMap := make(map[string]interface{})
 	for _, tag := range tags {
 		_m := tag.(map[string]interface{})
+		if client.isIgnoredTag(_m["TagKey"].(string)) {
+			continue
+		}
 		tagMap[_m["TagKey"].(string)] = _m["TagValue"].(string)
 	}
 	if len(tagMap) > 0 {
@@ -37,3 +50,147 @@ func mergeTagsData(d *schema.ResourceData, data *map[string]interface{}, client
 	}
 	return err
 }
+
+// defaultTags returns the default tags of provider
+func (client *KsyunClient) defaultTags() map[string]interface{} {
+	if client == nil || client.config == nil {
+		return nil
+	}
+	return client.config.DefaultTags
+}
+
+// mergeDefaultTags returns the tags of resource merged with the default tags of provider,
+// the tags of resource win on conflict.
+func (client *KsyunClient) mergeDefaultTags(tags map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for k, v := range client.defaultTags() {
+		result[k] = v
+	}
+	for k, v := range tags {
+		result[k] = v
+	}
+	return result
+}
+
+// ignoreDefaultTags returns the tags of resource from all tags read from remote, the default tags
+// are excluded unless they are declared by the resource as well.
+func (client *KsyunClient) ignoreDefaultTags(tagsAll, declared map[string]interface{}) map[string]interface{} {
+	defaultTags := client.defaultTags()
+	result := make(map[string]interface{})
+	for k, v := range tagsAll {
+		if dv, ok := defaultTags[k]; ok && dv == v {
+			if _, ok := declared[k]; !ok {
+				continue
+			}
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// isIgnoredTag returns whether the tag key is ignored by provider
+func (client *KsyunClient) isIgnoredTag(key string) bool {
+	if client == nil || client.config == nil || client.config.IgnoreTags == nil {
+		return false
+	}
+	for _, k := range client.config.IgnoreTags.Keys {
+		if k == key {
+			return true
+		}
+	}
+	for _, prefix := range client.config.IgnoreTags.KeyPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasIgnoreTags returns whether any tag is ignored by provider
+func (client *KsyunClient) hasIgnoreTags() bool {
+	if client == nil || client.config == nil || client.config.IgnoreTags == nil {
+		return false
+	}
+	return len(client.config.IgnoreTags.Keys) > 0 || len(client.config.IgnoreTags.KeyPrefixes) > 0
+}
+
+// removeIgnoredTags returns the tags except the ones ignored by provider
+func (client *KsyunClient) removeIgnoredTags(tags map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{})
+	for k, v := range tags {
+		if client.isIgnoredTag(k) {
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// hasTags returns whether the resource will have tags, either declared or from provider
+func hasTags(d *schema.ResourceData, client *KsyunClient) bool {
+	if _, ok := d.GetOk("tags"); ok {
+		return true
+	}
+	return len(client.defaultTags()) > 0
+}
+
+// hasTagsChange returns whether the tags of resource or the default tags of provider changed
+func hasTagsChange(d *schema.ResourceData) bool {
+	return d.HasChange("tags") || d.HasChange("tags_all")
+}
+
+// withDefaultTags makes the resources having tags_all attribute honour the default tags and ignore tags
+// of provider. The tags_all is planned as the merged tags, and the tags read from remote are split into
+// tags and tags_all after the ignored tags are removed.
+func withDefaultTags(resources map[string]*schema.Resource) {
+	for _, r := range resources {
+		if _, ok := r.Schema["tags_all"]; !ok {
+			continue
+		}
+		customizeDiff := r.CustomizeDiff
+		r.CustomizeDiff = func(d *schema.ResourceDiff, meta interface{}) error {
+			if customizeDiff != nil {
+				if err := customizeDiff(d, meta); err != nil {
+					return err
+				}
+			}
+			return tagsAllCustomizeDiff(d, meta)
+		}
+		r.Create = setTagsAllAfter(r.Create)
+		r.Read = setTagsAllAfter(r.Read)
+		r.Update = setTagsAllAfter(r.Update)
+	}
+}
+
+func tagsAllCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("tags") {
+		return d.SetNewComputed("tags_all")
+	}
+	tagsAll := meta.(*KsyunClient).mergeDefaultTags(d.Get("tags").(map[string]interface{}))
+	if reflect.DeepEqual(d.Get("tags_all"), tagsAll) {
+		return nil
+	}
+	return d.SetNew("tags_all", tagsAll)
+}
+
+func setTagsAllAfter(f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+	if f == nil {
+		return nil
+	}
+	return func(d *schema.ResourceData, meta interface{}) error {
+		declared := d.Get("tags").(map[string]interface{})
+		if err := f(d, meta); err != nil {
+			return err
+		}
+		// the resource has been removed
+		if d.Id() == "" {
+			return nil
+		}
+		client := meta.(*KsyunClient)
+		tagsAll := client.removeIgnoredTags(d.Get("tags").(map[string]interface{}))
+		if err := d.Set("tags_all", tagsAll); err != nil {
+			return err
+		}
+		return d.Set("tags", client.ignoreDefaultTags(tagsAll, declared))
+	}
+}
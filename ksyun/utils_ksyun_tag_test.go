@@ -0,0 +1,83 @@
+package ksyun
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func testTagsClient(defaultTags map[string]interface{}) *KsyunClient {
+	return &KsyunClient{config: &Config{DefaultTags: defaultTags}}
+}
+
+func TestMergeDefaultTags(t *testing.T) {
+	client := testTagsClient(map[string]interface{}{"team": "infra", "env": "prod"})
+	tags := client.mergeDefaultTags(map[string]interface{}{"env": "staging", "app": "web"})
+	expected := map[string]interface{}{"team": "infra", "env": "staging", "app": "web"}
+	if !reflect.DeepEqual(tags, expected) {
+		t.Errorf("Expected %v, got %v", expected, tags)
+	}
+}
+
+func TestIgnoreDefaultTags(t *testing.T) {
+	client := testTagsClient(map[string]interface{}{"team": "infra", "env": "prod"})
+	tagsAll := map[string]interface{}{"team": "infra", "env": "staging", "app": "web", "owner": "ops"}
+	tags := client.ignoreDefaultTags(tagsAll, map[string]interface{}{"app": "web", "owner": "ops"})
+	expected := map[string]interface{}{"env": "staging", "app": "web", "owner": "ops"}
+	if !reflect.DeepEqual(tags, expected) {
+		t.Errorf("Expected %v, got %v", expected, tags)
+	}
+
+	// the default tag declared by resource as well is kept
+	tags = client.ignoreDefaultTags(tagsAll, map[string]interface{}{"team": "infra"})
+	if _, ok := tags["team"]; !ok {
+		t.Errorf("Expected declared tag team to be kept, got %v", tags)
+	}
+}
+
+func TestSetTagsAllAfter(t *testing.T) {
+	client := testTagsClient(map[string]interface{}{"team": "infra"})
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"tags":     tagsSchema(),
+			"tags_all": tagsAllSchema(),
+		},
+	}
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"tags": map[string]interface{}{"app": "web"},
+	})
+	d.SetId("id")
+
+	read := setTagsAllAfter(func(d *schema.ResourceData, meta interface{}) error {
+		// the remote returns all tags of resource
+		return d.Set("tags", map[string]interface{}{"app": "web", "team": "infra"})
+	})
+	if err := read(d, client); err != nil {
+		t.Fatalf("Expected no error, got %s", err)
+	}
+	if tags := d.Get("tags").(map[string]interface{}); !reflect.DeepEqual(tags, map[string]interface{}{"app": "web"}) {
+		t.Errorf("Unexpected tags %v", tags)
+	}
+	if tagsAll := d.Get("tags_all").(map[string]interface{}); len(tagsAll) != 2 {
+		t.Errorf("Unexpected tags_all %v", tagsAll)
+	}
+}
+
+func TestRemoveIgnoredTags(t *testing.T) {
+	client := &KsyunClient{config: &Config{IgnoreTags: &IgnoreTagsConfig{
+		Keys:        []string{"owner"},
+		KeyPrefixes: []string{"ksc:"},
+	}}}
+	tags := client.removeIgnoredTags(map[string]interface{}{"owner": "ops", "ksc:scanner": "on", "app": "web"})
+	expected := map[string]interface{}{"app": "web"}
+	if !reflect.DeepEqual(tags, expected) {
+		t.Errorf("Expected %v, got %v", expected, tags)
+	}
+
+	// nothing is ignored without ignore_tags
+	client = testTagsClient(nil)
+	if client.isIgnoredTag("owner") || client.hasIgnoreTags() {
+		t.Errorf("Expected no tag to be ignored")
+	}
+}
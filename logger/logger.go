@@ -20,7 +20,7 @@ func Debug(format string, action string, req interface{}, v ...interface{}) {
 		file = file[start+1:]
 	}
 	message := fmt.Sprintf("[DEBUG] {%v:%v}", file, line)
-	log.Printf(message+format, action, req, v)
+	log.Printf(message+format, action, redactValue(req), v)
 }
 func DebugInfo(format string, info interface{}) {
 	_, file, line, _ := runtime.Caller(skip)
@@ -0,0 +1,218 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+// TraceEnv is the environment variable holding the file path of api trace,
+// the api trace is disabled if it's empty.
+const TraceEnv = "KSYUN_API_TRACE"
+
+// RedactedValue replaces the values of sensitive fields
+const RedactedValue = "******"
+
+// TraceRecord is the record of one api call, including all of its retries.
+type TraceRecord struct {
+	Time       string                 `json:"time"`
+	Service    string                 `json:"service"`
+	Action     string                 `json:"action"`
+	Region     string                 `json:"region,omitempty"`
+	RequestId  string                 `json:"request_id,omitempty"`
+	LatencyMs  int64                  `json:"latency_ms"`
+	RetryCount int                    `json:"retry_count"`
+	StatusCode int                    `json:"status_code,omitempty"`
+	Status     string                 `json:"status"`
+	ErrorCode  string                 `json:"error_code,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+	Request    map[string]interface{} `json:"request,omitempty"`
+}
+
+// Tracer writes the trace records as json lines
+type Tracer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewTracer returns a tracer writing to w
+func NewTracer(w io.Writer) *Tracer {
+	return &Tracer{w: w}
+}
+
+var (
+	tracersMu sync.Mutex
+	tracers   = make(map[string]*Tracer)
+)
+
+// OpenTracer returns the tracer appending to the file at path, the tracer is shared by
+// all the callers with the same path.
+func OpenTracer(path string) (*Tracer, error) {
+	tracersMu.Lock()
+	defer tracersMu.Unlock()
+
+	if t, ok := tracers[path]; ok {
+		return t, nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open api trace file %s: %s", path, err)
+	}
+	t := NewTracer(f)
+	tracers[path] = t
+	return t, nil
+}
+
+// Trace writes the record as a json line
+func (t *Tracer) Trace(record *TraceRecord) {
+	b, err := json.Marshal(record)
+	if err != nil {
+		log.Printf("[WARN] failed to marshal api trace of %s: %s", record.Action, err)
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, err = t.w.Write(append(b, '\n')); err != nil {
+		log.Printf("[WARN] failed to write api trace of %s: %s", record.Action, err)
+	}
+}
+
+// Handler returns a handler tracing the request when it's completed, it should be
+// registered to the Complete handlers so that it's called once per api call.
+func (t *Tracer) Handler() request.NamedHandler {
+	return request.NamedHandler{
+		Name: "ksyun.TraceHandler",
+		Fn: func(r *request.Request) {
+			t.Trace(NewTraceRecord(r))
+		},
+	}
+}
+
+// NewTraceRecord builds the trace record of the completed request
+func NewTraceRecord(r *request.Request) *TraceRecord {
+	record := &TraceRecord{
+		Time:       r.Time.UTC().Format(time.RFC3339Nano),
+		Service:    r.ClientInfo.ServiceName,
+		RetryCount: r.RetryCount,
+		RequestId:  r.RequestID,
+		Status:     "success",
+	}
+	if r.Operation != nil {
+		record.Action = r.Operation.Name
+	}
+	if r.Config.Region != nil {
+		record.Region = *r.Config.Region
+	}
+	if !r.Time.IsZero() {
+		record.LatencyMs = int64(time.Since(r.Time) / time.Millisecond)
+	}
+	if r.HTTPResponse != nil {
+		record.StatusCode = r.HTTPResponse.StatusCode
+		if record.RequestId == "" {
+			record.RequestId = r.HTTPResponse.Header.Get("X-Ksc-Request-Id")
+		}
+	}
+	if record.RequestId == "" {
+		record.RequestId = requestIdOf(r.Data)
+	}
+	if params, ok := r.Params.(*map[string]interface{}); ok && params != nil {
+		record.Request = Redact(*params)
+	}
+	if r.Error != nil {
+		record.Status = "error"
+		record.Error = r.Error.Error()
+		if e, ok := r.Error.(awserr.Error); ok {
+			record.ErrorCode = e.Code()
+		}
+	}
+	return record
+}
+
+func requestIdOf(data interface{}) string {
+	resp, ok := data.(*map[string]interface{})
+	if !ok || resp == nil {
+		return ""
+	}
+	for _, k := range []string{"RequestId", "RequestID", "requestId"} {
+		if id, ok := (*resp)[k].(string); ok {
+			return id
+		}
+	}
+	return ""
+}
+
+var (
+	sensitiveMu     sync.RWMutex
+	sensitiveFields = make(map[string]bool)
+)
+
+// RegisterSensitiveFields registers the fields whose values are redacted in logs, the names are
+// matched case-insensitively and without underscores, so the schema field instance_password
+// matches the api parameter InstancePassword.
+func RegisterSensitiveFields(names ...string) {
+	sensitiveMu.Lock()
+	defer sensitiveMu.Unlock()
+	for _, name := range names {
+		sensitiveFields[normalizeField(name)] = true
+	}
+}
+
+// IsSensitiveField returns whether the value of field should be redacted, the field may be
+// a flattened parameter such as DataDisk.1.Password.
+func IsSensitiveField(field string) bool {
+	parts := strings.Split(field, ".")
+	for i := len(parts) - 1; i >= 0; i-- {
+		if _, err := strconv.Atoi(parts[i]); err == nil {
+			continue
+		}
+		sensitiveMu.RLock()
+		defer sensitiveMu.RUnlock()
+		return sensitiveFields[normalizeField(parts[i])]
+	}
+	return false
+}
+
+func normalizeField(name string) string {
+	return strings.ToLower(strings.Replace(name, "_", "", -1))
+}
+
+// Redact returns a copy of params with the values of sensitive fields redacted
+func Redact(params map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		if IsSensitiveField(k) {
+			result[k] = RedactedValue
+			continue
+		}
+		result[k] = redactValue(v)
+	}
+	return result
+}
+
+func redactValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		return Redact(value)
+	case *map[string]interface{}:
+		if value == nil {
+			return value
+		}
+		return Redact(*value)
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, item := range value {
+			result[i] = redactValue(item)
+		}
+		return result
+	}
+	return v
+}
@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/client/metadata"
+	"github.com/aws/aws-sdk-go/aws/request"
+)
+
+func TestRedact(t *testing.T) {
+	RegisterSensitiveFields("instance_password", "master_user_password", "password")
+
+	params := map[string]interface{}{
+		"InstancePassword":       "secret",
+		"DataDisk.1.Password":    "secret",
+		"Instance.1.Password":    "secret",
+		"MasterUserPassword":     "secret",
+		"InstanceName":           "web",
+		"Nested":                 map[string]interface{}{"master_user_password": "secret"},
+		"InstancePasswordPolicy": "strong",
+	}
+	redacted := Redact(params)
+	for _, k := range []string{"InstancePassword", "DataDisk.1.Password", "Instance.1.Password", "MasterUserPassword"} {
+		if redacted[k] != RedactedValue {
+			t.Errorf("Expected %s to be redacted, got %v", k, redacted[k])
+		}
+	}
+	if redacted["InstanceName"] != "web" || redacted["InstancePasswordPolicy"] != "strong" {
+		t.Errorf("Unexpected redacted params %v", redacted)
+	}
+	if nested := redacted["Nested"].(map[string]interface{}); nested["master_user_password"] != RedactedValue {
+		t.Errorf("Expected nested field to be redacted, got %v", nested)
+	}
+	if params["InstancePassword"] != "secret" {
+		t.Errorf("Expected params not to be modified")
+	}
+}
+
+func TestTracerHandler(t *testing.T) {
+	RegisterSensitiveFields("instance_password")
+
+	params := map[string]interface{}{"InstancePassword": "secret", "ImageId": "img-1"}
+	data := map[string]interface{}{"RequestId": "req-1"}
+	r := request.New(
+		aws.Config{Region: aws.String("cn-beijing-6")},
+		metadata.ClientInfo{ServiceName: "kec"},
+		request.Handlers{},
+		nil,
+		&request.Operation{Name: "RunInstances"},
+		&params,
+		&data,
+	)
+	r.RetryCount = 2
+	r.HTTPResponse = &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}}
+	r.Error = awserr.New("ServiceUnavailable", "try again later", nil)
+
+	var buf bytes.Buffer
+	NewTracer(&buf).Handler().Fn(r)
+
+	var record TraceRecord
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Expected a json record, got %q: %s", buf.String(), err)
+	}
+	if record.Action != "RunInstances" || record.Service != "kec" || record.Region != "cn-beijing-6" {
+		t.Errorf("Unexpected record %+v", record)
+	}
+	if record.RequestId != "req-1" || record.RetryCount != 2 || record.StatusCode != 503 {
+		t.Errorf("Unexpected record %+v", record)
+	}
+	if record.Status != "error" || record.ErrorCode != "ServiceUnavailable" {
+		t.Errorf("Unexpected record %+v", record)
+	}
+	if record.Request["InstancePassword"] != RedactedValue || record.Request["ImageId"] != "img-1" {
+		t.Errorf("Unexpected request %v", record.Request)
+	}
+	if bytes.Contains(buf.Bytes(), []byte("secret")) {
+		t.Errorf("Expected the password not to be traced, got %s", buf.String())
+	}
+}